@@ -0,0 +1,280 @@
+// Package client is a Go client for the DePIN Compatibility API.
+//
+//	c, err := client.New("http://localhost:8080", client.Options{APIKey: "..."})
+//	if err != nil {
+//		return err
+//	}
+//	result, err := c.Predict(ctx, client.PredictionRequest{
+//		System: client.SystemSpec{CPUCores: 8, RAMGB: 16, StorageGB: 512, HasSSD: true, NetworkMbps: 100, OS: "Linux"},
+//	})
+//
+// Requests rejected with 429, 502, 503 or 504 are retried with exponential
+// backoff, waiting as long as the server's Retry-After asks. Failed
+// responses are returned as *APIError.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client defaults
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultMaxRetries   = 3
+	DefaultMinBackoff   = 500 * time.Millisecond
+	DefaultMaxBackoff   = 10 * time.Second
+	DefaultMaxRetryWait = 30 * time.Second
+)
+
+// maxErrorBody bounds how much of an error response is read
+const maxErrorBody = 1 << 20
+
+// Options configures a Client. Zero values use the defaults.
+type Options struct {
+	APIKey     string       // Sent as X-API-Key
+	HTTPClient *http.Client // Defaults to a client with DefaultTimeout
+	UserAgent  string
+
+	MaxRetries   int           // Retries after the first attempt; negative disables retries
+	MinBackoff   time.Duration // Wait before the first retry, doubled for each further retry
+	MaxBackoff   time.Duration // Longest wait between retries without Retry-After
+	MaxRetryWait time.Duration // A longer Retry-After, e.g. for an exhausted daily quota, is returned as an error instead
+}
+
+// Client calls the DePIN Compatibility API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	opts    Options
+}
+
+// New creates a client for the API at baseURL, e.g. "http://localhost:8080"
+func New(baseURL string, opts Options) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = "api-predict-go-client/1.0"
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultMinBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.MaxRetryWait <= 0 {
+		opts.MaxRetryWait = DefaultMaxRetryWait
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		opts:    opts,
+	}, nil
+}
+
+// APIError is a response with a non-2xx status
+type APIError struct {
+	StatusCode int
+	RetryAfter time.Duration // From the Retry-After header, when set
+	Response   ErrorResponse // Decoded body; Error holds the status text when the body was not JSON
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("api-predict: %d %s", e.StatusCode, e.Response.Error)
+	if e.Response.Message != "" {
+		message += ": " + e.Response.Message
+	}
+	if e.Response.RequestID != "" {
+		message += " (request " + e.Response.RequestID + ")"
+	}
+	return message
+}
+
+// Predict predicts which projects a system can run. Requests with Persist
+// set are not retried after network errors, as the prediction may already
+// have been stored.
+func (c *Client) Predict(ctx context.Context, request PredictionRequest) (*PredictionResponse, error) {
+	var response PredictionResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/predict", nil, request, !request.Persist, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Projects lists the projects of the selected dataset version
+func (c *Client) Projects(ctx context.Context, dataset DatasetQuery) (*ProjectsResponse, error) {
+	query := url.Values{}
+	if dataset.Version != 0 {
+		query.Set("dataset_version", strconv.Itoa(dataset.Version))
+	}
+	if dataset.AsOf != "" {
+		query.Set("as_of", dataset.AsOf)
+	}
+
+	var response ProjectsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/projects", query, nil, true, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Health reports service health
+func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
+	var response HealthResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/health", nil, nil, true, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Metrics returns service and dataset statistics
+func (c *Client) Metrics(ctx context.Context) (*MetricsResponse, error) {
+	var response MetricsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/metrics/json", nil, nil, true, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// do sends a request, retrying as allowed, and decodes the JSON response
+// into result. Network errors are only retried for idempotent requests.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, idempotent bool, result interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("api-predict: failed to encode request: %w", err)
+		}
+	}
+
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, method, target, payload, result)
+		if err == nil {
+			return nil
+		}
+
+		wait, ok := c.retryDelay(ctx, err, attempt, idempotent)
+		if !ok {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes one attempt
+func (c *Client) send(ctx context.Context, method, target string, payload []byte, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return fmt.Errorf("api-predict: %w", err)
+	}
+
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", c.opts.UserAgent)
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.opts.APIKey != "" {
+		request.Header.Set("X-API-Key", c.opts.APIKey)
+	}
+
+	response, err := c.opts.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("api-predict: %s %s: %w", method, request.URL.Path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		apiErr := &APIError{
+			StatusCode: response.StatusCode,
+			RetryAfter: parseRetryAfter(response.Header.Get("Retry-After"), time.Now()),
+		}
+		data, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+		if json.Unmarshal(data, &apiErr.Response) != nil || apiErr.Response.Error == "" {
+			apiErr.Response.Error = http.StatusText(response.StatusCode)
+		}
+		return apiErr
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return fmt.Errorf("api-predict: failed to decode %s %s response: %w", method, request.URL.Path, err)
+	}
+	return nil
+}
+
+// retryDelay decides whether a failed attempt is retried and how long to
+// wait first: as long as Retry-After asks, otherwise an exponential backoff
+// with jitter
+func (c *Client) retryDelay(ctx context.Context, err error, attempt int, idempotent bool) (time.Duration, bool) {
+	if attempt >= c.opts.MaxRetries || ctx.Err() != nil {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		default:
+			return 0, false
+		}
+		if apiErr.RetryAfter > 0 {
+			return apiErr.RetryAfter, apiErr.RetryAfter <= c.opts.MaxRetryWait
+		}
+	} else if !idempotent {
+		return 0, false
+	}
+
+	backoff := c.opts.MaxBackoff
+	if attempt < 20 {
+		backoff = min(c.opts.MinBackoff<<attempt, backoff)
+	}
+	return backoff/2 + rand.N(backoff/2+1), true
+}
+
+// parseRetryAfter reads a Retry-After value in seconds or as an HTTP date,
+// returning zero when it is missing or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,322 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/api"
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/graphqlapi"
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+	"github.com/simoncrean/api-predict/internal/server"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+const testAPIKey = "k-test"
+
+// testAPI is the real router behind an httptest server
+type testAPI struct {
+	server   *httptest.Server
+	requests atomic.Int32
+}
+
+// newTestAPI serves the repository dataset with two versions, the second
+// holding only the first three projects. predictLimit limits /predict.
+func newTestAPI(t *testing.T, predictLimit ratelimit.Limit) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	projects, _, err := storage.NewCSVSource(filepath.Join("..", "data", "depin_specs.csv")).Load()
+	if err != nil {
+		t.Fatalf("load dataset: %v", err)
+	}
+	compatibilityService := service.NewCompatibilityService(projects)
+	if _, _, err := compatibilityService.SetProjects(projects[:3], service.DatasetSourceAdmin); err != nil {
+		t.Fatalf("set projects: %v", err)
+	}
+
+	store, err := storage.OpenBolt(filepath.Join(t.TempDir(), "api.db"))
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	keyStore, err := auth.NewMemoryKeyStore([]auth.APIKey{
+		{Name: "test", Key: testAPIKey, Scopes: []string{auth.ScopePredict, auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatalf("key store: %v", err)
+	}
+
+	limits := models.DefaultSpecLimits()
+	appMetrics := metrics.New()
+	readiness := health.NewReadiness()
+	readiness.DatasetLoaded(len(projects), time.Now())
+	usage := auth.NewUsageTracker()
+
+	rateLimiters := make(map[string]*ratelimit.Limiter)
+	for _, group := range server.RateLimitGroups {
+		limit := ratelimit.Limit{Rate: 1000, Burst: 1000}
+		if group == server.RateLimitPredict {
+			limit = predictLimit
+		}
+		rateLimiters[group] = ratelimit.NewLimiter(rate.Limit(limit.Rate), limit.Burst, 100, time.Hour)
+	}
+
+	schema, err := graphqlapi.NewSchema(compatibilityService, appMetrics, limits)
+	if err != nil {
+		t.Fatalf("graphql schema: %v", err)
+	}
+
+	router, err := server.NewRouter(server.Config{SpecLimits: limits}, server.Components{
+		Handlers:        api.NewHandlers(compatibilityService, appMetrics, readiness, limits, service.NewPredictionArchive(store, 0)),
+		AdminHandlers:   api.NewAdminHandlers(keyStore, usage, compatibilityService, nil, service.NewProjectEditor(compatibilityService, store, nil), store),
+		ProfileHandlers: api.NewProfileHandlers(service.NewProfileService(compatibilityService, store, false), store, appMetrics, limits),
+		Metrics:         appMetrics,
+		KeyStore:        keyStore,
+		KeyUsage:        usage,
+		RateLimiters:    rateLimiters,
+		EventBroker:     events.NewBroker(events.DefaultHistorySize),
+		GraphQLSchema:   schema,
+	})
+	if err != nil {
+		t.Fatalf("router: %v", err)
+	}
+
+	testAPI := &testAPI{}
+	testAPI.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testAPI.requests.Add(1)
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(testAPI.server.Close)
+	return testAPI
+}
+
+// client creates a client for the test server with fast retries
+func (a *testAPI) client(t *testing.T, opts Options) *Client {
+	t.Helper()
+	if opts.MinBackoff == 0 {
+		opts.MinBackoff = time.Millisecond
+	}
+	c, err := New(a.server.URL, opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+var testSystem = SystemSpec{CPUCores: 8, RAMGB: 16, StorageGB: 512, HasSSD: true, NetworkMbps: 100, OS: "Linux"}
+
+func TestPredict(t *testing.T) {
+	c := newTestAPI(t, ratelimit.Limit{Rate: 1000, Burst: 1000}).client(t, Options{})
+
+	result, err := c.Predict(context.Background(), PredictionRequest{System: testSystem})
+	if err != nil {
+		t.Fatalf("Predict: %v", err)
+	}
+	if result.Summary.TotalProjects != 3 {
+		t.Errorf("TotalProjects = %d, want 3", result.Summary.TotalProjects)
+	}
+	if got := len(result.CompatibleProjects) + len(result.IncompatibleProjects); got != 3 {
+		t.Errorf("got %d results, want 3", got)
+	}
+	if result.DatasetVersion != 2 || result.RequestID == "" {
+		t.Errorf("DatasetVersion = %d, RequestID = %q", result.DatasetVersion, result.RequestID)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	c := newTestAPI(t, ratelimit.Limit{Rate: 1000, Burst: 1000}).client(t, Options{})
+	ctx := context.Background()
+
+	live, err := c.Projects(ctx, DatasetQuery{})
+	if err != nil {
+		t.Fatalf("Projects: %v", err)
+	}
+	if live.DatasetVersion != 2 || live.Total != 3 || len(live.Projects) != 3 {
+		t.Errorf("live: version %d, total %d, %d projects", live.DatasetVersion, live.Total, len(live.Projects))
+	}
+
+	first, err := c.Projects(ctx, DatasetQuery{Version: 1})
+	if err != nil {
+		t.Fatalf("Projects(version 1): %v", err)
+	}
+	if first.DatasetVersion != 1 || first.Total <= 3 {
+		t.Errorf("version 1: version %d, total %d", first.DatasetVersion, first.Total)
+	}
+
+	_, err = c.Projects(ctx, DatasetQuery{Version: 99})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Projects(version 99) error = %v, want a 404 APIError", err)
+	}
+}
+
+func TestHealthAndMetrics(t *testing.T) {
+	c := newTestAPI(t, ratelimit.Limit{Rate: 1000, Burst: 1000}).client(t, Options{})
+	ctx := context.Background()
+
+	health, err := c.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if health.Status != "healthy" || health.ProjectsLoaded != 3 {
+		t.Errorf("Health = %+v", health)
+	}
+
+	stats, err := c.Metrics(ctx)
+	if err != nil {
+		t.Fatalf("Metrics: %v", err)
+	}
+	if stats.ProjectsLoadedTotal != 3 || stats.DatasetVersion != 2 || stats.ServiceInfo.Name == "" {
+		t.Errorf("Metrics = %+v", stats)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	testAPI := newTestAPI(t, ratelimit.Limit{Rate: 1000, Burst: 1000})
+	c := testAPI.client(t, Options{})
+
+	system := testSystem
+	system.CPUCores = 0
+	_, err := c.Predict(context.Background(), PredictionRequest{System: system})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Response.RequestID == "" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if len(apiErr.Response.Details) == 0 || apiErr.Response.Details[0].Field != "system.cpu_cores" {
+		t.Errorf("Details = %+v, want a system.cpu_cores error", apiErr.Response.Details)
+	}
+	if got := testAPI.requests.Load(); got != 1 {
+		t.Errorf("sent %d requests, a 400 must not be retried", got)
+	}
+}
+
+func TestInvalidAPIKey(t *testing.T) {
+	c := newTestAPI(t, ratelimit.Limit{Rate: 1000, Burst: 1000}).client(t, Options{APIKey: "wrong"})
+
+	_, err := c.Health(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("error = %v, want a 401 APIError", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	testAPI := newTestAPI(t, ratelimit.Limit{Rate: 1, Burst: 1})
+	c := testAPI.client(t, Options{APIKey: testAPIKey})
+	ctx := context.Background()
+
+	if _, err := c.Predict(ctx, PredictionRequest{System: testSystem}); err != nil {
+		t.Fatalf("first Predict: %v", err)
+	}
+
+	// The bucket is empty, so the server answers 429 with Retry-After: 1
+	// and the client waits that long before its retry succeeds
+	started := time.Now()
+	if _, err := c.Predict(ctx, PredictionRequest{System: testSystem}); err != nil {
+		t.Fatalf("second Predict: %v", err)
+	}
+	if waited := time.Since(started); waited < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", waited)
+	}
+	if got := testAPI.requests.Load(); got != 3 {
+		t.Errorf("sent %d requests, want 3", got)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	testAPI := newTestAPI(t, ratelimit.Limit{Rate: 1, Burst: 1})
+	c := testAPI.client(t, Options{MaxRetryWait: 500 * time.Millisecond})
+	ctx := context.Background()
+
+	if _, err := c.Predict(ctx, PredictionRequest{System: testSystem}); err != nil {
+		t.Fatalf("first Predict: %v", err)
+	}
+
+	_, err := c.Predict(ctx, PredictionRequest{System: testSystem})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != time.Second || apiErr.Response.RetryAfter != 1 {
+		t.Errorf("APIError = %+v, want 429 with a 1s Retry-After", apiErr)
+	}
+	if got := testAPI.requests.Load(); got != 2 {
+		t.Errorf("sent %d requests, a Retry-After above MaxRetryWait must not be retried", got)
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	var requests atomic.Int32
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	c, err := New(unavailable.URL, Options{MaxRetries: 2, MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = c.Health(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("error = %v, want a 503 APIError", err)
+	}
+	if apiErr.Response.Error != http.StatusText(http.StatusServiceUnavailable) {
+		t.Errorf("Response.Error = %q, want the status text for a non-JSON body", apiErr.Response.Error)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("sent %d requests, want 3", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-3", 0},
+		{"soon", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNewRejectsInvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://example.com", "http://"} {
+		if _, err := New(baseURL, Options{}); err == nil {
+			t.Errorf("New(%q) succeeded, want an error", baseURL)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import "github.com/simoncrean/api-predict/internal/models"
+
+// Request and response types, shared with the server so they always match
+// its JSON
+type (
+	SystemSpec          = models.SystemSpec
+	GPU                 = models.GPU
+	StorageDevice       = models.StorageDevice
+	PredictionRequest   = models.PredictionRequest
+	PredictionResponse  = models.PredictionResponse
+	PredictionSummary   = models.PredictionSummary
+	CompatibilityResult = models.CompatibilityResult
+	FieldError          = models.FieldError
+	DePINProject        = models.DePINProject
+	ProjectsResponse    = models.ProjectsResponse
+	ProjectSummary      = models.ProjectSummary
+	HealthResponse      = models.HealthResponse
+	MetricsResponse     = models.MetricsResponse
+	ServiceInfo         = models.ServiceInfo
+	ErrorResponse       = models.ErrorResponse
+)
+
+// Storage device types
+const (
+	StorageNVMe = models.StorageNVMe
+	StorageSSD  = models.StorageSSD
+	StorageHDD  = models.StorageHDD
+)
+
+// DatasetQuery selects a past dataset version by number or by the time it
+// was live. The zero value selects the live version.
+type DatasetQuery struct {
+	Version int
+	AsOf    string // RFC 3339 time or YYYY-MM-DD date
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/data"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// runData implements the data subcommands for checking dataset changes
+func runData(args []string, config *Config) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: data lint [flags] [file.csv] | data diff [flags] old.csv new.csv | data impact [flags] old.csv new.csv")
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "lint":
+		return runDataLint(args[1:], config)
+	case "diff":
+		return runDataDiff(args[1:], config)
+	case "impact":
+		return runDataImpact(args[1:], config)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown data command %q; available commands: lint, diff, impact\n", args[0])
+		return exitUsage
+	}
+}
+
+// runDataLint loads a dataset strictly and checks semantic rules. It exits
+// non-zero when the file fails to load or any error-severity issue is found;
+// warnings alone do not fail.
+func runDataLint(args []string, config *Config) int {
+	flags := flag.NewFlagSet("data lint", flag.ContinueOnError)
+	maxAgeDays := flags.Int("max-age-days", int(data.DefaultMaxSpecAge.Hours()/24), "Days after which last_updated is stale")
+	format := flags.String("format", formatTable, "Output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	path := config.DataPath
+	if flags.NArg() > 0 {
+		path = flags.Arg(0)
+	}
+
+	projects, err := data.NewStrictLoader(path).LoadDePINSpecs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, strings.ReplaceAll(err.Error(), "\n", "\n  "))
+		return exitError
+	}
+
+	issues := data.Lint(projects, data.LintOptions{MaxSpecAge: time.Duration(*maxAgeDays) * 24 * time.Hour})
+
+	if *format == formatJSON {
+		if err := writeJSON(os.Stdout, issues); err != nil {
+			fmt.Fprintf(os.Stderr, "data lint: %v\n", err)
+			return exitError
+		}
+	} else {
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+	}
+
+	errorCount := 0
+	for _, issue := range issues {
+		if issue.Severity == data.SeverityError {
+			errorCount++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%s: %d projects, %d errors, %d warnings\n", path, len(projects), errorCount, len(issues)-errorCount)
+
+	if errorCount > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// datasetComparison is the data diff report
+type datasetComparison struct {
+	Diff   data.DatasetDiff     `json:"diff"`
+	Impact *models.ImpactReport `json:"impact"`
+}
+
+// runDataDiff compares two datasets field by field and shows how
+// compatibility changes for the reference systems
+func runDataDiff(args []string, config *Config) int {
+	flags := flag.NewFlagSet("data diff", flag.ContinueOnError)
+	profilesPath := flags.String("profiles", config.ReferenceSystemsPath, "Reference systems JSON file")
+	format := flags.String("format", formatTable, "Output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: data diff [flags] old.csv new.csv")
+		return exitUsage
+	}
+
+	old, updated, profiles, err := loadComparisonInputs(flags.Arg(0), flags.Arg(1), *profilesPath, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "data diff: %v\n", err)
+		return exitError
+	}
+
+	report := datasetComparison{Diff: data.Diff(old, updated)}
+	if report.Impact, err = service.CompareDatasets(profiles, old, updated); err != nil {
+		fmt.Fprintf(os.Stderr, "data diff: %v\n", err)
+		return exitError
+	}
+
+	if *format == formatJSON {
+		err = writeJSON(os.Stdout, report)
+	} else {
+		writeComparison(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "data diff: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// writeComparison writes a data diff report as plain text
+func writeComparison(w io.Writer, report datasetComparison) {
+	if report.Diff.Empty() {
+		fmt.Fprintln(w, "No project changes")
+	}
+	for _, name := range report.Diff.Added {
+		fmt.Fprintf(w, "+ %s\n", name)
+	}
+	for _, name := range report.Diff.Removed {
+		fmt.Fprintf(w, "- %s\n", name)
+	}
+	for _, change := range report.Diff.Changed {
+		fmt.Fprintf(w, "~ %s\n", change.Name)
+		for _, field := range change.Fields {
+			fmt.Fprintf(w, "    %s: %v -> %v\n", field.Field, field.Old, field.New)
+		}
+	}
+
+	fmt.Fprintln(w, "\nReference systems:")
+	for _, impact := range report.Impact.Profiles {
+		if len(impact.Gained) == 0 && len(impact.Lost) == 0 {
+			fmt.Fprintf(w, "  %s: no change\n", impact.Profile)
+			continue
+		}
+		fmt.Fprintf(w, "  %s:", impact.Profile)
+		if len(impact.Gained) > 0 {
+			fmt.Fprintf(w, " gains %s", strings.Join(impact.Gained, ", "))
+		}
+		if len(impact.Lost) > 0 {
+			if len(impact.Gained) > 0 {
+				fmt.Fprint(w, ";")
+			}
+			fmt.Fprintf(w, " loses %s", strings.Join(impact.Lost, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// runDataImpact reports how a dataset change moves each reference system's
+// compatible projects and scores
+func runDataImpact(args []string, config *Config) int {
+	flags := flag.NewFlagSet("data impact", flag.ContinueOnError)
+	profilesPath := flags.String("profiles", config.ReferenceSystemsPath, "Reference systems JSON file")
+	format := flags.String("format", formatTable, "Output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: data impact [flags] old.csv new.csv")
+		return exitUsage
+	}
+
+	old, updated, profiles, err := loadComparisonInputs(flags.Arg(0), flags.Arg(1), *profilesPath, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "data impact: %v\n", err)
+		return exitError
+	}
+
+	report, err := service.CompareDatasets(profiles, old, updated)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "data impact: %v\n", err)
+		return exitError
+	}
+
+	if *format == formatJSON {
+		err = writeJSON(os.Stdout, report)
+	} else {
+		err = writeImpact(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "data impact: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// loadComparisonInputs loads two datasets and the reference systems
+func loadComparisonInputs(oldPath, newPath, profilesPath string, config *Config) (old, updated []models.DePINProject, profiles []models.ReferenceSystem, err error) {
+	old, errOld := data.NewLoader(oldPath).LoadDePINSpecs()
+	updated, errNew := data.NewLoader(newPath).LoadDePINSpecs()
+	if err := errors.Join(errOld, errNew); err != nil {
+		return nil, nil, nil, err
+	}
+
+	profiles, err = data.LoadReferenceSystems(profilesPath, config.SpecLimits)
+	return old, updated, profiles, err
+}
+
+// writeImpact writes an impact report as an aligned table followed by the
+// score moves of each profile
+func writeImpact(w io.Writer, report *models.ImpactReport) error {
+	fmt.Fprintf(w, "%d of %d reference systems affected: %d projects gained, %d lost\n\n",
+		report.ProfilesAffected, len(report.Profiles), report.ProjectsGained, report.ProjectsLost)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "PROFILE\tCOMPATIBLE\tAVG SCORE\tGAINED\tLOST")
+	for _, impact := range report.Profiles {
+		fmt.Fprintf(table, "%s\t%d -> %d\t%.2f -> %.2f\t%s\t%s\n",
+			impact.Profile, impact.CompatibleBefore, impact.CompatibleAfter,
+			impact.AverageScoreBefore, impact.AverageScoreAfter,
+			strings.Join(impact.Gained, ", "), strings.Join(impact.Lost, ", "))
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	for _, impact := range report.Profiles {
+		if len(impact.ScoreChanges) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s score changes:\n", impact.Profile)
+		for _, change := range impact.ScoreChanges {
+			fmt.Fprintf(w, "  %s: %.2f -> %.2f (%+.2f)\n", change.Project, change.Before, change.After, change.Delta)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+// csvChecksumKey is the store meta key holding the checksum of the last
+// imported CSV
+const csvChecksumKey = "csv_checksum"
+
+// errAdminEdits refuses a CSV import that would discard live admin edits
+var errAdminEdits = errors.New("the live dataset has admin edits the changed CSV would discard; " +
+	"copy them into the CSV or set CSV_IMPORT_OVERWRITE_EDITS=true")
+
+// openDataset restores the dataset versions kept in the store and imports the
+// CSV as a new version when its content changed since the last import, so
+// admin edits survive restarts while the CSV is unchanged. The CSV is only
+// required when the store holds no dataset yet.
+func openDataset(source *storage.CSVSource, store storage.Store, retention int, overwriteEdits bool) (*service.CompatibilityService, error) {
+	snapshots, err := store.DatasetVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) == 0 {
+		projects, checksum, err := source.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		compatibilityService := service.NewCompatibilityService(projects)
+		if err := store.SaveDatasetVersion(compatibilityService.CurrentSnapshot()); err != nil {
+			return nil, fmt.Errorf("failed to store initial dataset: %w", err)
+		}
+		if err := store.SetMeta(csvChecksumKey, checksum); err != nil {
+			return nil, err
+		}
+		compatibilityService.SetVersionStore(store)
+		return compatibilityService, compatibilityService.SetVersionRetention(retention)
+	}
+
+	compatibilityService := service.RestoreCompatibilityService(snapshots)
+	compatibilityService.SetVersionStore(store)
+	if err := compatibilityService.SetVersionRetention(retention); err != nil {
+		return nil, err
+	}
+	slog.Info("Restored dataset versions", "versions", len(snapshots), "current", compatibilityService.CurrentDatasetVersion().Version)
+
+	if _, err := importCSV(source, store, compatibilityService, service.DatasetSourceImport, overwriteEdits); err != nil {
+		slog.Warn("CSV import failed, serving the stored dataset", "path", source.Path(), "error", err)
+	}
+	return compatibilityService, nil
+}
+
+// importCSV makes the CSV's projects the live dataset unless the file is
+// unchanged since its last import, and reports whether it was imported. While
+// the live version is an admin edit, a CSV that differs from it is refused
+// with errAdminEdits unless overwriteEdits is set, and stays pending until it
+// matches the edited dataset or is allowed.
+func importCSV(source *storage.CSVSource, store storage.Store, compatibilityService *service.CompatibilityService, origin string, overwriteEdits bool) (bool, error) {
+	projects, checksum, err := source.Load()
+	if err != nil {
+		return false, err
+	}
+
+	last, err := store.Meta(csvChecksumKey)
+	if err != nil {
+		return false, err
+	}
+	if checksum == last {
+		return false, nil
+	}
+
+	live := compatibilityService.CurrentDatasetVersion()
+	if live.Source == service.DatasetSourceAdmin && !slices.Equal(projects, compatibilityService.GetProjects()) {
+		if !overwriteEdits {
+			return false, errAdminEdits
+		}
+		slog.Warn("Importing the changed CSV over admin edits", "path", source.Path(), "replaced_version", live.Version)
+	}
+
+	if _, _, err := compatibilityService.SetProjects(projects, origin); err != nil {
+		return false, err
+	}
+	return true, store.SetMeta(csvChecksumKey, checksum)
+}
+
+// reloadDataset imports the CSV again if it changed. On failure the previous
+// dataset keeps serving and readiness reports the error; an import refused to
+// keep admin edits is only logged, as the service is still healthy.
+func reloadDataset(source *storage.CSVSource, store storage.Store, compatibilityService *service.CompatibilityService, overwriteEdits bool, appMetrics *metrics.Metrics, readiness *health.Readiness) {
+	imported, err := importCSV(source, store, compatibilityService, service.DatasetSourceReload, overwriteEdits)
+	if errors.Is(err, errAdminEdits) {
+		slog.Warn("Dataset reload refused, keeping admin edits", "path", source.Path(), "error", err)
+		return
+	}
+	if err != nil {
+		slog.Error("Dataset reload failed, keeping previous dataset", "error", err)
+		readiness.ReloadFailed(err, time.Now())
+		return
+	}
+
+	projects := compatibilityService.GetProjects()
+	loadedAt := time.Now()
+	appMetrics.SetDataset(len(projects), loadedAt)
+	readiness.DatasetLoaded(len(projects), loadedAt)
+
+	if imported {
+		slog.Info("Reloaded DePIN projects", "count", len(projects), "version", compatibilityService.CurrentDatasetVersion().Version)
+	} else {
+		slog.Info("CSV unchanged since its last import, keeping the current dataset", "path", source.Path())
+	}
+}
+
+// loadKeyStore loads API keys. A configured key file replaces the stored
+// keys; without one, the keys stored by a previous run are used.
+func loadKeyStore(path string, store storage.KeyStore) (*auth.MemoryKeyStore, error) {
+	if path == "" {
+		keys, err := store.APIKeys()
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) > 0 {
+			slog.Info("Loaded stored API keys", "count", len(keys))
+		}
+		return auth.NewMemoryKeyStore(keys)
+	}
+
+	keys, err := auth.ReadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validating hashes the keys in place, so only hashes are stored
+	keyStore, err := auth.NewMemoryKeyStore(keys)
+	if err != nil {
+		return nil, fmt.Errorf("API key file '%s': %w", path, err)
+	}
+	if err := store.ReplaceAPIKeys(keys); err != nil {
+		return nil, fmt.Errorf("failed to store API keys: %w", err)
+	}
+
+	slog.Info("Loaded API keys", "count", len(keys), "path", path)
+	return keyStore, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+const datasetHeader = "project_name,cpu_cores_min,ram_gb_min,storage_gb_min,network_speed_mbps_min\n"
+
+// openTestDataset opens a dataset from a CSV holding rows, backed by a fresh store
+func openTestDataset(t *testing.T, rows string) (*storage.CSVSource, *storage.BoltStore, *service.CompatibilityService) {
+	t.Helper()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "specs.csv")
+	writeCSV(t, path, rows)
+
+	store, err := storage.OpenBolt(filepath.Join(dir, "api.db"))
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	source := storage.NewCSVSource(path)
+	compatibilityService, err := openDataset(source, store, 10, false)
+	if err != nil {
+		t.Fatalf("openDataset: %v", err)
+	}
+	return source, store, compatibilityService
+}
+
+func writeCSV(t *testing.T, path, rows string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(datasetHeader+rows), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// editProject applies an admin edit the way ProjectEditor does
+func editProject(t *testing.T, compatibilityService *service.CompatibilityService, project models.DePINProject) {
+	t.Helper()
+	projects := append(compatibilityService.GetProjects(), project)
+	if _, _, err := compatibilityService.SetProjects(projects, service.DatasetSourceAdmin); err != nil {
+		t.Fatalf("SetProjects: %v", err)
+	}
+}
+
+func TestImportCSVUnchanged(t *testing.T) {
+	source, store, compatibilityService := openTestDataset(t, "Alpha,2,4,100,20\n")
+
+	imported, err := importCSV(source, store, compatibilityService, service.DatasetSourceReload, false)
+	if err != nil || imported {
+		t.Errorf("importCSV = %v, %v; want nothing imported", imported, err)
+	}
+	if version := compatibilityService.CurrentDatasetVersion(); version.Version != 1 || version.Source != service.DatasetSourceInitial {
+		t.Errorf("live version = %+v", version)
+	}
+}
+
+func TestImportCSVChanged(t *testing.T) {
+	source, store, compatibilityService := openTestDataset(t, "Alpha,2,4,100,20\n")
+	writeCSV(t, source.Path(), "Alpha,4,4,100,20\n")
+
+	imported, err := importCSV(source, store, compatibilityService, service.DatasetSourceReload, false)
+	if err != nil || !imported {
+		t.Fatalf("importCSV = %v, %v; want imported", imported, err)
+	}
+	if version := compatibilityService.CurrentDatasetVersion(); version.Version != 2 || version.Source != service.DatasetSourceReload {
+		t.Errorf("live version = %+v", version)
+	}
+}
+
+func TestImportCSVRefusesToDiscardAdminEdits(t *testing.T) {
+	source, store, compatibilityService := openTestDataset(t, "Alpha,2,4,100,20\n")
+	editProject(t, compatibilityService, models.DePINProject{Name: "Beta", CPUCoresMin: 1})
+	checksum, _ := store.Meta(csvChecksumKey)
+
+	writeCSV(t, source.Path(), "Alpha,4,4,100,20\n")
+	imported, err := importCSV(source, store, compatibilityService, service.DatasetSourceReload, false)
+	if !errors.Is(err, errAdminEdits) || imported {
+		t.Fatalf("importCSV = %v, %v; want errAdminEdits", imported, err)
+	}
+	if version := compatibilityService.CurrentDatasetVersion(); version.Source != service.DatasetSourceAdmin || version.Projects != 2 {
+		t.Errorf("live version = %+v, want the admin edit", version)
+	}
+	// The import stays pending
+	if pending, _ := store.Meta(csvChecksumKey); pending != checksum {
+		t.Error("refused import recorded the new CSV checksum")
+	}
+
+	imported, err = importCSV(source, store, compatibilityService, service.DatasetSourceReload, true)
+	if err != nil || !imported {
+		t.Fatalf("importCSV with overwrite = %v, %v; want imported", imported, err)
+	}
+	if projects := compatibilityService.GetProjects(); len(projects) != 1 || projects[0].CPUCoresMin != 4 {
+		t.Errorf("projects = %+v, want the CSV", projects)
+	}
+}
+
+func TestImportCSVMatchingAdminEdits(t *testing.T) {
+	source, store, compatibilityService := openTestDataset(t, "Alpha,2,4,100,20\n")
+	writeCSV(t, source.Path(), "Alpha,2,4,100,20\nBeta,1,0,0,0\n")
+
+	// Add Beta exactly as the CSV line loads, defaults included
+	projects, _, err := source.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	editProject(t, compatibilityService, projects[1])
+
+	imported, err := importCSV(source, store, compatibilityService, service.DatasetSourceReload, false)
+	if err != nil || !imported {
+		t.Fatalf("importCSV = %v, %v; want the CSV holding the edits imported", imported, err)
+	}
+	// The dataset is unchanged, so the admin version stays live
+	if version := compatibilityService.CurrentDatasetVersion(); version.Version != 2 {
+		t.Errorf("live version = %+v, want the admin edit kept", version)
+	}
+}
+
+func TestOpenDatasetRestoresEdits(t *testing.T) {
+	source, store, compatibilityService := openTestDataset(t, "Alpha,2,4,100,20\n")
+	editProject(t, compatibilityService, models.DePINProject{Name: "Beta", CPUCoresMin: 1})
+
+	// A restart with a changed CSV keeps serving the stored edits
+	writeCSV(t, source.Path(), "Alpha,8,4,100,20\n")
+	restored, err := openDataset(source, store, 10, false)
+	if err != nil {
+		t.Fatalf("openDataset: %v", err)
+	}
+	if version := restored.CurrentDatasetVersion(); version.Version != 2 || version.Source != service.DatasetSourceAdmin {
+		t.Errorf("restored version = %+v, want the admin edit", version)
+	}
+}
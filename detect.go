@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/data"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/sysinfo"
+)
+
+// runDetect implements the detect subcommand: it builds a SystemSpec from
+// procfs/sysfs, prints it and optionally predicts compatibility locally or
+// against a running server. It returns the process exit code.
+func runDetect(args []string, config *Config) int {
+	flags := flag.NewFlagSet("detect", flag.ContinueOnError)
+	root := flags.String("root", "/", "Directory containing proc/ and sys/ to read hardware from")
+	networkMbps := flags.Int("network-mbps", 0, "Network speed in Mbps, overriding the detected link speed")
+	predict := flags.Bool("predict", false, "Predict compatibility locally using DATA_PATH")
+	server := flags.String("server", "", "Predict compatibility against the API server at this base URL")
+	apiKey := flags.String("api-key", os.Getenv("API_KEY"), "API key sent to -server")
+	format := flags.String("format", formatTable, "Prediction output format: table, json or markdown")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if !slices.Contains(outputFormats, *format) {
+		fmt.Fprintf(os.Stderr, "detect: unknown format %q, expected one of %s\n", *format, strings.Join(outputFormats, ", "))
+		return exitUsage
+	}
+
+	detector := sysinfo.NewDetector(*root)
+	detector.NetworkMbps = *networkMbps
+
+	spec, notes, err := detector.Detect()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "detect: %v\n", err)
+		return exitError
+	}
+
+	for _, note := range notes {
+		fmt.Fprintf(os.Stderr, "note: %s\n", note)
+	}
+	if err := writeJSON(os.Stdout, spec); err != nil {
+		fmt.Fprintf(os.Stderr, "detect: %v\n", err)
+		return exitError
+	}
+
+	var result *models.PredictionResponse
+	switch {
+	case *server != "":
+		result, err = predictRemote(*server, *apiKey, spec)
+	case *predict:
+		result, err = predictLocal(config, spec)
+	default:
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "detect: %v\n", err)
+		return exitError
+	}
+
+	fmt.Println()
+	if err := writePrediction(os.Stdout, result, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "detect: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// predictLocal runs a prediction against the dataset at DATA_PATH
+func predictLocal(config *Config, spec models.SystemSpec) (*models.PredictionResponse, error) {
+	if errs, _ := models.ValidateSystemSpec(spec, config.SpecLimits); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, fieldErr := range errs {
+			messages[i] = fieldErr.Message
+		}
+		return nil, fmt.Errorf("invalid system specifications: %s", strings.Join(messages, "; "))
+	}
+
+	projects, err := data.NewLoader(config.DataPath).LoadDePINSpecs()
+	if err != nil {
+		return nil, err
+	}
+	return service.NewCompatibilityService(projects).PredictCompatibility(spec)
+}
+
+// predictRemote posts the spec to a running server's predict endpoint
+func predictRemote(baseURL, apiKey string, spec models.SystemSpec) (*models.PredictionResponse, error) {
+	body, err := json.Marshal(models.PredictionRequest{System: spec})
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(baseURL, "/") + "/api/v1/predict"
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr models.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+			return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+		}
+		message := apiErr.Error + ": " + apiErr.Message
+		for _, detail := range apiErr.Details {
+			message += "; " + detail.Message
+		}
+		return nil, fmt.Errorf("%s returned %s: %s", url, resp.Status, message)
+	}
+
+	var result models.PredictionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return &result, nil
+}
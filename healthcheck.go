@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// runHealthCheck probes the readiness endpoint of a server running with the
+// same configuration and returns the process exit code: 0 when ready, 1 otherwise.
+// It backs the container HEALTHCHECK, which has no shell or curl available.
+func runHealthCheck(config *Config) int {
+	host := config.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	url := fmt.Sprintf("http://%s/readyz", net.JoinHostPort(host, config.Port))
+	client := &http.Client{Timeout: 3 * time.Second}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "health check failed: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "health check failed: %s returned %s\n", url, resp.Status)
+		return 1
+	}
+
+	return 0
+}
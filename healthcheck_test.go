@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// probeTarget serves /readyz with status and returns a config pointing at it
+func probeTarget(t *testing.T, status int) *Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An unspecified host probes the loopback address
+	return &Config{Host: "0.0.0.0", Port: port}
+}
+
+func TestRunHealthCheck(t *testing.T) {
+	tests := []struct {
+		status int
+		want   int
+	}{
+		{http.StatusOK, 0},
+		{http.StatusServiceUnavailable, 1},
+	}
+	for _, tt := range tests {
+		if got := runHealthCheck(probeTarget(t, tt.status)); got != tt.want {
+			t.Errorf("readyz %d: exit code = %d, want %d", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRunHealthCheckUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	if got := runHealthCheck(&Config{Host: "127.0.0.1", Port: port}); got != 1 {
+		t.Errorf("exit code = %d, want 1", got)
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/data"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+// maxDatasetUploadBytes bounds candidate datasets posted for impact reports
+const maxDatasetUploadBytes = 5 << 20
+
+// AdminHandlers contains handlers for administrative endpoints
+type AdminHandlers struct {
+	keys                 auth.KeyStore
+	usage                *auth.UsageTracker
+	compatibilityService *service.CompatibilityService
+	referenceSystems     []models.ReferenceSystem
+	projectEditor        *service.ProjectEditor
+	projectHistory       storage.ChangeStore
+}
+
+// NewAdminHandlers creates a new admin handlers instance
+func NewAdminHandlers(keys auth.KeyStore, usage *auth.UsageTracker, compatibilityService *service.CompatibilityService, referenceSystems []models.ReferenceSystem, projectEditor *service.ProjectEditor, projectHistory storage.ChangeStore) *AdminHandlers {
+	return &AdminHandlers{
+		keys:                 keys,
+		usage:                usage,
+		compatibilityService: compatibilityService,
+		referenceSystems:     referenceSystems,
+		projectEditor:        projectEditor,
+		projectHistory:       projectHistory,
+	}
+}
+
+// KeyUsage reports request counters for every configured API key
+func (h *AdminHandlers) KeyUsage(c *gin.Context) {
+	usage := h.usage.Snapshot(h.keys.List())
+
+	c.JSON(http.StatusOK, models.APIKeyUsageResponse{
+		Keys:      usage,
+		Total:     len(usage),
+		Timestamp: time.Now(),
+	})
+}
+
+// ReferenceSystems lists the reference systems used for impact reports
+func (h *AdminHandlers) ReferenceSystems(c *gin.Context) {
+	c.JSON(http.StatusOK, models.ReferenceSystemsResponse{
+		Systems: h.referenceSystems,
+		Total:   len(h.referenceSystems),
+	})
+}
+
+// DatasetImpact reports how replacing the live dataset with the candidate CSV
+// in the request body would change predictions for the reference systems
+func (h *AdminHandlers) DatasetImpact(c *gin.Context) {
+	if len(h.referenceSystems) == 0 {
+		abortWithError(c, http.StatusServiceUnavailable, "No reference systems", "No reference systems are configured")
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxDatasetUploadBytes)
+	candidate, err := data.NewStrictLoader("request body").ParseDePINSpecs(body)
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, "Invalid dataset", err.Error())
+		return
+	}
+
+	report, err := service.CompareDatasets(h.referenceSystems, h.compatibilityService.GetProjects(), candidate)
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, "Impact report failed", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CreateProject adds a project to the live dataset as a new stored version
+func (h *AdminHandlers) CreateProject(c *gin.Context) {
+	var project models.DePINProject
+	if !bindProject(c, &project) {
+		return
+	}
+
+	record, err := h.projectEditor.Create(project, adminActor(c))
+	if err != nil {
+		abortWithProjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// UpdateProject replaces a project in the live dataset as a new stored version
+func (h *AdminHandlers) UpdateProject(c *gin.Context) {
+	var project models.DePINProject
+	if !bindProject(c, &project) {
+		return
+	}
+
+	// The path names the project; a body without a name keeps it
+	name := c.Param("name")
+	if project.Name == "" {
+		project.Name = name
+	}
+
+	record, err := h.projectEditor.Update(name, project, adminActor(c))
+	if err != nil {
+		abortWithProjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// DeleteProject removes a project from the live dataset as a new stored version
+func (h *AdminHandlers) DeleteProject(c *gin.Context) {
+	record, err := h.projectEditor.Delete(c.Param("name"), adminActor(c))
+	if err != nil {
+		abortWithProjectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// ProjectHistory lists project changes, newest first, optionally filtered
+// by ?project= and capped by ?limit=
+func (h *AdminHandlers) ProjectHistory(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			abortWithError(c, http.StatusBadRequest, "Invalid limit", "limit must be a non-negative integer")
+			return
+		}
+	}
+
+	changes, err := h.projectHistory.ListChanges(c.Query("project"), limit)
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, "History unavailable", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ProjectHistoryResponse{
+		Changes: changes,
+		Total:   len(changes),
+	})
+}
+
+// bindProject decodes a project body, answering 400 when it does not parse
+func bindProject(c *gin.Context, project *models.DePINProject) bool {
+	if err := c.ShouldBindJSON(project); err != nil {
+		response := newErrorResponse(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		response.Details = bindingFieldErrors(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return false
+	}
+	return true
+}
+
+// abortWithProjectError maps ProjectEditor errors to HTTP statuses
+func abortWithProjectError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProject):
+		abortWithError(c, http.StatusBadRequest, "Invalid project", err.Error())
+	case errors.Is(err, service.ErrProjectNotFound):
+		abortWithError(c, http.StatusNotFound, "Project not found", err.Error())
+	case errors.Is(err, service.ErrProjectExists):
+		abortWithError(c, http.StatusConflict, "Project exists", err.Error())
+	case errors.Is(err, service.ErrLastProject):
+		abortWithError(c, http.StatusConflict, "Last project", err.Error())
+	default:
+		abortWithError(c, http.StatusInternalServerError, "Project change failed", err.Error())
+	}
+}
+
+// adminActor names the API key making a change for the history
+func adminActor(c *gin.Context) string {
+	if key, ok := APIKeyFromContext(c); ok {
+		return key.Name
+	}
+	return "anonymous"
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+)
+
+// apiKeyContextKey is the gin context key holding the authenticated *auth.APIKey
+const apiKeyContextKey = "api_key"
+
+// APIKeyMiddleware authenticates requests presenting an API key via the
+// X-API-Key header or an Authorization bearer token. Requests without a key
+// are let through anonymously unless required is set; invalid keys are
+// always rejected.
+func APIKeyMiddleware(keys auth.KeyStore, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented := presentedAPIKey(c)
+		if presented == "" {
+			if required {
+				abortUnauthorized(c, "API key required")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		key, ok := keys.Lookup(presented)
+		if !ok {
+			abortUnauthorized(c, "Invalid or disabled API key")
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose API key lacks scope. Anonymous requests
+// pass for non-admin scopes, since APIKeyMiddleware decides whether keys are
+// mandatory; the admin scope always needs a key.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := APIKeyFromContext(c)
+		if !ok {
+			if scope == auth.ScopeAdmin {
+				abortUnauthorized(c, "API key with admin scope required")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if !key.HasScope(scope) {
+			abortWithError(c, http.StatusForbidden, "Forbidden",
+				"API key '"+key.Name+"' lacks the '"+scope+"' scope")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// APIKeyFromContext returns the API key authenticated for this request, if any
+func APIKeyFromContext(c *gin.Context) (*auth.APIKey, bool) {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return nil, false
+	}
+	key, ok := value.(*auth.APIKey)
+	return key, ok
+}
+
+// presentedAPIKey extracts the key from X-API-Key or Authorization: Bearer
+func presentedAPIKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+		return key
+	}
+
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	abortWithError(c, http.StatusUnauthorized, "Unauthorized", message)
+}
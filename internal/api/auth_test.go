@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+)
+
+// newAuthRouter serves /read, /predict and /admin behind APIKeyMiddleware,
+// each requiring the matching scope and echoing the key name
+func newAuthRouter(t *testing.T, required bool) *gin.Engine {
+	t.Helper()
+	keys, err := auth.NewMemoryKeyStore([]auth.APIKey{
+		{Name: "reader", Key: "k-read", Scopes: []string{auth.ScopeRead}},
+		{Name: "ops", Key: "k-admin", Scopes: []string{auth.ScopeAdmin}},
+		{Name: "retired", Key: "k-off", Scopes: []string{auth.ScopeRead}, Disabled: true},
+	})
+	if err != nil {
+		t.Fatalf("NewMemoryKeyStore: %v", err)
+	}
+
+	echo := func(c *gin.Context) {
+		name := "anonymous"
+		if key, ok := APIKeyFromContext(c); ok {
+			name = key.Name
+		}
+		c.String(http.StatusOK, name)
+	}
+	return newTestRouter(t, func(router *gin.Engine) {
+		router.GET("/read", RequireScope(auth.ScopeRead), echo)
+		router.GET("/predict", RequireScope(auth.ScopePredict), echo)
+		router.GET("/admin", RequireScope(auth.ScopeAdmin), echo)
+	}, APIKeyMiddleware(keys, required))
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		required bool
+		path     string
+		header   string
+		value    string
+		want     int
+		wantKey  string
+	}{
+		{"anonymous allowed", false, "/read", "", "", http.StatusOK, "anonymous"},
+		{"anonymous when required", true, "/read", "", "", http.StatusUnauthorized, ""},
+		{"X-API-Key", true, "/read", "X-API-Key", "k-read", http.StatusOK, "reader"},
+		{"bearer token", true, "/read", "Authorization", "Bearer k-read", http.StatusOK, "reader"},
+		{"lower-case bearer", true, "/read", "Authorization", "bearer k-read", http.StatusOK, "reader"},
+		{"basic auth ignored", true, "/read", "Authorization", "Basic k-read", http.StatusUnauthorized, ""},
+		{"invalid key when optional", false, "/read", "X-API-Key", "nope", http.StatusUnauthorized, ""},
+		{"disabled key", false, "/read", "X-API-Key", "k-off", http.StatusUnauthorized, ""},
+		{"missing scope", false, "/predict", "X-API-Key", "k-read", http.StatusForbidden, ""},
+		{"admin implies predict", false, "/predict", "X-API-Key", "k-admin", http.StatusOK, "ops"},
+		{"anonymous admin", false, "/admin", "", "", http.StatusUnauthorized, ""},
+		{"admin", false, "/admin", "X-API-Key", "k-admin", http.StatusOK, "ops"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthRouter(t, tt.required)
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				request.Header.Set(tt.header, tt.value)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+			if tt.want == http.StatusOK && recorder.Body.String() != tt.wantKey {
+				t.Errorf("key = %q, want %q", recorder.Body.String(), tt.wantKey)
+			}
+			if tt.want == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// DatasetVersionHeader reports the dataset version a response was computed from
+const DatasetVersionHeader = "X-Dataset-Version"
+
+// queryDatasetSelector reads the ?dataset_version= and ?as_of= parameters
+func queryDatasetSelector(c *gin.Context) (service.DatasetSelector, bool) {
+	version := 0
+	if value := c.Query("dataset_version"); value != "" {
+		var err error
+		if version, err = strconv.Atoi(value); err != nil {
+			abortWithError(c, http.StatusBadRequest, "Invalid dataset version", "dataset_version must be a positive integer")
+			return service.DatasetSelector{}, false
+		}
+	}
+	return datasetSelector(c, version, c.Query("as_of"))
+}
+
+// datasetSelector validates a dataset version and as_of time, answering 400
+// when either is malformed or both are given
+func datasetSelector(c *gin.Context, version int, asOf string) (service.DatasetSelector, bool) {
+	selector, err := service.ParseDatasetSelector(version, asOf)
+	var selectorErr *service.DatasetSelectorError
+	if errors.As(err, &selectorErr) {
+		title := "Invalid dataset selection"
+		switch selectorErr.Field {
+		case "dataset_version":
+			title = "Invalid dataset version"
+		case "as_of":
+			title = "Invalid as_of"
+		}
+		abortWithError(c, http.StatusBadRequest, title, selectorErr.Message)
+		return service.DatasetSelector{}, false
+	}
+	return selector, true
+}
@@ -0,0 +1,419 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/openapi"
+)
+
+// apiInfo describes the service in the generated OpenAPI document
+var apiInfo = openapi.Info{
+	Title:       "DePIN Compatibility API",
+	Version:     "1.0.0",
+	Description: "Predicts DePIN compatibility based on consumer system specifications",
+}
+
+// Query parameters selecting a past dataset version
+var (
+	datasetVersionParameter = openapi.Parameter{
+		Name:        "dataset_version",
+		In:          "query",
+		Description: "Use this retained dataset version instead of the live one",
+		Schema:      &openapi.Schema{Type: "integer"},
+	}
+	asOfParameter = openapi.Parameter{
+		Name:        "as_of",
+		In:          "query",
+		Description: "Use the dataset version live at this RFC 3339 time or YYYY-MM-DD date (end of day, UTC)",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+)
+
+// profileTokenParameter authorizes changes to a saved profile
+var profileTokenParameter = openapi.Parameter{
+	Name:        "X-Profile-Token",
+	In:          "header",
+	Description: "access_token from the response that created the profile; not needed for the creating key or admin keys",
+	Schema:      &openapi.Schema{Type: "string"},
+}
+
+// operations documents the routes registered by server.NewRouter, keyed by
+// "METHOD /path". Request and response shapes come from the models package.
+var operations = map[string]openapi.Operation{
+	"POST /api/v1/predict": {
+		Summary: "Predict DePIN compatibility for a system",
+		Tags:    []string{"prediction"},
+		Parameters: []openapi.Parameter{{
+			Name:        "Accept-Language",
+			In:          "header",
+			Description: "Preferred message locale when the body has no locale",
+			Schema:      &openapi.Schema{Type: "string"},
+		}, datasetVersionParameter, asOfParameter},
+		Request: models.PredictionRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:                  models.PredictionResponse{},
+			http.StatusBadRequest:          models.ErrorResponse{},
+			http.StatusNotFound:            models.ErrorResponse{},
+			http.StatusTooManyRequests:     models.ErrorResponse{},
+			http.StatusInternalServerError: models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/predictions/:id": {
+		Summary: "Get a persisted prediction, unchanged since it was stored",
+		Tags:    []string{"prediction"},
+		Responses: map[int]interface{}{
+			http.StatusOK:       models.StoredPrediction{},
+			http.StatusNotFound: models.ErrorResponse{},
+		},
+	},
+	"DELETE /api/v1/predictions/:id": {
+		Summary: "Delete a persisted prediction (its deletion token, the key that stored it, or admin scope)",
+		Tags:    []string{"prediction"},
+		Parameters: []openapi.Parameter{{
+			Name:        "X-Deletion-Token",
+			In:          "header",
+			Description: "deletion_token from the response that stored the prediction",
+			Schema:      &openapi.Schema{Type: "string"},
+		}},
+		Responses: map[int]interface{}{
+			http.StatusNoContent: nil,
+			http.StatusForbidden: models.ErrorResponse{},
+			http.StatusNotFound:  models.ErrorResponse{},
+		},
+	},
+	"POST /api/v1/systems": {
+		Summary: "Save a named system profile",
+		Tags:    []string{"profiles"},
+		Request: models.SavedProfileRequest{},
+		Responses: map[int]interface{}{
+			http.StatusCreated:         models.SavedProfile{},
+			http.StatusBadRequest:      models.ErrorResponse{},
+			http.StatusTooManyRequests: models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/systems/:id": {
+		Summary: "Get a saved system profile with its latest evaluation",
+		Tags:    []string{"profiles"},
+		Responses: map[int]interface{}{
+			http.StatusOK:       models.SavedProfile{},
+			http.StatusNotFound: models.ErrorResponse{},
+		},
+	},
+	"DELETE /api/v1/systems/:id": {
+		Summary:    "Delete a saved system profile",
+		Tags:       []string{"profiles"},
+		Parameters: []openapi.Parameter{profileTokenParameter},
+		Responses: map[int]interface{}{
+			http.StatusNoContent: nil,
+			http.StatusForbidden: models.ErrorResponse{},
+			http.StatusNotFound:  models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/systems/:id/predict": {
+		Summary: "Predict for a saved profile against the live dataset, with changes since its previous evaluation",
+		Tags:    []string{"profiles"},
+		Parameters: []openapi.Parameter{
+			{Name: "Accept-Language", In: "header", Description: "Preferred message locale", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "plain_text", In: "query", Description: "Strip emojis from messages", Schema: &openapi.Schema{Type: "boolean"}},
+		},
+		Responses: map[int]interface{}{
+			http.StatusOK:              models.ProfilePredictionResponse{},
+			http.StatusNotFound:        models.ErrorResponse{},
+			http.StatusTooManyRequests: models.ErrorResponse{},
+		},
+	},
+	"PUT /api/v1/systems/:id/webhook": {
+		Summary:    "Register a webhook notified when dataset changes alter a saved profile's compatible projects",
+		Tags:       []string{"profiles"},
+		Parameters: []openapi.Parameter{profileTokenParameter},
+		Request:    models.WebhookRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:         models.ProfileWebhook{},
+			http.StatusBadRequest: models.ErrorResponse{},
+			http.StatusForbidden:  models.ErrorResponse{},
+			http.StatusNotFound:   models.ErrorResponse{},
+		},
+	},
+	"DELETE /api/v1/systems/:id/webhook": {
+		Summary:    "Remove a saved profile's webhook",
+		Tags:       []string{"profiles"},
+		Parameters: []openapi.Parameter{profileTokenParameter},
+		Responses: map[int]interface{}{
+			http.StatusNoContent: nil,
+			http.StatusForbidden: models.ErrorResponse{},
+			http.StatusNotFound:  models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/systems/:id/webhook/deliveries": {
+		Summary: "Webhook delivery attempts for a saved profile, newest first",
+		Tags:    []string{"profiles"},
+		Parameters: []openapi.Parameter{
+			{Name: "limit", In: "query", Description: "Maximum number of deliveries, default 50, 0 for all", Schema: &openapi.Schema{Type: "integer"}},
+			profileTokenParameter,
+		},
+		Responses: map[int]interface{}{
+			http.StatusOK:         models.WebhookDeliveriesResponse{},
+			http.StatusBadRequest: models.ErrorResponse{},
+			http.StatusForbidden:  models.ErrorResponse{},
+			http.StatusNotFound:   models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/health": {
+		Summary:   "Service health check",
+		Tags:      []string{"operations"},
+		Responses: map[int]interface{}{http.StatusOK: models.HealthResponse{}},
+	},
+	"GET /api/v1/projects": {
+		Summary:    "List all DePIN projects",
+		Tags:       []string{"projects"},
+		Parameters: []openapi.Parameter{datasetVersionParameter, asOfParameter},
+		Responses: map[int]interface{}{
+			http.StatusOK:         models.ProjectsResponse{},
+			http.StatusBadRequest: models.ErrorResponse{},
+			http.StatusNotFound:   models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/datasets": {
+		Summary:   "Retained dataset versions, newest first",
+		Tags:      []string{"projects"},
+		Responses: map[int]interface{}{http.StatusOK: models.DatasetVersionsResponse{}},
+	},
+	"GET /api/v1/docs": {
+		Summary:   "API overview",
+		Tags:      []string{"docs"},
+		Responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}},
+	},
+	"GET /api/v1/docs/ui": {
+		Summary:     "Interactive API documentation",
+		Tags:        []string{"docs"},
+		Responses:   map[int]interface{}{http.StatusOK: ""},
+		ContentType: "text/html",
+	},
+	"GET /api/v1/openapi.json": {
+		Summary:   "OpenAPI 3 document for this API",
+		Tags:      []string{"docs"},
+		Responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}},
+	},
+	"POST /api/v1/graphql": {
+		Summary: "GraphQL queries for projects, summary stats and predictions (predict needs the predict scope)",
+		Tags:    []string{"graphql"},
+		Parameters: []openapi.Parameter{{
+			Name:        "Accept-Language",
+			In:          "header",
+			Description: "Message locale for predictions without a locale argument",
+			Schema:      &openapi.Schema{Type: "string"},
+		}},
+		Request: models.GraphQLRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:              map[string]interface{}{},
+			http.StatusBadRequest:      models.ErrorResponse{},
+			http.StatusTooManyRequests: models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/graphql": {
+		Summary: "GraphQL queries passed as query parameters",
+		Tags:    []string{"graphql"},
+		Parameters: []openapi.Parameter{
+			{Name: "query", In: "query", Required: true, Description: "GraphQL query document", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "operationName", In: "query", Description: "Operation to run when the document has several", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "variables", In: "query", Description: "JSON-encoded variables", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: map[int]interface{}{
+			http.StatusOK:              map[string]interface{}{},
+			http.StatusBadRequest:      models.ErrorResponse{},
+			http.StatusTooManyRequests: models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/events": {
+		Summary: "Server-Sent Events stream of dataset reloads, project edits and summary changes",
+		Tags:    []string{"projects"},
+		Parameters: []openapi.Parameter{
+			{Name: "Last-Event-ID", In: "header", Description: "Resume after this event", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "last_event_id", In: "query", Description: "Resume after this event, for clients that cannot set headers", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses:   map[int]interface{}{http.StatusOK: ""},
+		ContentType: "text/event-stream",
+	},
+	"GET /api/v1/metrics": {
+		Summary:     "Prometheus metrics, or JSON with Accept: application/json",
+		Tags:        []string{"operations"},
+		Responses:   map[int]interface{}{http.StatusOK: ""},
+		ContentType: "text/plain",
+	},
+	"GET /api/v1/metrics/json": {
+		Summary:   "Service and dataset statistics",
+		Tags:      []string{"operations"},
+		Responses: map[int]interface{}{http.StatusOK: models.MetricsResponse{}},
+	},
+	"GET /metrics": {
+		Summary:     "Prometheus scrape endpoint",
+		Tags:        []string{"operations"},
+		Responses:   map[int]interface{}{http.StatusOK: ""},
+		ContentType: "text/plain",
+	},
+	"GET /api/v1/admin/keys/usage": {
+		Summary: "Request counters per API key (admin scope)",
+		Tags:    []string{"admin"},
+		Responses: map[int]interface{}{
+			http.StatusOK:           models.APIKeyUsageResponse{},
+			http.StatusUnauthorized: models.ErrorResponse{},
+			http.StatusForbidden:    models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/admin/reference-systems": {
+		Summary: "Reference systems used for impact reports (admin scope)",
+		Tags:    []string{"admin"},
+		Responses: map[int]interface{}{
+			http.StatusOK:           models.ReferenceSystemsResponse{},
+			http.StatusUnauthorized: models.ErrorResponse{},
+			http.StatusForbidden:    models.ErrorResponse{},
+		},
+	},
+	"POST /api/v1/admin/impact": {
+		Summary:     "Impact of a candidate CSV dataset on the reference systems (admin scope)",
+		Tags:        []string{"admin"},
+		Request:     "",
+		RequestType: "text/csv",
+		Responses: map[int]interface{}{
+			http.StatusOK:                 models.ImpactReport{},
+			http.StatusBadRequest:         models.ErrorResponse{},
+			http.StatusUnauthorized:       models.ErrorResponse{},
+			http.StatusForbidden:          models.ErrorResponse{},
+			http.StatusServiceUnavailable: models.ErrorResponse{},
+		},
+	},
+	"POST /api/v1/admin/projects": {
+		Summary: "Add a project to the dataset (admin scope)",
+		Tags:    []string{"admin"},
+		Request: models.DePINProject{},
+		Responses: map[int]interface{}{
+			http.StatusCreated:      models.ProjectChangeRecord{},
+			http.StatusBadRequest:   models.ErrorResponse{},
+			http.StatusUnauthorized: models.ErrorResponse{},
+			http.StatusForbidden:    models.ErrorResponse{},
+			http.StatusConflict:     models.ErrorResponse{},
+		},
+	},
+	"PUT /api/v1/admin/projects/:name": {
+		Summary: "Replace a project in the dataset (admin scope)",
+		Tags:    []string{"admin"},
+		Request: models.DePINProject{},
+		Responses: map[int]interface{}{
+			http.StatusOK:           models.ProjectChangeRecord{},
+			http.StatusBadRequest:   models.ErrorResponse{},
+			http.StatusUnauthorized: models.ErrorResponse{},
+			http.StatusForbidden:    models.ErrorResponse{},
+			http.StatusNotFound:     models.ErrorResponse{},
+			http.StatusConflict:     models.ErrorResponse{},
+		},
+	},
+	"DELETE /api/v1/admin/projects/:name": {
+		Summary: "Remove a project from the dataset (admin scope); the last project cannot be removed",
+		Tags:    []string{"admin"},
+		Responses: map[int]interface{}{
+			http.StatusOK:           models.ProjectChangeRecord{},
+			http.StatusUnauthorized: models.ErrorResponse{},
+			http.StatusForbidden:    models.ErrorResponse{},
+			http.StatusNotFound:     models.ErrorResponse{},
+			http.StatusConflict:     models.ErrorResponse{},
+		},
+	},
+	"GET /api/v1/admin/projects/history": {
+		Summary: "Project change history, newest first (admin scope)",
+		Tags:    []string{"admin"},
+		Parameters: []openapi.Parameter{
+			{Name: "project", In: "query", Description: "Only changes to this project", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "limit", In: "query", Description: "Maximum number of changes", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Responses: map[int]interface{}{
+			http.StatusOK:           models.ProjectHistoryResponse{},
+			http.StatusBadRequest:   models.ErrorResponse{},
+			http.StatusUnauthorized: models.ErrorResponse{},
+			http.StatusForbidden:    models.ErrorResponse{},
+		},
+	},
+	"GET /livez": {
+		Summary:   "Liveness probe",
+		Tags:      []string{"operations"},
+		Responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}},
+	},
+	"GET /readyz": {
+		Summary: "Readiness probe reflecting dataset, reload and shutdown state",
+		Tags:    []string{"operations"},
+		Responses: map[int]interface{}{
+			http.StatusOK:                 models.ReadinessResponse{},
+			http.StatusServiceUnavailable: models.ReadinessResponse{},
+		},
+	},
+	"GET /": {
+		Summary:   "Service index",
+		Responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}},
+	},
+}
+
+// GenerateOpenAPI builds the OpenAPI document for the routes registered on
+// router, with SystemSpec ranges taken from limits
+func GenerateOpenAPI(router *gin.Engine, limits models.SpecLimits) *openapi.Document {
+	var routes []openapi.Route
+	for _, route := range router.Routes() {
+		routes = append(routes, openapi.Route{Method: route.Method, Path: route.Path})
+	}
+
+	doc := openapi.Generate(apiInfo, routes, operations)
+	if schema := doc.Schema("SystemSpec"); schema != nil {
+		schema.Constrain(specConstraints(limits))
+	}
+	if schema := doc.Schema("GPU"); schema != nil {
+		schema.Constrain(gpuConstraints(limits))
+	}
+	if schema := doc.Schema("StorageDevice"); schema != nil {
+		schema.Constrain(storageDeviceConstraints(limits))
+	}
+	return doc
+}
+
+// OpenAPIHandler serves the generated OpenAPI document. The document is built
+// on first request, once all routes have been registered.
+func OpenAPIHandler(router *gin.Engine, limits models.SpecLimits) gin.HandlerFunc {
+	var once sync.Once
+	var doc *openapi.Document
+
+	return func(c *gin.Context) {
+		once.Do(func() {
+			doc = GenerateOpenAPI(router, limits)
+		})
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+// DocsUI serves the bundled offline documentation page
+func DocsUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.UI)
+}
+
+// systemRequirementsDoc describes SystemSpec fields from their validation rules
+func systemRequirementsDoc(limits models.SpecLimits) gin.H {
+	schema := openapi.Describe(models.SystemSpec{})
+	schema.Constrain(specConstraints(limits))
+
+	gpu := openapi.Describe(models.GPU{})
+	gpu.Constrain(gpuConstraints(limits))
+	drive := openapi.Describe(models.StorageDevice{})
+	drive.Constrain(storageDeviceConstraints(limits))
+
+	doc := gin.H{}
+	describe := func(prefix string, schema *openapi.Schema) {
+		for name, property := range schema.Properties {
+			doc[prefix+name] = property.Summary(slices.Contains(schema.Required, name))
+		}
+	}
+	describe("", schema)
+	describe("gpus[].", gpu)
+	describe("storage_devices[].", drive)
+	return doc
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/logging"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// RequestIDHeader carries the request correlation ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDContextKey is the gin context key holding the request ID
+const requestIDContextKey = "request_id"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware accepts a well-formed X-Request-ID from the client or
+// generates one, stores it on the gin and request contexts for logging, and
+// echoes it in the response header
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDContextKey, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Header(RequestIDHeader, id)
+
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the request ID assigned by RequestIDMiddleware
+func RequestIDFromContext(c *gin.Context) string {
+	return c.GetString(requestIDContextKey)
+}
+
+// validRequestID allows IDs of printable, header-safe ASCII only
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit hex ID
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// newErrorResponse builds the JSON error envelope used by every endpoint
+func newErrorResponse(c *gin.Context, code int, title, message string) models.ErrorResponse {
+	return models.ErrorResponse{
+		Error:     title,
+		Message:   message,
+		Code:      code,
+		RequestID: RequestIDFromContext(c),
+		Time:      time.Now(),
+	}
+}
+
+// abortWithError writes the JSON error envelope and stops the handler chain
+func abortWithError(c *gin.Context, code int, title, message string) {
+	c.AbortWithStatusJSON(code, newErrorResponse(c, code, title, message))
+}
+
+// NotFound answers unknown routes with a JSON error
+func NotFound(c *gin.Context) {
+	abortWithError(c, http.StatusNotFound, "Not found",
+		fmt.Sprintf("No route for %s %s", c.Request.Method, c.Request.URL.Path))
+}
+
+// MethodNotAllowed answers known routes called with the wrong method with a JSON error
+func MethodNotAllowed(c *gin.Context) {
+	abortWithError(c, http.StatusMethodNotAllowed, "Method not allowed",
+		fmt.Sprintf("%s is not supported for %s", c.Request.Method, c.Request.URL.Path))
+}
+
+// RecoveryMiddleware turns panics into a logged error and a JSON 500
+func RecoveryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered interface{}) {
+		logger.ErrorContext(c.Request.Context(), "Panic recovered",
+			"panic", fmt.Sprint(recovered),
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"stack", string(debug.Stack()),
+		)
+		abortWithError(c, http.StatusInternalServerError, "Internal server error",
+			"An unexpected error occurred. Quote the request ID when reporting this issue.")
+	})
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/logging"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// newErrorsRouter serves /ok, which echoes the request ID seen by loggers,
+// and /panic behind the request ID and recovery middleware
+func newErrorsRouter(t *testing.T, logs *bytes.Buffer) *gin.Engine {
+	t.Helper()
+	logger, err := logging.New(logs, "debug", "json")
+	if err != nil {
+		t.Fatalf("logging.New: %v", err)
+	}
+	return newTestRouter(t, func(router *gin.Engine) {
+		router.HandleMethodNotAllowed = true
+		router.NoRoute(NotFound)
+		router.NoMethod(MethodNotAllowed)
+		router.GET("/ok", func(c *gin.Context) {
+			c.String(http.StatusOK, logging.RequestID(c.Request.Context()))
+		})
+		router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	}, RequestIDMiddleware(), RecoveryMiddleware(logger))
+}
+
+// decodeError decodes the JSON error envelope of a response
+func decodeError(t *testing.T, recorder *httptest.ResponseRecorder) models.ErrorResponse {
+	t.Helper()
+	var response models.ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode %q: %v", recorder.Body.String(), err)
+	}
+	return response
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	router := newErrorsRouter(t, &bytes.Buffer{})
+
+	tests := []struct {
+		name      string
+		presented string
+		kept      bool
+	}{
+		{"accepted", "trace-7f3a", true},
+		{"generated", "", false},
+		{"spaces replaced", "two words", false},
+		{"too long replaced", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			if tt.presented != "" {
+				request.Header.Set(RequestIDHeader, tt.presented)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			id := recorder.Header().Get(RequestIDHeader)
+			if tt.kept && id != tt.presented {
+				t.Errorf("ID = %q, want the presented %q", id, tt.presented)
+			}
+			if !tt.kept && (len(id) != 32 || id == tt.presented) {
+				t.Errorf("ID = %q, want a generated 32-character ID", id)
+			}
+			if recorder.Body.String() != id {
+				t.Errorf("request context ID = %q, want %q", recorder.Body.String(), id)
+			}
+		})
+	}
+}
+
+func TestErrorEnvelopes(t *testing.T) {
+	var logs bytes.Buffer
+	router := newErrorsRouter(t, &logs)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+		title  string
+	}{
+		{"unknown route", http.MethodGet, "/missing", http.StatusNotFound, "Not found"},
+		{"wrong method", http.MethodPost, "/ok", http.StatusMethodNotAllowed, "Method not allowed"},
+		{"panic", http.MethodGet, "/panic", http.StatusInternalServerError, "Internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			request.Header.Set(RequestIDHeader, "req-"+strings.ReplaceAll(tt.name, " ", "-"))
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.code {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.code)
+			}
+			response := decodeError(t, recorder)
+			if response.Error != tt.title || response.Code != tt.code || response.Message == "" || response.Time.IsZero() {
+				t.Errorf("envelope = %+v", response)
+			}
+			if response.RequestID != request.Header.Get(RequestIDHeader) {
+				t.Errorf("request_id = %q, want %q", response.RequestID, request.Header.Get(RequestIDHeader))
+			}
+		})
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("panic log %q: %v", logs.String(), err)
+	}
+	if record["msg"] != "Panic recovered" || record["panic"] != "boom" || record["request_id"] != "req-panic" {
+		t.Errorf("panic log = %v", record)
+	}
+}
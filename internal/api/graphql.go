@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/graphqlapi"
+	"github.com/simoncrean/api-predict/internal/i18n"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+)
+
+// GraphQL executes queries against schema, from a JSON body on POST or from
+// the query, operationName and variables parameters on GET. Malformed
+// requests get 400; errors while executing a well-formed request are listed
+// in the response's errors with status 200, alongside any data resolved.
+// The rate limit middleware charges the request once; every predict field
+// after the first takes another token from predictLimiter and another unit
+// of the key's quota. predictLimiter may be nil to charge requests only once.
+func GraphQL(schema graphql.Schema, predictLimiter *ratelimit.Limiter, m *metrics.Metrics, usage *auth.UsageTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GraphQLRequest
+		var err error
+		if c.Request.Method == http.MethodGet {
+			err = c.ShouldBindQuery(&request)
+			if variables := c.Query("variables"); err == nil && variables != "" {
+				err = json.Unmarshal([]byte(variables), &request.Variables)
+			}
+		} else {
+			err = c.ShouldBindJSON(&request)
+		}
+		if err != nil {
+			response := newErrorResponse(c, http.StatusBadRequest, "Invalid GraphQL request", err.Error())
+			response.Details = bindingFieldErrors(err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+
+		key, _ := APIKeyFromContext(c)
+		locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+
+		var charge func() error
+		if predictLimiter != nil {
+			client, route := clientKey(c), routeLabel(c)
+			charge = func() error {
+				return chargePrediction(predictLimiter, m, usage, client, route, key)
+			}
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  request.Query,
+			OperationName:  request.OperationName,
+			VariableValues: request.Variables,
+			Context:        graphqlapi.WithRequest(c.Request.Context(), key, locale, charge),
+		})
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// chargePrediction charges one GraphQL prediction to the caller's predict
+// bucket and quota
+func chargePrediction(limiter *ratelimit.Limiter, m *metrics.Metrics, usage *auth.UsageTracker, client, route string, key *auth.APIKey) error {
+	charge := ratelimit.ChargeRequest(limiter, usage, client, key)
+	if charge.Allowed {
+		return nil
+	}
+
+	if m != nil {
+		m.ObserveRateLimitRejection(route)
+	}
+	message := "Too many requests. Please try again later."
+	if charge.QuotaExceeded {
+		message = "Daily quota exceeded for this API key."
+	}
+	return &graphqlapi.Error{Code: graphqlapi.CodeRateLimited, Message: message, RetryAfter: charge.RetryAfter}
+}
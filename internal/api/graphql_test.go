@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/graphqlapi"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// graphQLResponse is the decoded body of a GraphQL response
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newGraphQLRouter serves /graphql with a predict bucket of burst tokens
+// that never refills
+func newGraphQLRouter(t *testing.T, burst int) *gin.Engine {
+	t.Helper()
+	appMetrics := metrics.New()
+	schema, err := graphqlapi.NewSchema(service.NewCompatibilityService(testDataset(t)), appMetrics, models.DefaultSpecLimits())
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+	limiter := ratelimit.NewLimiter(rate.Every(time.Hour), burst, 100, time.Hour)
+	handler := GraphQL(schema, limiter, appMetrics, auth.NewUsageTracker())
+
+	return newTestRouter(t, func(router *gin.Engine) {
+		router.POST("/graphql", handler)
+		router.GET("/graphql", handler)
+	})
+}
+
+// postGraphQL sends query as alice and decodes the response
+func postGraphQL(t *testing.T, router *gin.Engine, query string) graphQLResponse {
+	t.Helper()
+	body, _ := json.Marshal(models.GraphQLRequest{Query: query})
+	request := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Test-Key", "alice")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body.String())
+	}
+	var response graphQLResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return response
+}
+
+func TestGraphQLChargesEachPrediction(t *testing.T) {
+	router := newGraphQLRouter(t, 2)
+
+	var query strings.Builder
+	query.WriteString("{")
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(&query, ` p%d: predict(system: {cpu_cores: 8, ram_gb: 32, storage_gb: 2000, network_mbps: 500, os: "Linux"}) { locale }`, i)
+	}
+	query.WriteString(" }")
+
+	// The middleware pays for the first prediction and the bucket for two
+	// more, so one of the four is rate limited. Fields resolve in no fixed
+	// order, so which one varies.
+	response := postGraphQL(t, router, query.String())
+	if len(response.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1: %+v", len(response.Errors), response.Errors)
+	}
+	extensions := response.Errors[0].Extensions
+	if extensions["code"] != graphqlapi.CodeRateLimited {
+		t.Errorf("code = %v, want %s", extensions["code"], graphqlapi.CodeRateLimited)
+	}
+	if retryAfter, _ := extensions["retry_after"].(float64); retryAfter <= 0 {
+		t.Errorf("retry_after = %v, want a positive number of seconds", extensions["retry_after"])
+	}
+	limited := 0
+	for _, prediction := range response.Data {
+		if string(prediction) == "null" {
+			limited++
+		}
+	}
+	if len(response.Data) != 4 || limited != 1 {
+		t.Errorf("data = %s, want one null prediction of four", response.Data)
+	}
+}
+
+func TestGraphQLQueriesDoNotCharge(t *testing.T) {
+	router := newGraphQLRouter(t, 0)
+
+	response := postGraphQL(t, router, "{ a: projects { total } b: summary { total } }")
+	if len(response.Errors) != 0 {
+		t.Fatalf("errors = %+v, want none", response.Errors)
+	}
+}
+
+func TestGraphQLMalformedRequest(t *testing.T) {
+	router := newGraphQLRouter(t, 1)
+
+	tests := []struct {
+		name    string
+		request *http.Request
+	}{
+		{"POST without query", httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{}`))},
+		{"GET with bad variables", httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ projects { total } }")+"&variables=nope", nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, tt.request)
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", recorder.Code)
+			}
+		})
+	}
+}
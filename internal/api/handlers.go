@@ -1,11 +1,17 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github/simoncrean/api-predict/internal/models"
-	"github/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/i18n"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +19,20 @@ import (
 // Handlers contains all HTTP request handlers
 type Handlers struct {
 	compatibilityService *service.CompatibilityService
+	metrics              *metrics.Metrics
+	readiness            *health.Readiness
+	limits               models.SpecLimits
+	predictions          *service.PredictionArchive
 }
 
 // NewHandlers creates a new handlers instance
-func NewHandlers(compatibilityService *service.CompatibilityService) *Handlers {
+func NewHandlers(compatibilityService *service.CompatibilityService, m *metrics.Metrics, readiness *health.Readiness, limits models.SpecLimits, predictions *service.PredictionArchive) *Handlers {
 	return &Handlers{
 		compatibilityService: compatibilityService,
+		metrics:              m,
+		readiness:            readiness,
+		limits:               limits,
+		predictions:          predictions,
 	}
 }
 
@@ -28,48 +42,92 @@ func (h *Handlers) PredictCompatibility(c *gin.Context) {
 
 	// Bind and validate request
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request format",
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
-			Time:    time.Now(),
-		})
+		response := newErrorResponse(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		response.Details = bindingFieldErrors(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
 		return
 	}
 
 	// Validate system specifications
-	if err := validateSystemSpec(request.System); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid system specifications",
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
-			Time:    time.Now(),
-		})
+	fieldErrs, warnings := validateSystemSpec(request.System, h.limits)
+	if len(fieldErrs) > 0 {
+		response := newErrorResponse(c, http.StatusBadRequest, "Invalid system specifications",
+			fmt.Sprintf("%d field(s) failed validation", len(fieldErrs)))
+		response.Details = fieldErrs
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
 		return
 	}
 
+	// Body fields select the dataset, falling back to the query string
+	var selector service.DatasetSelector
+	var ok bool
+	if request.DatasetVersion != 0 || request.AsOf != "" {
+		selector, ok = datasetSelector(c, request.DatasetVersion, request.AsOf)
+	} else {
+		selector, ok = queryDatasetSelector(c)
+	}
+	if !ok {
+		return
+	}
+
+	// Request body locale takes precedence over Accept-Language
+	locale := request.Locale
+	if locale == "" {
+		locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+	}
+
 	// Perform compatibility prediction
-	result, err := h.compatibilityService.PredictCompatibility(request.System)
+	result, err := h.compatibilityService.PredictCompatibilityWithOptions(c.Request.Context(), request.System, service.PredictOptions{
+		Locale:    locale,
+		PlainText: request.PlainText,
+		Dataset:   selector,
+	})
+	if errors.Is(err, service.ErrDatasetVersionNotFound) {
+		abortWithError(c, http.StatusNotFound, "Dataset version not found", err.Error())
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Prediction failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-			Time:    time.Now(),
-		})
+		abortWithError(c, http.StatusInternalServerError, "Prediction failed", err.Error())
 		return
 	}
 
+	h.metrics.ObservePrediction(result)
+	result.InputWarnings = warnings
+	result.RequestID = RequestIDFromContext(c)
+
+	if request.Persist {
+		owner := ""
+		if key, ok := APIKeyFromContext(c); ok {
+			owner = key.Name
+		}
+		if _, err := h.predictions.Save(request.System, result, owner); err != nil {
+			abortWithError(c, http.StatusInternalServerError, "Prediction not stored", err.Error())
+			return
+		}
+		c.Header("Location", "/api/v1/predictions/"+result.PredictionID)
+	}
+
+	c.Header("Content-Language", result.Locale)
+	c.Header(DatasetVersionHeader, strconv.Itoa(result.DatasetVersion))
 	c.JSON(http.StatusOK, result)
 }
 
-// HealthCheck handles health check requests
+// HealthCheck handles health check requests. Status is "healthy" when ready,
+// "draining" during shutdown and "degraded" otherwise.
 func (h *Handlers) HealthCheck(c *gin.Context) {
 	projects := h.compatibilityService.GetProjects()
 	uptime := h.compatibilityService.GetUptime()
 
+	status := "healthy"
+	if ready, _ := h.readiness.Check(); !ready {
+		status = "degraded"
+		if h.readiness.Draining() {
+			status = "draining"
+		}
+	}
+
 	health := models.HealthResponse{
-		Status:         "healthy",
+		Status:         status,
 		Version:        "1.0.0",
 		ProjectsLoaded: len(projects),
 		Uptime:         uptime.String(),
@@ -79,20 +137,71 @@ func (h *Handlers) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, health)
 }
 
-// ListProjects handles requests for listing all DePIN projects
+// Livez reports that the process is up and serving requests
+func (h *Handlers) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "alive",
+		"timestamp": time.Now(),
+	})
+}
+
+// Readyz reports whether the service should receive traffic, returning 503
+// until the dataset is loaded, after a failed reload and while draining
+func (h *Handlers) Readyz(c *gin.Context) {
+	ready, checks := h.readiness.Check()
+
+	response := models.ReadinessResponse{
+		Status:    "ready",
+		Checks:    checks,
+		Timestamp: time.Now(),
+	}
+
+	code := http.StatusOK
+	if !ready {
+		response.Status = "not_ready"
+		code = http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, response)
+}
+
+// ListProjects handles requests for listing all DePIN projects, optionally
+// of a past dataset version selected by ?dataset_version= or ?as_of=
 func (h *Handlers) ListProjects(c *gin.Context) {
-	projects := h.compatibilityService.GetProjects()
-	summary := h.compatibilityService.GetProjectSummary()
+	selector, ok := queryDatasetSelector(c)
+	if !ok {
+		return
+	}
+
+	projects, version, err := h.compatibilityService.ProjectsAt(selector)
+	if err != nil {
+		abortWithError(c, http.StatusNotFound, "Dataset version not found", err.Error())
+		return
+	}
 
 	response := models.ProjectsResponse{
-		Projects: projects,
-		Total:    len(projects),
-		Summary:  summary,
+		Projects:         projects,
+		Total:            len(projects),
+		Summary:          service.SummarizeProjects(projects),
+		DatasetVersion:   version.Version,
+		DatasetCreatedAt: version.CreatedAt,
 	}
 
+	c.Header(DatasetVersionHeader, strconv.Itoa(version.Version))
 	c.JSON(http.StatusOK, response)
 }
 
+// ListDatasetVersions lists the retained dataset versions, newest first
+func (h *Handlers) ListDatasetVersions(c *gin.Context) {
+	versions := h.compatibilityService.DatasetVersions()
+
+	c.JSON(http.StatusOK, models.DatasetVersionsResponse{
+		Versions: versions,
+		Current:  versions[0].Version,
+		Total:    len(versions),
+	})
+}
+
 // APIDocs serves API documentation
 func (h *Handlers) APIDocs(c *gin.Context) {
 	docs := gin.H{
@@ -113,28 +222,37 @@ func (h *Handlers) APIDocs(c *gin.Context) {
 						"network_mbps": 100,
 						"os":           "Windows",
 					},
+					"locale":     "en",
+					"plain_text": false,
+				},
+				"localization": gin.H{
+					"locales":    i18n.Supported(),
+					"selection":  "locale field, then Accept-Language header, then en",
+					"plain_text": "Set plain_text to strip emojis from messages",
 				},
 			},
 			"GET /api/v1/health": gin.H{
 				"description": "Service health check",
 			},
+			"GET /livez": gin.H{
+				"description": "Liveness probe",
+			},
+			"GET /readyz": gin.H{
+				"description": "Readiness probe, 503 when not ready",
+			},
 			"GET /api/v1/projects": gin.H{
 				"description": "List all DePIN projects",
 			},
 			"GET /api/v1/metrics": gin.H{
-				"description": "Service metrics",
+				"description": "Prometheus metrics (text exposition, or JSON with Accept: application/json)",
+			},
+			"GET /api/v1/metrics/json": gin.H{
+				"description": "Service and dataset statistics as JSON",
 			},
 		},
-		"system_requirements": gin.H{
-			"cpu_cores":    "Number of CPU cores (1-64)",
-			"ram_gb":       "RAM in GB (1-128)",
-			"storage_gb":   "Storage in GB (32-8192)",
-			"has_ssd":      "Boolean - SSD storage",
-			"has_gpu":      "Boolean - Dedicated GPU",
-			"gpu_vram_gb":  "GPU VRAM in GB (0-48)",
-			"network_mbps": "Network speed in Mbps (1-10000)",
-			"os":           "Operating system (Windows/Linux/macOS)",
-		},
+		"system_requirements": systemRequirementsDoc(h.limits),
+		"openapi":             "/api/v1/openapi.json",
+		"docs_ui":             "/api/v1/docs/ui",
 		"compatibility_scores": gin.H{
 			"excellent": "0.9 - 1.0",
 			"good":      "0.7 - 0.89",
@@ -146,43 +264,37 @@ func (h *Handlers) APIDocs(c *gin.Context) {
 	c.JSON(http.StatusOK, docs)
 }
 
-// Metrics handles metrics requests (simplified Prometheus-style metrics)
+// Metrics serves Prometheus text exposition, or the JSON summary when the
+// client prefers application/json
 func (h *Handlers) Metrics(c *gin.Context) {
+	if c.NegotiateFormat(gin.MIMEPlain, gin.MIMEJSON) == gin.MIMEJSON {
+		h.MetricsJSON(c)
+		return
+	}
+
+	h.metrics.Handler().ServeHTTP(c.Writer, c.Request)
+}
+
+// MetricsJSON serves a JSON summary of service and dataset statistics
+func (h *Handlers) MetricsJSON(c *gin.Context) {
 	projects := h.compatibilityService.GetProjects()
 	summary := h.compatibilityService.GetProjectSummary()
 	uptime := h.compatibilityService.GetUptime()
 
-	metrics := gin.H{
-		"service_info": gin.H{
-			"name":           "depin_compatibility_api",
-			"version":        "1.0.0",
-			"uptime_seconds": uptime.Seconds(),
+	metrics := models.MetricsResponse{
+		ServiceInfo: models.ServiceInfo{
+			Name:          "depin_compatibility_api",
+			Version:       "1.0.0",
+			UptimeSeconds: uptime.Seconds(),
 		},
-		"projects_loaded_total":  len(projects),
-		"projects_by_type":       summary.ByType,
-		"projects_by_cost":       summary.ByCostCategory,
-		"projects_home_friendly": summary.HomeFriendly,
-		"projects_gpu_required":  summary.GPURequired,
-		"timestamp":              time.Now().Unix(),
+		ProjectsLoadedTotal:  len(projects),
+		ProjectsByType:       summary.ByType,
+		ProjectsByCost:       summary.ByCostCategory,
+		ProjectsHomeFriendly: summary.HomeFriendly,
+		ProjectsGPURequired:  summary.GPURequired,
+		DatasetVersion:       h.compatibilityService.CurrentDatasetVersion().Version,
+		Timestamp:            time.Now().Unix(),
 	}
 
 	c.JSON(http.StatusOK, metrics)
 }
-
-// validateSystemSpec performs additional validation on system specifications
-func validateSystemSpec(spec models.SystemSpec) error {
-	// Custom validation logic can be added here
-	// For example, logical consistency checks
-
-	// If GPU is claimed but no VRAM specified
-	if spec.HasGPU && spec.GPUVRAMGB == 0 {
-		// This might be valid for very old GPUs, so just a warning
-	}
-
-	// If no GPU but VRAM specified
-	if !spec.HasGPU && spec.GPUVRAMGB > 0 {
-		// This might indicate integrated graphics
-	}
-
-	return nil
-}
@@ -0,0 +1,312 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/i18n"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// newHandlersRouter serves the repository dataset through Handlers as
+// version 1, with version 2 holding only its first three projects
+func newHandlersRouter(t *testing.T, readiness *health.Readiness) *gin.Engine {
+	t.Helper()
+	projects := testDataset(t)
+	compatibilityService := service.NewCompatibilityService(projects)
+	if _, _, err := compatibilityService.SetProjects(projects[:3], service.DatasetSourceAdmin); err != nil {
+		t.Fatalf("set projects: %v", err)
+	}
+	handlers := NewHandlers(compatibilityService, metrics.New(), readiness, models.DefaultSpecLimits(), nil)
+
+	return newTestRouter(t, func(router *gin.Engine) {
+		router.POST("/predict", handlers.PredictCompatibility)
+		router.GET("/projects", handlers.ListProjects)
+		router.GET("/datasets", handlers.ListDatasetVersions)
+		router.GET("/health", handlers.HealthCheck)
+		router.GET("/livez", handlers.Livez)
+		router.GET("/readyz", handlers.Readyz)
+	}, RequestIDMiddleware())
+}
+
+// serve sends a request without a body and returns the recorder
+func serve(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestProbes(t *testing.T) {
+	readiness := health.NewReadiness()
+	router := newHandlersRouter(t, readiness)
+
+	steps := []struct {
+		name        string
+		apply       func()
+		ready       int
+		readyStatus string
+		health      string
+	}{
+		{"starting", func() {}, http.StatusServiceUnavailable, "not_ready", "degraded"},
+		{"loaded", func() { readiness.DatasetLoaded(10, time.Now()) }, http.StatusOK, "ready", "healthy"},
+		{"reload failed", func() { readiness.ReloadFailed(errors.New("bad row"), time.Now()) }, http.StatusServiceUnavailable, "not_ready", "degraded"},
+		{"draining", func() { readiness.DatasetLoaded(10, time.Now()); readiness.StartDraining() }, http.StatusServiceUnavailable, "not_ready", "draining"},
+	}
+	for _, step := range steps {
+		step.apply()
+
+		if recorder := serve(router, http.MethodGet, "/livez"); recorder.Code != http.StatusOK {
+			t.Errorf("%s: livez = %d, want 200", step.name, recorder.Code)
+		}
+
+		recorder := serve(router, http.MethodGet, "/readyz")
+		if recorder.Code != step.ready {
+			t.Errorf("%s: readyz = %d, want %d", step.name, recorder.Code, step.ready)
+		}
+		var ready models.ReadinessResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &ready); err != nil {
+			t.Fatalf("%s: decode readyz: %v", step.name, err)
+		}
+		if ready.Status != step.readyStatus || len(ready.Checks) != 3 {
+			t.Errorf("%s: readyz body = %+v", step.name, ready)
+		}
+
+		recorder = serve(router, http.MethodGet, "/health")
+		var healthResponse models.HealthResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &healthResponse); err != nil {
+			t.Fatalf("%s: decode health: %v", step.name, err)
+		}
+		if recorder.Code != http.StatusOK || healthResponse.Status != step.health || healthResponse.ProjectsLoaded == 0 {
+			t.Errorf("%s: health = %d %+v, want %s", step.name, recorder.Code, healthResponse, step.health)
+		}
+	}
+}
+
+// postPredict sends body to POST /predict
+func postPredict(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// detailFields lists the field paths of an error envelope's details
+func detailFields(response models.ErrorResponse) []string {
+	fields := make([]string, len(response.Details))
+	for i, detail := range response.Details {
+		fields[i] = detail.Field + ":" + detail.Rule
+	}
+	return fields
+}
+
+func TestPredictValidationErrors(t *testing.T) {
+	router := newHandlersRouter(t, health.NewReadiness())
+
+	tests := []struct {
+		name  string
+		body  string
+		title string
+		want  []string
+	}{
+		{"malformed JSON", `{"system":`, "Invalid request format", []string{}},
+		{"missing system", `{}`, "Invalid system specifications", []string{
+			"system.cpu_cores:required", "system.ram_gb:required", "system.storage_gb:required", "system.network_mbps:required", "system.os:required",
+		}},
+		{"wrong type", `{"system": {"cpu_cores": "eight"}}`, "Invalid request format", []string{"system.cpu_cores:type"}},
+		{"out of range", `{"system": {"cpu_cores": 0, "ram_gb": 32, "storage_gb": 500, "network_mbps": 100, "os": "Windows"}}`,
+			"Invalid system specifications", []string{"system.cpu_cores:required"}},
+		{"several fields", `{"system": {"cpu_cores": 4, "ram_gb": 99999, "storage_gb": 500, "network_mbps": 100, "os": "Plan 9"}}`,
+			"Invalid system specifications", []string{"system.ram_gb:max", "system.os:oneof"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := postPredict(router, tt.body)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", recorder.Code)
+			}
+			response := decodeError(t, recorder)
+			if response.Error != tt.title {
+				t.Errorf("error = %q, want %q", response.Error, tt.title)
+			}
+			if got := detailFields(response); !slices.Equal(got, tt.want) {
+				t.Errorf("details = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredictReturnsInputWarnings(t *testing.T) {
+	router := newHandlersRouter(t, health.NewReadiness())
+
+	recorder := postPredict(router, `{"system": {"cpu_cores": 8, "ram_gb": 32, "storage_gb": 1000, "has_gpu": true, "network_mbps": 500, "os": "Linux"}}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body.String())
+	}
+	var response models.PredictionResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(response.InputWarnings) != 1 || response.InputWarnings[0].Field != "system.gpu_vram_gb" {
+		t.Errorf("input_warnings = %+v, want system.gpu_vram_gb", response.InputWarnings)
+	}
+}
+
+func TestListProjectsDatasetSelection(t *testing.T) {
+	router := newHandlersRouter(t, health.NewReadiness())
+
+	tests := []struct {
+		name    string
+		query   string
+		code    int
+		version string // X-Dataset-Version, or the error title
+	}{
+		{"live", "", http.StatusOK, "2"},
+		{"past version", "?dataset_version=1", http.StatusOK, "1"},
+		{"as of now", "?as_of=" + time.Now().UTC().Format(time.RFC3339Nano), http.StatusOK, "2"},
+		{"as of a date", "?as_of=2000-01-01", http.StatusNotFound, "Dataset version not found"},
+		{"unknown version", "?dataset_version=7", http.StatusNotFound, "Dataset version not found"},
+		{"non-numeric version", "?dataset_version=latest", http.StatusBadRequest, "Invalid dataset version"},
+		{"negative version", "?dataset_version=-1", http.StatusBadRequest, "Invalid dataset version"},
+		{"malformed as_of", "?as_of=last-week", http.StatusBadRequest, "Invalid as_of"},
+		{"both", "?dataset_version=1&as_of=2024-01-01", http.StatusBadRequest, "Invalid dataset selection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(router, http.MethodGet, "/projects"+tt.query)
+			if recorder.Code != tt.code {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.code, recorder.Body.String())
+			}
+			if tt.code != http.StatusOK {
+				if response := decodeError(t, recorder); response.Error != tt.version {
+					t.Errorf("error = %q, want %q", response.Error, tt.version)
+				}
+				return
+			}
+
+			if got := recorder.Header().Get(DatasetVersionHeader); got != tt.version {
+				t.Errorf("%s = %q, want %s", DatasetVersionHeader, got, tt.version)
+			}
+			var response models.ProjectsResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if strconv.Itoa(response.DatasetVersion) != tt.version || response.Total != len(response.Projects) {
+				t.Errorf("response version %d with total %d of %d projects", response.DatasetVersion, response.Total, len(response.Projects))
+			}
+		})
+	}
+}
+
+func TestPredictDatasetSelection(t *testing.T) {
+	router := newHandlersRouter(t, health.NewReadiness())
+	system := `"system": {"cpu_cores": 8, "ram_gb": 32, "storage_gb": 1000, "has_ssd": true, "network_mbps": 500, "os": "Linux"}`
+
+	// The body selector wins over the query string
+	request := httptest.NewRequest(http.MethodPost, "/predict?dataset_version=2", strings.NewReader(`{`+system+`, "dataset_version": 1}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Header().Get(DatasetVersionHeader) != "1" {
+		t.Errorf("status %d on version %q, want 200 on 1", recorder.Code, recorder.Header().Get(DatasetVersionHeader))
+	}
+
+	if recorder := postPredict(router, `{`+system+`, "dataset_version": 1, "as_of": "2024-01-01"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("both selectors: status = %d, want 400", recorder.Code)
+	}
+	if recorder := postPredict(router, `{`+system+`, "dataset_version": 9}`); recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown version: status = %d, want 404", recorder.Code)
+	}
+}
+
+func TestListDatasetVersions(t *testing.T) {
+	router := newHandlersRouter(t, health.NewReadiness())
+
+	recorder := serve(router, http.MethodGet, "/datasets")
+	var response models.DatasetVersionsResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if response.Current != 2 || response.Total != 2 || response.Versions[0].Version != 2 || response.Versions[0].Projects != 3 {
+		t.Errorf("response = %+v", response)
+	}
+}
+
+func TestPredictLocalization(t *testing.T) {
+	router := newHandlersRouter(t, health.NewReadiness())
+
+	predict := func(acceptLanguage, fields string) models.PredictionResponse {
+		t.Helper()
+		body := `{"system": {"cpu_cores": 8, "ram_gb": 32, "storage_gb": 2000, "network_mbps": 500, "os": "Linux"}` + fields + `}`
+		request := httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		if acceptLanguage != "" {
+			request.Header.Set("Accept-Language", acceptLanguage)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+		}
+
+		var response models.PredictionResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if recorder.Header().Get("Content-Language") != response.Locale {
+			t.Errorf("Content-Language = %q, body locale %q", recorder.Header().Get("Content-Language"), response.Locale)
+		}
+		if len(response.Recommendations) == 0 {
+			t.Fatal("no recommendations")
+		}
+		return response
+	}
+
+	english := predict("", "")
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		fields         string
+		locale         string
+	}{
+		{"default", "", "", "en"},
+		{"header", "es-MX,en;q=0.5", "", "es"},
+		{"refused language", "es;q=0, en", "", "en"},
+		{"unsupported language", "de", "", "en"},
+		{"body overrides header", "es", `, "locale": "en"`, "en"},
+		{"body locale", "", `, "locale": "es"`, "es"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := predict(tt.acceptLanguage, tt.fields)
+			if response.Locale != tt.locale {
+				t.Errorf("locale = %q, want %q", response.Locale, tt.locale)
+			}
+			if translated := response.Recommendations[0] != english.Recommendations[0]; translated != (tt.locale != "en") {
+				t.Errorf("recommendation %q, English %q", response.Recommendations[0], english.Recommendations[0])
+			}
+		})
+	}
+
+	plain := predict("", `, "plain_text": true`)
+	for _, recommendation := range plain.Recommendations {
+		if i18n.StripEmoji(recommendation) != recommendation {
+			t.Errorf("plain_text recommendation %q keeps emojis", recommendation)
+		}
+	}
+	if plain.Recommendations[0] == english.Recommendations[0] {
+		t.Errorf("plain_text left %q unchanged", english.Recommendations[0])
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+// testKeys are the API keys a request selects by name with the X-Test-Key
+// header
+var testKeys = map[string]*auth.APIKey{
+	"alice":   {Name: "alice", Scopes: []string{auth.ScopePredict, auth.ScopeRead}},
+	"bob":     {Name: "bob", Scopes: []string{auth.ScopePredict, auth.ScopeRead}},
+	"ops":     {Name: "ops", Scopes: []string{auth.ScopeAdmin}},
+	"bulk":    {Name: "bulk", RatePerSecond: 1, Burst: 5},
+	"metered": {Name: "metered", Burst: 5, DailyQuota: 1},
+}
+
+// newTestRouter returns a test-mode router that runs middleware, then
+// authenticates the key named in X-Test-Key, before the handlers registered
+// by routes
+func newTestRouter(t *testing.T, routes func(router *gin.Engine), middleware ...gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware...)
+	router.Use(func(c *gin.Context) {
+		if key, ok := testKeys[c.GetHeader("X-Test-Key")]; ok {
+			c.Set(apiKeyContextKey, key)
+		}
+	})
+	routes(router)
+	return router
+}
+
+// testDataset loads the repository dataset
+func testDataset(t *testing.T) []models.DePINProject {
+	t.Helper()
+	projects, _, err := storage.NewCSVSource(filepath.Join("..", "..", "data", "depin_specs.csv")).Load()
+	if err != nil {
+		t.Fatalf("load dataset: %v", err)
+	}
+	return projects
+}
+
+// openTestStore opens a bolt store that is closed when the test ends
+func openTestStore(t *testing.T) *storage.BoltStore {
+	t.Helper()
+	store, err := storage.OpenBolt(filepath.Join(t.TempDir(), "api.db"))
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
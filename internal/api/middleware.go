@@ -1,13 +1,13 @@
 package api
 
 import (
-	"fmt"
+	"log/slog"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+
+	"github.com/simoncrean/api-predict/internal/metrics"
 )
 
 // CORSMiddleware handles Cross-Origin Resource Sharing
@@ -15,8 +15,8 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
-		c.Header("Access-Control-Expose-Headers", "Content-Length")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-API-Key, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, X-Request-ID, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
@@ -29,26 +29,24 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
-// RateLimitMiddleware implements simple rate limiting
-func RateLimitMiddleware() gin.HandlerFunc {
-	// Create a rate limiter that allows 10 requests per second with burst of 20
-	limiter := NewIPRateLimiter(10, 20)
-
+// MetricsMiddleware records request counts and latency per route and status
+func MetricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-
-		if !limiter.Allow(clientIP) {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Rate limit exceeded",
-				"message":     "Too many requests. Please try again later.",
-				"retry_after": "1 minute",
-			})
-			c.Abort()
-			return
-		}
+		start := time.Now()
 
 		c.Next()
+
+		m.ObserveRequest(c.Request.Method, routeLabel(c), c.Writer.Status(), time.Since(start))
+	}
+}
+
+// routeLabel returns the matched route template, keeping label cardinality
+// bounded for unknown paths
+func routeLabel(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
 	}
+	return "unmatched"
 }
 
 // SecurityMiddleware adds basic security headers
@@ -62,79 +60,49 @@ func SecurityMiddleware() gin.HandlerFunc {
 	}
 }
 
-// RequestLoggingMiddleware logs API requests with timing
-func RequestLoggingMiddleware() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	})
-}
-
-// IPRateLimiter manages rate limiting per IP address
-type IPRateLimiter struct {
-	ips map[string]*rate.Limiter
-	mu  *sync.RWMutex
-	r   rate.Limit
-	b   int
-}
+// RequestLoggingMiddleware emits one structured record per request with the
+// request ID (from the request context, see RequestIDMiddleware), route,
+// status, latency and the client key used for rate limiting. Server errors
+// log at error level and client errors at warn.
+func RequestLoggingMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
 
-// NewIPRateLimiter creates a new IP-based rate limiter
-func NewIPRateLimiter(r rate.Limit, b int) *IPRateLimiter {
-	i := &IPRateLimiter{
-		ips: make(map[string]*rate.Limiter),
-		mu:  &sync.RWMutex{},
-		r:   r,
-		b:   b,
-	}
+		c.Next()
 
-	return i
-}
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
 
-// Allow checks if a request from the given IP is allowed
-func (i *IPRateLimiter) Allow(ip string) bool {
-	i.mu.Lock()
-	defer i.mu.Unlock()
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", routeLabel(c)),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("client_key", clientKey(c)),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
 
-	limiter, exists := i.ips[ip]
-	if !exists {
-		limiter = rate.NewLimiter(i.r, i.b)
-		i.ips[ip] = limiter
+		logger.LogAttrs(c.Request.Context(), level, "HTTP request", attrs...)
 	}
-
-	return limiter.Allow()
-}
-
-// CleanupMiddleware periodically cleans up old rate limiters
-func (i *IPRateLimiter) CleanupMiddleware() {
-	ticker := time.NewTicker(time.Hour)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				i.cleanup()
-			}
-		}
-	}()
 }
 
-// cleanup removes inactive rate limiters
-func (i *IPRateLimiter) cleanup() {
-	i.mu.Lock()
-	defer i.mu.Unlock()
-
-	for ip, limiter := range i.ips {
-		// Remove limiters that haven't been used recently
-		if limiter.Burst() == limiter.Tokens() {
-			delete(i.ips, ip)
-		}
+// clientKey identifies the caller the way the rate limiter does: by API key
+// name when authenticated, otherwise by client IP
+func clientKey(c *gin.Context) string {
+	if key, ok := APIKeyFromContext(c); ok {
+		return "key:" + key.Name
 	}
+	return "ip:" + c.ClientIP()
 }
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/logging"
+	"github.com/simoncrean/api-predict/internal/metrics"
+)
+
+func TestRequestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		status int
+		level  string
+	}{
+		{http.StatusOK, "INFO"},
+		{http.StatusNotFound, "WARN"},
+		{http.StatusServiceUnavailable, "ERROR"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger, err := logging.New(&buf, "debug", "json")
+		if err != nil {
+			t.Fatalf("logging.New: %v", err)
+		}
+
+		router := newTestRouter(t, func(router *gin.Engine) {
+			router.GET("/items/:id", func(c *gin.Context) { c.Status(tt.status) })
+		}, RequestIDMiddleware(), RequestLoggingMiddleware(logger))
+
+		request := httptest.NewRequest(http.MethodGet, "/items/7", nil)
+		request.Header.Set(RequestIDHeader, "req-42")
+		router.ServeHTTP(httptest.NewRecorder(), request)
+
+		var record map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("status %d: invalid record %q: %v", tt.status, buf.String(), err)
+		}
+		if record["level"] != tt.level {
+			t.Errorf("status %d logged at %v, want %s", tt.status, record["level"], tt.level)
+		}
+		if record["request_id"] != "req-42" || record["route"] != "/items/:id" || record["path"] != "/items/7" {
+			t.Errorf("status %d: record = %v", tt.status, record)
+		}
+		if record["status"] != float64(tt.status) || !strings.HasPrefix(record["client_key"].(string), "ip:") {
+			t.Errorf("status %d: record = %v", tt.status, record)
+		}
+	}
+}
+
+func TestRequestLoggingMiddlewareHonorsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "warn", "json")
+	if err != nil {
+		t.Fatalf("logging.New: %v", err)
+	}
+
+	router := newTestRouter(t, func(router *gin.Engine) {
+		router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	}, RequestLoggingMiddleware(logger))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if buf.Len() != 0 {
+		t.Errorf("successful request logged at LOG_LEVEL=warn: %q", buf.String())
+	}
+}
+
+func TestMetricsMiddlewareLabelsRouteTemplates(t *testing.T) {
+	m := metrics.New()
+	router := newTestRouter(t, func(router *gin.Engine) {
+		router.GET("/items/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	}, MetricsMiddleware(m))
+	for _, path := range []string{"/items/1", "/items/2", "/nowhere/3"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	for _, want := range []string{
+		`depin_http_requests_total{method="GET",route="/items/:id",status="200"} 2`,
+		`depin_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("exposition lacks %q", want)
+		}
+	}
+	if strings.Contains(body, "/nowhere/3") {
+		t.Error("unmatched path used as a label")
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+// DeletionTokenHeader carries the deletion token returned when a prediction
+// was stored
+const DeletionTokenHeader = "X-Deletion-Token"
+
+// GetPrediction returns a persisted prediction. Stored predictions never
+// change, so responses may be cached until they are deleted or expire.
+func (h *Handlers) GetPrediction(c *gin.Context) {
+	prediction, err := h.predictions.Get(c.Param("id"))
+	if err != nil {
+		abortWithPredictionError(c, err)
+		return
+	}
+	prediction.Owner = ""
+	prediction.DeletionTokenHash = ""
+
+	c.Header("Cache-Control", "private, max-age=3600, immutable")
+	c.Header("ETag", strconv.Quote(prediction.ID))
+	c.Header(DatasetVersionHeader, strconv.Itoa(prediction.Result.DatasetVersion))
+	c.JSON(http.StatusOK, prediction)
+}
+
+// DeletePrediction deletes a persisted prediction. Only a caller holding its
+// deletion token, the API key that stored it or an admin key may delete it.
+func (h *Handlers) DeletePrediction(c *gin.Context) {
+	prediction, err := h.predictions.Get(c.Param("id"))
+	if err != nil {
+		abortWithPredictionError(c, err)
+		return
+	}
+
+	key, ok := APIKeyFromContext(c)
+	switch {
+	case ok && key.HasScope(auth.ScopeAdmin):
+	case ok && prediction.Owner != "" && key.Name == prediction.Owner:
+	case service.ValidDeletionToken(prediction, c.GetHeader(DeletionTokenHeader)):
+	default:
+		abortWithError(c, http.StatusForbidden, "Forbidden", "Deleting a prediction needs its deletion token, the API key that stored it or an admin key")
+		return
+	}
+
+	if err := h.predictions.Delete(prediction.ID); err != nil {
+		abortWithPredictionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// abortWithPredictionError maps PredictionArchive errors to HTTP statuses
+func abortWithPredictionError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, service.ErrPredictionExpired) {
+		abortWithError(c, http.StatusNotFound, "Prediction not found", fmt.Sprintf("No stored prediction with ID %q", c.Param("id")))
+		return
+	}
+	abortWithError(c, http.StatusInternalServerError, "Prediction unavailable", err.Error())
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// newPredictionRouter serves the prediction routes over a bolt store
+func newPredictionRouter(t *testing.T) (*gin.Engine, *service.PredictionArchive) {
+	t.Helper()
+	archive := service.NewPredictionArchive(openTestStore(t), 0)
+	handlers := &Handlers{predictions: archive}
+
+	router := newTestRouter(t, func(router *gin.Engine) {
+		router.GET("/predictions/:id", handlers.GetPrediction)
+		router.DELETE("/predictions/:id", handlers.DeletePrediction)
+	})
+	return router, archive
+}
+
+func TestDeletePrediction(t *testing.T) {
+	tests := []struct {
+		name       string
+		owner      string
+		key        string
+		useToken   bool
+		wrongToken bool
+		want       int
+	}{
+		{"anonymous with token", "", "", true, false, http.StatusNoContent},
+		{"anonymous without token", "", "", false, false, http.StatusForbidden},
+		{"anonymous with wrong token", "", "", false, true, http.StatusForbidden},
+		{"other key with token", "alice", "bob", true, false, http.StatusNoContent},
+		{"owner", "alice", "alice", false, false, http.StatusNoContent},
+		{"other key", "alice", "bob", false, false, http.StatusForbidden},
+		{"admin", "", "ops", false, false, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		router, archive := newPredictionRouter(t)
+		result := &models.PredictionResponse{}
+		if _, err := archive.Save(models.SystemSpec{CPUCores: 4}, result, tt.owner); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		request := httptest.NewRequest(http.MethodDelete, "/predictions/"+result.PredictionID, nil)
+		request.Header.Set("X-Test-Key", tt.key)
+		switch {
+		case tt.useToken:
+			request.Header.Set(DeletionTokenHeader, result.DeletionToken)
+		case tt.wrongToken:
+			request.Header.Set(DeletionTokenHeader, result.PredictionID)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		if recorder.Code != tt.want {
+			t.Errorf("%s: DELETE = %d, want %d: %s", tt.name, recorder.Code, tt.want, recorder.Body)
+		}
+		_, err := archive.Get(result.PredictionID)
+		if deleted := err != nil; deleted != (tt.want == http.StatusNoContent) {
+			t.Errorf("%s: deleted = %v after %d", tt.name, deleted, recorder.Code)
+		}
+	}
+}
+
+func TestGetPredictionHidesSecrets(t *testing.T) {
+	router, archive := newPredictionRouter(t)
+	result := &models.PredictionResponse{}
+	if _, err := archive.Save(models.SystemSpec{CPUCores: 4}, result, "alice"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/predictions/"+result.PredictionID, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET = %d: %s", recorder.Code, recorder.Body)
+	}
+	for _, secret := range []string{"alice", "deletion_token", result.DeletionToken} {
+		if body := recorder.Body.String(); strings.Contains(body, secret) {
+			t.Errorf("GET response contains %q: %s", secret, body)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/predictions/unknown", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("GET unknown = %d, want 404", recorder.Code)
+	}
+}
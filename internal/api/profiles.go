@@ -0,0 +1,232 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/i18n"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+// ProfileTokenHeader carries the access token returned when a saved profile
+// was created
+const ProfileTokenHeader = "X-Profile-Token"
+
+// ProfileHandlers contains handlers for saved system profiles
+type ProfileHandlers struct {
+	profiles   *service.ProfileService
+	deliveries storage.WebhookStore
+	metrics    *metrics.Metrics
+	limits     models.SpecLimits
+}
+
+// NewProfileHandlers creates a new profile handlers instance
+func NewProfileHandlers(profiles *service.ProfileService, deliveries storage.WebhookStore, m *metrics.Metrics, limits models.SpecLimits) *ProfileHandlers {
+	return &ProfileHandlers{
+		profiles:   profiles,
+		deliveries: deliveries,
+		metrics:    m,
+		limits:     limits,
+	}
+}
+
+// CreateProfile saves a named system profile and returns it with its ID and
+// access token, which is not shown again
+func (h *ProfileHandlers) CreateProfile(c *gin.Context) {
+	var request models.SavedProfileRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response := newErrorResponse(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		response.Details = bindingFieldErrors(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	fieldErrs, _ := validateSystemSpec(request.System, h.limits)
+	if len(fieldErrs) > 0 {
+		response := newErrorResponse(c, http.StatusBadRequest, "Invalid system specifications",
+			fmt.Sprintf("%d field(s) failed validation", len(fieldErrs)))
+		response.Details = fieldErrs
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	owner := ""
+	if key, ok := APIKeyFromContext(c); ok {
+		owner = key.Name
+	}
+	profile, err := h.profiles.Create(request.Name, request.System, owner)
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, "Profile not saved", err.Error())
+		return
+	}
+
+	profile.AccessTokenHash = ""
+	profile.Owner = ""
+	c.Header("Location", "/api/v1/systems/"+profile.ID)
+	c.JSON(http.StatusCreated, profile)
+}
+
+// GetProfile returns a saved system profile with its latest evaluation
+func (h *ProfileHandlers) GetProfile(c *gin.Context) {
+	profile, err := h.profiles.Get(c.Param("id"))
+	if err != nil {
+		abortWithProfileError(c, err)
+		return
+	}
+
+	redactProfileSecrets(&profile)
+	c.JSON(http.StatusOK, profile)
+}
+
+// DeleteProfile deletes a saved system profile
+func (h *ProfileHandlers) DeleteProfile(c *gin.Context) {
+	if !h.authorizeProfile(c) {
+		return
+	}
+	if err := h.profiles.Delete(c.Param("id")); err != nil {
+		abortWithProfileError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PredictProfile predicts compatibility for a saved profile against the live
+// dataset, reporting the changes since its previous evaluation
+func (h *ProfileHandlers) PredictProfile(c *gin.Context) {
+	result, err := h.profiles.Predict(c.Request.Context(), c.Param("id"), service.PredictOptions{
+		Locale:    i18n.Negotiate(c.GetHeader("Accept-Language")),
+		PlainText: c.Query("plain_text") == "true",
+	})
+	if err != nil {
+		abortWithProfileError(c, err)
+		return
+	}
+
+	h.metrics.ObservePrediction(result.Prediction)
+	result.Prediction.RequestID = RequestIDFromContext(c)
+	redactProfileSecrets(&result.Profile)
+
+	c.Header("Content-Language", result.Prediction.Locale)
+	c.Header(DatasetVersionHeader, strconv.Itoa(result.Prediction.DatasetVersion))
+	c.JSON(http.StatusOK, result)
+}
+
+// SetWebhook registers or replaces the webhook notified when dataset changes
+// alter a saved profile's compatible projects. The response includes the
+// signing secret, which is not shown again.
+func (h *ProfileHandlers) SetWebhook(c *gin.Context) {
+	var request models.WebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response := newErrorResponse(c, http.StatusBadRequest, "Invalid request format", err.Error())
+		response.Details = bindingFieldErrors(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if !h.authorizeProfile(c) {
+		return
+	}
+
+	webhook, err := h.profiles.SetWebhook(c.Request.Context(), c.Param("id"), request.URL, request.Secret)
+	if err != nil {
+		abortWithProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook removes a saved profile's webhook
+func (h *ProfileHandlers) DeleteWebhook(c *gin.Context) {
+	if !h.authorizeProfile(c) {
+		return
+	}
+	if err := h.profiles.RemoveWebhook(c.Param("id")); err != nil {
+		abortWithProfileError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// WebhookDeliveries lists delivery attempts of a saved profile's webhook
+// events, newest first, capped by ?limit= (default 50)
+func (h *ProfileHandlers) WebhookDeliveries(c *gin.Context) {
+	limit := 50
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			abortWithError(c, http.StatusBadRequest, "Invalid limit", "limit must be a non-negative integer")
+			return
+		}
+	}
+
+	if !h.authorizeProfile(c) {
+		return
+	}
+
+	deliveries, err := h.deliveries.ListWebhookDeliveries(c.Param("id"), limit)
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, "Delivery log unavailable", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      len(deliveries),
+	})
+}
+
+// authorizeProfile aborts unless the caller may change the profile named by
+// the id parameter: it holds the profile's access token, is the API key that
+// created it or has the admin scope
+func (h *ProfileHandlers) authorizeProfile(c *gin.Context) bool {
+	profile, err := h.profiles.Get(c.Param("id"))
+	if err != nil {
+		abortWithProfileError(c, err)
+		return false
+	}
+
+	key, ok := APIKeyFromContext(c)
+	switch {
+	case ok && key.HasScope(auth.ScopeAdmin):
+	case ok && profile.Owner != "" && key.Name == profile.Owner:
+	case service.ValidProfileToken(profile, c.GetHeader(ProfileTokenHeader)):
+	default:
+		abortWithError(c, http.StatusForbidden, "Forbidden", "Changing a profile needs its access token, the API key that created it or an admin key")
+		return false
+	}
+	return true
+}
+
+// redactProfileSecrets hides the ownership fields and webhook signing secret
+// of a profile
+func redactProfileSecrets(profile *models.SavedProfile) {
+	profile.AccessTokenHash = ""
+	profile.Owner = ""
+	if profile.Webhook != nil {
+		webhook := *profile.Webhook
+		webhook.Secret = ""
+		profile.Webhook = &webhook
+	}
+}
+
+// abortWithProfileError maps ProfileService errors to HTTP statuses
+func abortWithProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		abortWithError(c, http.StatusNotFound, "Profile not found", fmt.Sprintf("No saved profile with ID %q", c.Param("id")))
+	case errors.Is(err, service.ErrInvalidWebhook):
+		abortWithError(c, http.StatusBadRequest, "Invalid webhook", err.Error())
+	default:
+		abortWithError(c, http.StatusInternalServerError, "Profile unavailable", err.Error())
+	}
+}
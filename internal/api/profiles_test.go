@@ -0,0 +1,213 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// newProfileRouter serves the profile routes, with the methods
+// server.NewRouter uses, over a bolt store
+func newProfileRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	store := openTestStore(t)
+	profiles := service.NewProfileService(service.NewCompatibilityService(testDataset(t)), store, false)
+	handlers := NewProfileHandlers(profiles, store, metrics.New(), models.DefaultSpecLimits())
+
+	return newTestRouter(t, func(router *gin.Engine) {
+		router.POST("/systems", handlers.CreateProfile)
+		router.GET("/systems/:id", handlers.GetProfile)
+		router.DELETE("/systems/:id", handlers.DeleteProfile)
+		router.GET("/systems/:id/predict", handlers.PredictProfile)
+		router.PUT("/systems/:id/webhook", handlers.SetWebhook)
+		router.DELETE("/systems/:id/webhook", handlers.DeleteWebhook)
+		router.GET("/systems/:id/webhook/deliveries", handlers.WebhookDeliveries)
+	})
+}
+
+// serveProfile sends a request as key, with token in X-Profile-Token
+func serveProfile(router *gin.Engine, method, target, body, key, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Test-Key", key)
+	if token != "" {
+		request.Header.Set(ProfileTokenHeader, token)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// createProfile creates a profile as key and returns it with its token
+func createProfile(t *testing.T, router *gin.Engine, key string) models.SavedProfile {
+	t.Helper()
+	body := `{"name": "rig", "system": {"cpu_cores": 8, "ram_gb": 16, "storage_gb": 512, "network_mbps": 100, "os": "Linux"}}`
+	recorder := serveProfile(router, http.MethodPost, "/systems", body, key, "")
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("POST /systems = %d: %s", recorder.Code, recorder.Body)
+	}
+	if strings.Contains(recorder.Body.String(), "access_token_hash") || strings.Contains(recorder.Body.String(), `"owner"`) {
+		t.Errorf("create response leaks ownership fields: %s", recorder.Body)
+	}
+
+	var profile models.SavedProfile
+	if err := json.Unmarshal(recorder.Body.Bytes(), &profile); err != nil || profile.AccessToken == "" {
+		t.Fatalf("create response %s: %v, want an access token", recorder.Body, err)
+	}
+	return profile
+}
+
+func TestProfileOwnership(t *testing.T) {
+	tests := []struct {
+		name     string
+		creator  string
+		key      string
+		useToken bool
+		want     int
+	}{
+		{"anonymous with token", "", "", true, http.StatusNoContent},
+		{"anonymous without token", "", "", false, http.StatusForbidden},
+		{"other key with token", "alice", "bob", true, http.StatusNoContent},
+		{"creating key", "alice", "alice", false, http.StatusNoContent},
+		{"other key", "alice", "bob", false, http.StatusForbidden},
+		{"anonymous caller of a keyed profile", "alice", "", false, http.StatusForbidden},
+		{"admin", "", "ops", false, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		router := newProfileRouter(t)
+		profile := createProfile(t, router, tt.creator)
+		token := ""
+		if tt.useToken {
+			token = profile.AccessToken
+		}
+		target := "/systems/" + profile.ID
+
+		// Every webhook route and delete share the same check
+		checks := []struct {
+			method, path, body string
+			success            int
+		}{
+			{http.MethodGet, target + "/webhook/deliveries", "", http.StatusOK},
+			{http.MethodDelete, target + "/webhook", "", http.StatusNoContent},
+			{http.MethodDelete, target, "", http.StatusNoContent},
+		}
+		for _, check := range checks {
+			want := tt.want
+			if want != http.StatusForbidden {
+				want = check.success
+			}
+			if recorder := serveProfile(router, check.method, check.path, check.body, tt.key, token); recorder.Code != want {
+				t.Errorf("%s: %s %s = %d, want %d: %s", tt.name, check.method, check.path, recorder.Code, want, recorder.Body)
+			}
+		}
+	}
+}
+
+func TestSetWebhookRequiresOwnership(t *testing.T) {
+	router := newProfileRouter(t)
+	profile := createProfile(t, router, "")
+	target := "/systems/" + profile.ID + "/webhook"
+	body := `{"url": "https://93.184.216.34/hook"}`
+
+	if recorder := serveProfile(router, http.MethodPut, target, body, "", ""); recorder.Code != http.StatusForbidden {
+		t.Errorf("PUT without token = %d, want 403", recorder.Code)
+	}
+	if recorder := serveProfile(router, http.MethodPut, target, body, "", "wrong"); recorder.Code != http.StatusForbidden {
+		t.Errorf("PUT with a wrong token = %d, want 403", recorder.Code)
+	}
+	if recorder := serveProfile(router, http.MethodPut, target, body, "", profile.AccessToken); recorder.Code != http.StatusOK {
+		t.Errorf("PUT with token = %d: %s", recorder.Code, recorder.Body)
+	}
+
+	// Internal targets are rejected even for the owner
+	metadata := `{"url": "http://169.254.169.254/latest/meta-data/"}`
+	if recorder := serveProfile(router, http.MethodPut, target, metadata, "", profile.AccessToken); recorder.Code != http.StatusBadRequest {
+		t.Errorf("PUT metadata URL = %d, want 400: %s", recorder.Code, recorder.Body)
+	}
+
+	// Readers see neither the ownership fields nor the webhook secret
+	recorder := serveProfile(router, http.MethodGet, "/systems/"+profile.ID, "", "", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET = %d: %s", recorder.Code, recorder.Body)
+	}
+	for _, field := range []string{"access_token", "owner", "secret"} {
+		if strings.Contains(recorder.Body.String(), `"`+field) {
+			t.Errorf("GET response contains %q: %s", field, recorder.Body)
+		}
+	}
+}
+
+func TestProfileNotFound(t *testing.T) {
+	router := newProfileRouter(t)
+	if recorder := serveProfile(router, http.MethodDelete, "/systems/unknown", "", "ops", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("DELETE unknown = %d, want 404", recorder.Code)
+	}
+}
+
+func TestPredictProfile(t *testing.T) {
+	router := newProfileRouter(t)
+	profile := createProfile(t, router, "alice")
+
+	for i, wantChanges := range []bool{false, true} {
+		recorder := serveProfile(router, http.MethodGet, "/systems/"+profile.ID+"/predict", "", "", "")
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("predict %d = %d: %s", i+1, recorder.Code, recorder.Body)
+		}
+		if recorder.Header().Get(DatasetVersionHeader) != "1" {
+			t.Errorf("predict %d: %s = %q, want 1", i+1, DatasetVersionHeader, recorder.Header().Get(DatasetVersionHeader))
+		}
+		if strings.Contains(recorder.Body.String(), "access_token_hash") {
+			t.Errorf("predict %d leaks the token hash", i+1)
+		}
+
+		var response models.ProfilePredictionResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if response.Prediction == nil || response.Prediction.Summary.TotalProjects == 0 {
+			t.Fatalf("predict %d: prediction = %+v", i+1, response.Prediction)
+		}
+		if (response.Changes != nil) != wantChanges {
+			t.Errorf("predict %d: changes = %+v, want present %v", i+1, response.Changes, wantChanges)
+		}
+		if wantChanges && (len(response.Changes.Gained) != 0 || len(response.Changes.Lost) != 0) {
+			t.Errorf("unchanged dataset reported changes: %+v", response.Changes)
+		}
+		if response.Profile.LastEvaluation == nil {
+			t.Errorf("predict %d: evaluation not returned", i+1)
+		}
+	}
+
+	if recorder := serveProfile(router, http.MethodGet, "/systems/missing/predict", "", "", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("unknown profile = %d, want 404", recorder.Code)
+	}
+}
+
+func TestCreateProfileValidation(t *testing.T) {
+	router := newProfileRouter(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing name", `{"system": {"cpu_cores": 8, "ram_gb": 16, "storage_gb": 512, "network_mbps": 100, "os": "Linux"}}`},
+		{"long name", `{"name": "` + strings.Repeat("x", 101) + `", "system": {"cpu_cores": 8, "ram_gb": 16, "storage_gb": 512, "network_mbps": 100, "os": "Linux"}}`},
+		{"invalid system", `{"name": "rig", "system": {"cpu_cores": 8, "ram_gb": 16, "storage_gb": 512, "network_mbps": 100, "os": "Amiga"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if recorder := serveProfile(router, http.MethodPost, "/systems", tt.body, "", ""); recorder.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", recorder.Code)
+			}
+		})
+	}
+}
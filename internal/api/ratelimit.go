@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+)
+
+// RateLimitMiddleware limits requests for a route group. Requests with an API
+// key are limited per key, using the key's own rate and burst when set, and
+// count against the key's daily quota; anonymous requests are limited per
+// client IP. RateLimit-* headers describe the caller's bucket and rejected
+// requests get a Retry-After computed from the limiter state.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, m *metrics.Metrics, usage *auth.UsageTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, _ := APIKeyFromContext(c)
+		charge := ratelimit.ChargeRequest(limiter, usage, clientKey(c), key)
+
+		setRateLimitHeaders(c, charge.Decision)
+
+		switch {
+		case charge.QuotaExceeded:
+			abortRateLimited(c, m, "Quota exceeded", "Daily quota exceeded for this API key.", charge.RetryAfter)
+			return
+		case !charge.Allowed:
+			abortRateLimited(c, m, "Rate limit exceeded", "Too many requests. Please try again later.", charge.RetryAfter)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// abortRateLimited rejects a request with 429 and a Retry-After header
+func abortRateLimited(c *gin.Context, m *metrics.Metrics, title, message string, retryAfter time.Duration) {
+	if m != nil {
+		m.ObserveRateLimitRejection(routeLabel(c))
+	}
+
+	seconds := ceilSeconds(retryAfter)
+	c.Header("Retry-After", strconv.Itoa(seconds))
+
+	response := newErrorResponse(c, http.StatusTooManyRequests, title, message)
+	response.RetryAfter = seconds
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, response)
+}
+
+// setRateLimitHeaders writes the IETF RateLimit-* headers for a decision
+func setRateLimitHeaders(c *gin.Context, decision ratelimit.Decision) {
+	c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	if decision.Window > 0 {
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", decision.Limit, ceilSeconds(decision.Window)))
+	}
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+)
+
+// newRateLimitedRouter serves /limited with burst requests per client that
+// refill at one per second
+func newRateLimitedRouter(t *testing.T, burst int) (*gin.Engine, *metrics.Metrics) {
+	t.Helper()
+	m := metrics.New()
+	limiter := ratelimit.NewLimiter(rate.Limit(1), burst, 100, time.Hour)
+
+	router := newTestRouter(t, func(router *gin.Engine) {
+		router.GET("/limited", RateLimitMiddleware(limiter, m, auth.NewUsageTracker()), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+	}, RequestIDMiddleware())
+	return router, m
+}
+
+// get sends GET /limited from addr, presenting the named test key
+func get(router *gin.Engine, addr, key string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	request.RemoteAddr = addr
+	request.Header.Set("X-Test-Key", key)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRateLimitMiddlewareHeaders(t *testing.T) {
+	router, m := newRateLimitedRouter(t, 2)
+
+	for i, remaining := range []string{"1", "0"} {
+		recorder := get(router, "192.0.2.1:1000", "")
+		if recorder.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d", i+1, recorder.Code)
+		}
+		if got := recorder.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want 2", i+1, got)
+		}
+		if got := recorder.Header().Get("RateLimit-Remaining"); got != remaining {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %s", i+1, got, remaining)
+		}
+		if got := recorder.Header().Get("RateLimit-Policy"); got != "2;w=2" {
+			t.Errorf("request %d: RateLimit-Policy = %q, want 2;w=2", i+1, got)
+		}
+	}
+
+	recorder := get(router, "192.0.2.1:1000", "")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", recorder.Code)
+	}
+	retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 {
+		t.Errorf("Retry-After = %q, want whole seconds", recorder.Header().Get("Retry-After"))
+	}
+
+	var response models.ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if response.Code != http.StatusTooManyRequests || response.RetryAfter != retryAfter || response.RequestID == "" {
+		t.Errorf("envelope = %+v", response)
+	}
+
+	// Other clients have their own bucket
+	if recorder := get(router, "192.0.2.2:1000", ""); recorder.Code != http.StatusNoContent {
+		t.Errorf("second client: status = %d", recorder.Code)
+	}
+
+	metricsRecorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(metricsRecorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(metricsRecorder.Body.String(), `depin_rate_limit_rejections_total{route="/limited"} 1`) {
+		t.Error("rejection not counted")
+	}
+}
+
+func TestRateLimitMiddlewarePerKey(t *testing.T) {
+	router, _ := newRateLimitedRouter(t, 1)
+
+	// A key's own burst replaces the default and follows it across addresses
+	for i := 0; i < 5; i++ {
+		if recorder := get(router, "192.0.2."+strconv.Itoa(i+1)+":1000", "bulk"); recorder.Code != http.StatusNoContent {
+			t.Fatalf("bulk request %d: status = %d", i+1, recorder.Code)
+		}
+	}
+	if recorder := get(router, "192.0.2.9:1000", "bulk"); recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("bulk request 6: status = %d, want 429", recorder.Code)
+	}
+
+	if recorder := get(router, "192.0.2.1:1000", "metered"); recorder.Code != http.StatusNoContent {
+		t.Fatalf("metered request 1: status = %d", recorder.Code)
+	}
+	recorder := get(router, "192.0.2.1:1000", "metered")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("metered request 2: status = %d, want 429", recorder.Code)
+	}
+	var response models.ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if response.Error != "Quota exceeded" {
+		t.Errorf("error = %q, want Quota exceeded", response.Error)
+	}
+	retryAfter, _ := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	if retryAfter < 1 || retryAfter > 24*60*60 {
+		t.Errorf("Retry-After = %d, want the time to midnight UTC", retryAfter)
+	}
+}
+
+func TestCeilSeconds(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want int
+	}{
+		{0, 0},
+		{-time.Second, 0},
+		{time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+	}
+	for _, tt := range tests {
+		if got := ceilSeconds(tt.in); got != tt.want {
+			t.Errorf("ceilSeconds(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
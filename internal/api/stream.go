@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// streamKeepalive is how often an idle stream sends a comment, so proxies
+// and clients do not time it out
+const streamKeepalive = 30 * time.Second
+
+// EventStream serves the change stream as Server-Sent Events. A client
+// resumes with the Last-Event-ID header, or ?last_event_id= for clients that
+// cannot set headers, and first receives the events it missed. When those
+// are no longer available it receives a stream.reset event instead and
+// should fetch current state again.
+func EventStream(broker *events.Broker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+
+		replay, resumable, stream, cancel := broker.Subscribe(lastEventID)
+		defer cancel()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+		c.Status(http.StatusOK)
+
+		if !resumable {
+			sse.Encode(c.Writer, sse.Event{
+				Event: models.StreamEventReset,
+				Data:  models.StreamResetEvent{Reason: "events after " + lastEventID + " are no longer available"},
+			})
+		}
+		for _, event := range replay {
+			writeStreamEvent(c, event)
+		}
+		c.Writer.Flush()
+
+		keepalive := time.NewTicker(streamKeepalive)
+		defer keepalive.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, ok := <-stream:
+				if !ok {
+					return
+				}
+				writeStreamEvent(c, event)
+				c.Writer.Flush()
+			case <-keepalive.C:
+				c.Writer.WriteString(": keepalive\n\n")
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// writeStreamEvent writes one event with its ID and JSON data
+func writeStreamEvent(c *gin.Context, event events.Event) {
+	sse.Encode(c.Writer, sse.Event{
+		Id:    event.ID,
+		Event: event.Type,
+		Data:  event.Data,
+	})
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// newStreamRouter serves the broker's stream at /events
+func newStreamRouter(t *testing.T, broker *events.Broker) *gin.Engine {
+	t.Helper()
+	return newTestRouter(t, func(router *gin.Engine) {
+		router.GET("/events", EventStream(broker))
+	})
+}
+
+// replayStream requests the stream with a request context that is already
+// done, so only the replayed events are written before the handler returns
+func replayStream(router *gin.Engine, path, lastEventID string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestEventStreamReplay(t *testing.T) {
+	broker := events.NewBroker(events.DefaultHistorySize)
+	router := newStreamRouter(t, broker)
+	first := broker.Publish(models.StreamEventProjectChanged, map[string]string{"name": "first"})
+	second := broker.Publish(models.StreamEventProjectChanged, map[string]string{"name": "second"})
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		contains []string
+		excludes []string
+	}{
+		{"new client", "/events", "", nil, []string{"event:"}},
+		{"header", "/events", first.ID, []string{"id:" + second.ID + "\n", "event:project.changed\n", `data:{"name":"second"}`}, []string{`"first"`}},
+		{"query", "/events?last_event_id=" + first.ID, "", []string{"id:" + second.ID + "\n"}, []string{`"first"`}},
+		{"lost position", "/events", "gone-1", []string{"event:stream.reset\n", "gone-1"}, []string{"id:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := replayStream(router, tt.path, tt.header)
+			if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "text/event-stream" {
+				t.Fatalf("status %d with Content-Type %q", recorder.Code, recorder.Header().Get("Content-Type"))
+			}
+			body := recorder.Body.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(body, want) {
+					t.Errorf("body %q lacks %q", body, want)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(body, unwanted) {
+					t.Errorf("body %q contains %q", body, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestEventStreamLive(t *testing.T) {
+	broker := events.NewBroker(events.DefaultHistorySize)
+	server := httptest.NewServer(newStreamRouter(t, broker))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer response.Body.Close()
+
+	// The handler has subscribed once the headers arrive
+	published := broker.Publish(models.StreamEventSummaryChanged, map[string]int{"dataset_version": 2})
+	broker.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	want := []string{"id:" + published.ID, "event:summary.changed", `data:{"dataset_version":2}`}
+	if len(lines) < len(want) {
+		t.Fatalf("stream = %q, want the published event", lines)
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("line %d = %q, want %q", i, lines[i], line)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/openapi"
+)
+
+func init() {
+	// Report binding failures by JSON field name rather than Go field name
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// validateSystemSpec checks a request's system against limits, returning
+// errors and warnings with field paths relative to the request body
+func validateSystemSpec(spec models.SystemSpec, limits models.SpecLimits) (errs []models.FieldError, warnings []models.FieldError) {
+	errs, warnings = models.ValidateSystemSpec(spec, limits)
+	return models.PrefixFields("system.", errs), models.PrefixFields("system.", warnings)
+}
+
+// bindingFieldErrors converts a request binding failure into per-field
+// errors. It returns nil when the failure is not tied to a field, such as
+// malformed JSON.
+func bindingFieldErrors(err error) []models.FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []models.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    models.RuleType,
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, jsonTypeName(typeErr.Type)),
+			Value:   typeErr.Value,
+		}}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]models.FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			// Drop the root struct name from the namespace
+			_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+			fields = append(fields, models.FieldError{
+				Field:   field,
+				Rule:    fieldErr.Tag(),
+				Message: fmt.Sprintf("%s failed the %q rule", field, fieldErr.Tag()),
+				Value:   fieldErr.Value(),
+			})
+		}
+		return fields
+	}
+
+	return nil
+}
+
+// jsonTypeName names a Go type the way a JSON client would see it
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// specConstraints expresses the SystemSpec rules as OpenAPI constraints
+func specConstraints(limits models.SpecLimits) []openapi.Constraint {
+	rules := limits.SystemSpecRules()
+	constraints := make([]openapi.Constraint, 0, len(rules))
+
+	for _, rule := range rules {
+		constraint := openapi.Constraint{
+			Property: rule.Field,
+			Required: rule.Required,
+			Enum:     rule.OneOf,
+		}
+		if rule.Range != nil {
+			lo, hi := float64(rule.Range.Min), float64(rule.Range.Max)
+			constraint.Minimum = &lo
+			constraint.Maximum = &hi
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	constraints = append(constraints,
+		countConstraint("gpus", limits.GPUs),
+		countConstraint("storage_devices", limits.StorageDevices),
+	)
+
+	return constraints
+}
+
+// countConstraint expresses a list length limit; a minimum of 0 is omitted
+func countConstraint(property string, limit models.Range) openapi.Constraint {
+	lo, hi := limit.Min, limit.Max
+	constraint := openapi.Constraint{Property: property, MaxItems: &hi}
+	if lo > 0 {
+		constraint.MinItems = &lo
+	}
+	return constraint
+}
+
+// gpuConstraints expresses the per-card GPU rules as OpenAPI constraints
+func gpuConstraints(limits models.SpecLimits) []openapi.Constraint {
+	lo, hi := float64(limits.GPUVRAMGB.Min), float64(limits.GPUVRAMGB.Max)
+	return []openapi.Constraint{{Property: "vram_gb", Required: true, Minimum: &lo, Maximum: &hi}}
+}
+
+// storageDeviceConstraints expresses the per-drive rules as OpenAPI constraints
+func storageDeviceConstraints(limits models.SpecLimits) []openapi.Constraint {
+	lo, hi := float64(1), float64(limits.StorageGB.Max)
+	return []openapi.Constraint{
+		{Property: "type", Required: true, Enum: models.StorageDeviceTypes},
+		{Property: "capacity_gb", Required: true, Minimum: &lo, Maximum: &hi},
+	}
+}
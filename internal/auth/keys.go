@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Scopes granted to API keys
+const (
+	ScopePredict = "predict" // POST /predict and saved profiles
+	ScopeRead    = "read"    // Project listing, health and docs
+	ScopeAdmin   = "admin"   // Administrative endpoints
+)
+
+// APIKey describes a configured API key and its limits
+type APIKey struct {
+	Name          string   `json:"name"`
+	Key           string   `json:"key,omitempty"`        // Plaintext key, convenient for local setups
+	KeyHash       string   `json:"key_sha256,omitempty"` // Hex SHA-256 of the key, preferred
+	Scopes        []string `json:"scopes"`
+	RatePerSecond float64  `json:"rate_per_second,omitempty"` // 0 uses the default limit
+	Burst         int      `json:"burst,omitempty"`           // 0 uses the default burst
+	DailyQuota    int      `json:"daily_quota,omitempty"`     // 0 means unlimited
+	Disabled      bool     `json:"disabled,omitempty"`
+}
+
+// HasScope reports whether the key grants scope. The admin scope implies all others.
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAdmin)
+}
+
+// KeyStore looks up API keys by their presented value
+type KeyStore interface {
+	Lookup(key string) (*APIKey, bool)
+	List() []APIKey
+}
+
+// MemoryKeyStore is a KeyStore backed by an in-memory list
+type MemoryKeyStore struct {
+	mu     sync.RWMutex
+	byHash map[string]*APIKey
+	keys   []APIKey
+}
+
+// NewMemoryKeyStore creates a key store from the given keys
+func NewMemoryKeyStore(keys []APIKey) (*MemoryKeyStore, error) {
+	store := &MemoryKeyStore{}
+	if err := store.Replace(keys); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// LoadKeyFile reads API keys from a JSON file of the form {"keys": [...]}
+func LoadKeyFile(path string) (*MemoryKeyStore, error) {
+	keys, err := ReadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMemoryKeyStore(keys)
+}
+
+// ReadKeyFile parses a JSON key file of the form {"keys": [...]} without
+// validating the keys
+func ReadKeyFile(path string) ([]APIKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API key file '%s': %w", path, err)
+	}
+
+	var file struct {
+		Keys []APIKey `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse API key file '%s': %w", path, err)
+	}
+	return file.Keys, nil
+}
+
+// Replace swaps the configured keys after validating them
+func (s *MemoryKeyStore) Replace(keys []APIKey) error {
+	byHash := make(map[string]*APIKey, len(keys))
+	names := make(map[string]bool, len(keys))
+
+	for i := range keys {
+		key := keys[i]
+		if key.Name == "" {
+			return fmt.Errorf("API key %d: name is required", i+1)
+		}
+		if names[key.Name] {
+			return fmt.Errorf("API key %q: duplicate name", key.Name)
+		}
+		names[key.Name] = true
+
+		hash := key.KeyHash
+		if hash == "" {
+			if key.Key == "" {
+				return fmt.Errorf("API key %q: key or key_sha256 is required", key.Name)
+			}
+			hash = HashKey(key.Key)
+		}
+
+		// Never keep plaintext keys around once hashed
+		key.Key = ""
+		key.KeyHash = hash
+		keys[i] = key
+		byHash[hash] = &keys[i]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byHash = byHash
+	s.keys = keys
+	return nil
+}
+
+// Lookup returns the enabled key matching the presented value
+func (s *MemoryKeyStore) Lookup(presented string) (*APIKey, bool) {
+	hash := HashKey(presented)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for stored, key := range s.byHash {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			if key.Disabled {
+				return nil, false
+			}
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// List returns the configured keys without secrets
+func (s *MemoryKeyStore) List() []APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]APIKey, len(s.keys))
+	for i, key := range s.keys {
+		key.Key = ""
+		key.KeyHash = ""
+		keys[i] = key
+	}
+	return keys
+}
+
+// HashKey returns the hex SHA-256 of an API key
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemoryKeyStoreLookup(t *testing.T) {
+	store, err := NewMemoryKeyStore([]APIKey{
+		{Name: "plain", Key: "secret-1", Scopes: []string{ScopeRead}},
+		{Name: "hashed", KeyHash: HashKey("secret-2"), Scopes: []string{ScopePredict}},
+		{Name: "off", Key: "secret-3", Disabled: true},
+	})
+	if err != nil {
+		t.Fatalf("NewMemoryKeyStore: %v", err)
+	}
+
+	tests := []struct {
+		presented string
+		want      string // Name of the matched key, empty for none
+	}{
+		{"secret-1", "plain"},
+		{"secret-2", "hashed"},
+		{"secret-3", ""},
+		{"secret-4", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		key, ok := store.Lookup(tt.presented)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("Lookup(%q) = %s, want no key", tt.presented, key.Name)
+			}
+			continue
+		}
+		if !ok || key.Name != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v, want %s", tt.presented, key, ok, tt.want)
+		}
+	}
+}
+
+func TestMemoryKeyStoreDropsPlaintext(t *testing.T) {
+	store, err := NewMemoryKeyStore([]APIKey{{Name: "plain", Key: "secret-1"}})
+	if err != nil {
+		t.Fatalf("NewMemoryKeyStore: %v", err)
+	}
+
+	key, _ := store.Lookup("secret-1")
+	if key.Key != "" || key.KeyHash != HashKey("secret-1") {
+		t.Errorf("stored key = %+v, want only the hash", key)
+	}
+	for _, listed := range store.List() {
+		if listed.Key != "" || listed.KeyHash != "" {
+			t.Errorf("List exposes secrets: %+v", listed)
+		}
+	}
+}
+
+func TestMemoryKeyStoreValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []APIKey
+		want string
+	}{
+		{"missing name", []APIKey{{Key: "k"}}, "name is required"},
+		{"duplicate name", []APIKey{{Name: "a", Key: "k1"}, {Name: "a", Key: "k2"}}, "duplicate name"},
+		{"missing key", []APIKey{{Name: "a"}}, "key or key_sha256 is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMemoryKeyStore(tt.keys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceKeepsOldKeysOnError(t *testing.T) {
+	store, err := NewMemoryKeyStore([]APIKey{{Name: "a", Key: "k1"}})
+	if err != nil {
+		t.Fatalf("NewMemoryKeyStore: %v", err)
+	}
+	if err := store.Replace([]APIKey{{Name: "b"}}); err == nil {
+		t.Fatal("Replace accepted a key without a secret")
+	}
+	if _, ok := store.Lookup("k1"); !ok {
+		t.Error("failed Replace dropped the existing keys")
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	reader := &APIKey{Scopes: []string{ScopeRead}}
+	admin := &APIKey{Scopes: []string{ScopeAdmin}}
+
+	if !reader.HasScope(ScopeRead) || reader.HasScope(ScopePredict) {
+		t.Error("reader scopes wrong")
+	}
+	if !admin.HasScope(ScopePredict) || !admin.HasScope(ScopeRead) {
+		t.Error("admin scope does not imply the others")
+	}
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+	content := `{"keys": [{"name": "ci", "key": "ci-secret", "scopes": ["predict"], "daily_quota": 100}]}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatalf("LoadKeyFile: %v", err)
+	}
+	key, ok := store.Lookup("ci-secret")
+	if !ok || key.DailyQuota != 100 || !key.HasScope(ScopePredict) {
+		t.Errorf("loaded key = %+v, %v", key, ok)
+	}
+
+	if _, err := LoadKeyFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("missing file accepted")
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeyFile(path); err == nil {
+		t.Error("malformed file accepted")
+	}
+}
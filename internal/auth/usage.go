@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// UsageTracker counts requests per API key and enforces daily quotas
+type UsageTracker struct {
+	mu    sync.Mutex
+	usage map[string]*keyUsage
+	now   func() time.Time
+}
+
+type keyUsage struct {
+	total         int64
+	rateLimited   int64
+	quotaExceeded int64
+	day           string
+	today         int
+	lastUsed      time.Time
+}
+
+// NewUsageTracker creates an empty usage tracker
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{
+		usage: make(map[string]*keyUsage),
+		now:   time.Now,
+	}
+}
+
+// Consume records a request for key and reports whether it fits within the
+// key's daily quota. Rejected requests are counted but do not use quota.
+func (t *UsageTracker) Consume(key *APIKey) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.get(key.Name)
+	now := t.now()
+	day := now.UTC().Format("2006-01-02")
+	if u.day != day {
+		u.day = day
+		u.today = 0
+	}
+
+	u.total++
+	u.lastUsed = now
+
+	if key.DailyQuota > 0 && u.today >= key.DailyQuota {
+		u.quotaExceeded++
+		return false
+	}
+
+	u.today++
+	return true
+}
+
+// RecordRateLimited counts a request for key rejected by the rate limiter
+func (t *UsageTracker) RecordRateLimited(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.get(name)
+	u.total++
+	u.rateLimited++
+	u.lastUsed = t.now()
+}
+
+// Snapshot returns usage for every configured key, ordered by name
+func (t *UsageTracker) Snapshot(keys []APIKey) []models.APIKeyUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	today := t.now().UTC().Format("2006-01-02")
+	result := make([]models.APIKeyUsage, 0, len(keys))
+
+	for _, key := range keys {
+		entry := models.APIKeyUsage{
+			Name:       key.Name,
+			Scopes:     key.Scopes,
+			DailyQuota: key.DailyQuota,
+			Disabled:   key.Disabled,
+		}
+
+		if u, ok := t.usage[key.Name]; ok {
+			entry.TotalRequests = u.total
+			entry.RateLimited = u.rateLimited
+			entry.QuotaExceeded = u.quotaExceeded
+			if u.day == today {
+				entry.RequestsToday = u.today
+			}
+			if !u.lastUsed.IsZero() {
+				lastUsed := u.lastUsed
+				entry.LastUsed = &lastUsed
+			}
+		}
+
+		result = append(result, entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func (t *UsageTracker) get(name string) *keyUsage {
+	u, ok := t.usage[name]
+	if !ok {
+		u = &keyUsage{}
+		t.usage[name] = u
+	}
+	return u
+}
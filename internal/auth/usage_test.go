@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageTrackerQuota(t *testing.T) {
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	tracker := NewUsageTracker()
+	tracker.now = func() time.Time { return now }
+	key := &APIKey{Name: "metered", DailyQuota: 2}
+
+	for i, want := range []bool{true, true, false} {
+		if got := tracker.Consume(key); got != want {
+			t.Errorf("request %d: Consume = %v, want %v", i+1, got, want)
+		}
+	}
+
+	// The quota resets at midnight UTC
+	now = now.Add(2 * time.Hour)
+	if !tracker.Consume(key) {
+		t.Error("quota not reset on the next day")
+	}
+
+	usage := tracker.Snapshot([]APIKey{*key})
+	if usage[0].TotalRequests != 4 || usage[0].QuotaExceeded != 1 || usage[0].RequestsToday != 1 {
+		t.Errorf("usage = %+v", usage[0])
+	}
+	if usage[0].LastUsed == nil || !usage[0].LastUsed.Equal(now) {
+		t.Errorf("last used = %v, want %v", usage[0].LastUsed, now)
+	}
+}
+
+func TestUsageTrackerUnlimited(t *testing.T) {
+	tracker := NewUsageTracker()
+	key := &APIKey{Name: "free"}
+	for i := 0; i < 100; i++ {
+		if !tracker.Consume(key) {
+			t.Fatalf("request %d rejected without a quota", i+1)
+		}
+	}
+}
+
+func TestUsageTrackerSnapshot(t *testing.T) {
+	tracker := NewUsageTracker()
+	tracker.RecordRateLimited("b")
+
+	usage := tracker.Snapshot([]APIKey{{Name: "b"}, {Name: "a", DailyQuota: 5}})
+	if len(usage) != 2 || usage[0].Name != "a" || usage[1].Name != "b" {
+		t.Fatalf("usage = %+v, want a then b", usage)
+	}
+	if usage[0].TotalRequests != 0 || usage[0].LastUsed != nil || usage[0].DailyQuota != 5 {
+		t.Errorf("unused key = %+v", usage[0])
+	}
+	if usage[1].TotalRequests != 1 || usage[1].RateLimited != 1 {
+		t.Errorf("rate limited key = %+v", usage[1])
+	}
+}
@@ -0,0 +1,88 @@
+package data
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// FieldChange is a single changed project field, by JSON name
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ProjectChange lists the changed fields of a project present in both datasets
+type ProjectChange struct {
+	Name   string        `json:"name"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// DatasetDiff describes how one dataset differs from another
+type DatasetDiff struct {
+	Added   []string        `json:"added"`
+	Removed []string        `json:"removed"`
+	Changed []ProjectChange `json:"changed"`
+}
+
+// Empty reports whether the datasets have the same projects and values
+func (d DatasetDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares two datasets, matching projects by name
+func Diff(old, updated []models.DePINProject) DatasetDiff {
+	diff := DatasetDiff{Added: []string{}, Removed: []string{}, Changed: []ProjectChange{}}
+
+	oldByName := projectsByName(old)
+	newByName := projectsByName(updated)
+
+	for name, oldProject := range oldByName {
+		newProject, ok := newByName[name]
+		if !ok {
+			diff.Removed = append(diff.Removed, name)
+			continue
+		}
+		if fields := diffProject(oldProject, newProject); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ProjectChange{Name: name, Fields: fields})
+		}
+	}
+	for name := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			diff.Added = append(diff.Added, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+	return diff
+}
+
+func projectsByName(projects []models.DePINProject) map[string]models.DePINProject {
+	byName := make(map[string]models.DePINProject, len(projects))
+	for _, project := range projects {
+		byName[project.Name] = project
+	}
+	return byName
+}
+
+// diffProject lists fields whose values differ, in struct order
+func diffProject(old, updated models.DePINProject) []FieldChange {
+	var changes []FieldChange
+
+	oldValue, newValue := reflect.ValueOf(old), reflect.ValueOf(updated)
+	for i := 0; i < oldValue.NumField(); i++ {
+		a, b := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if a == b {
+			continue
+		}
+
+		name, _, _ := strings.Cut(oldValue.Type().Field(i).Tag.Get("json"), ",")
+		changes = append(changes, FieldChange{Field: name, Old: a, New: b})
+	}
+	return changes
+}
@@ -0,0 +1,51 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+func TestDiff(t *testing.T) {
+	old := []models.DePINProject{
+		{Name: "Alpha", CPUCoresMin: 2, RAMGBMin: 4},
+		{Name: "Beta", CPUCoresMin: 4},
+		{Name: "Gamma", StorageType: "SSD"},
+	}
+	updated := []models.DePINProject{
+		{Name: "Gamma", StorageType: "SSD"},
+		{Name: "Alpha", CPUCoresMin: 4, RAMGBMin: 8},
+		{Name: "Delta"},
+	}
+
+	diff := Diff(old, updated)
+
+	if !reflect.DeepEqual(diff.Added, []string{"Delta"}) || !reflect.DeepEqual(diff.Removed, []string{"Beta"}) {
+		t.Errorf("Added = %v, Removed = %v", diff.Added, diff.Removed)
+	}
+
+	want := []ProjectChange{{Name: "Alpha", Fields: []FieldChange{
+		{Field: "cpu_cores_min", Old: 2, New: 4},
+		{Field: "ram_gb_min", Old: 4, New: 8},
+	}}}
+	if !reflect.DeepEqual(diff.Changed, want) {
+		t.Errorf("Changed = %+v, want %+v", diff.Changed, want)
+	}
+	if diff.Empty() {
+		t.Error("Empty() = true for differing datasets")
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	projects := []models.DePINProject{{Name: "Alpha", CPUCoresMin: 2}}
+
+	diff := Diff(projects, projects)
+	if !diff.Empty() {
+		t.Errorf("Diff of identical datasets = %+v", diff)
+	}
+	// Empty lists rather than nil, so JSON output shows []
+	if diff.Added == nil || diff.Removed == nil || diff.Changed == nil {
+		t.Errorf("Diff returned nil lists: %+v", diff)
+	}
+}
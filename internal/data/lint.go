@@ -0,0 +1,105 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// Lint issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// DefaultMaxSpecAge is how long project specifications stay fresh without review
+const DefaultMaxSpecAge = 365 * 24 * time.Hour
+
+// LintIssue is a semantic problem found in a project's specifications
+type LintIssue struct {
+	Project  string `json:"project"`
+	Field    string `json:"field"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s", i.Severity, i.Project, i.Field, i.Message)
+}
+
+// LintOptions configures Lint
+type LintOptions struct {
+	Now        time.Time     // Reference time for staleness, defaults to time.Now
+	MaxSpecAge time.Duration // Age after which last_updated is stale, defaults to DefaultMaxSpecAge
+}
+
+// Lint checks projects for values that parse but make no sense together
+func Lint(projects []models.DePINProject, opts LintOptions) []LintIssue {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.MaxSpecAge == 0 {
+		opts.MaxSpecAge = DefaultMaxSpecAge
+	}
+
+	var issues []LintIssue
+	for _, project := range projects {
+		issues = append(issues, lintProject(project, opts)...)
+	}
+	return issues
+}
+
+// lintProject checks a single project
+func lintProject(project models.DePINProject, opts LintOptions) []LintIssue {
+	var issues []LintIssue
+	add := func(severity, field, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{
+			Project:  project.Name,
+			Field:    field,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if project.RAMGBRecommended > 0 && project.RAMGBRecommended < project.RAMGBMin {
+		add(SeverityError, "ram_gb_recommended", "recommended RAM %dGB is below the minimum %dGB", project.RAMGBRecommended, project.RAMGBMin)
+	}
+
+	if project.EstimatedCostMin > project.EstimatedCostMax {
+		add(SeverityError, "estimated_monthly_cost_usd_min", "minimum cost $%d exceeds maximum cost $%d", project.EstimatedCostMin, project.EstimatedCostMax)
+	}
+
+	if project.GPUVRAMGBMin > 0 && !project.GPURequired {
+		add(SeverityError, "gpu_vram_gb_min", "%dGB VRAM minimum set but gpu_required is false", project.GPUVRAMGBMin)
+	}
+	if project.GPURequired && project.GPUVRAMGBMin == 0 {
+		add(SeverityWarning, "gpu_vram_gb_min", "GPU required but no VRAM minimum set")
+	}
+
+	if project.StorageType != "" && project.StorageType != "SSD" && project.StorageType != "Any" {
+		add(SeverityError, "storage_type", "unknown storage type %q, expected SSD or Any", project.StorageType)
+	}
+
+	for _, name := range strings.Split(project.SupportedOS, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(models.SupportedOperatingSystems, name) {
+			add(SeverityError, "supported_os", "unknown OS %q, expected one of %s", name, strings.Join(models.SupportedOperatingSystems, ", "))
+		}
+	}
+
+	switch updated, err := time.Parse(time.DateOnly, project.LastUpdated); {
+	case project.LastUpdated == "":
+		add(SeverityWarning, "last_updated", "no review date")
+	case err != nil:
+		add(SeverityError, "last_updated", "%q is not a YYYY-MM-DD date", project.LastUpdated)
+	case updated.After(opts.Now):
+		add(SeverityError, "last_updated", "%s is in the future", project.LastUpdated)
+	case opts.Now.Sub(updated) > opts.MaxSpecAge:
+		add(SeverityWarning, "last_updated", "specifications last reviewed %s, over %d days ago", project.LastUpdated, int(opts.MaxSpecAge.Hours()/24))
+	}
+
+	return issues
+}
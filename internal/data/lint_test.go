@@ -0,0 +1,66 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+func TestLint(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	valid := models.DePINProject{
+		Name:             "Valid",
+		RAMGBMin:         4,
+		RAMGBRecommended: 8,
+		StorageType:      "SSD",
+		SupportedOS:      "Linux, Windows",
+		EstimatedCostMin: 5,
+		EstimatedCostMax: 10,
+		LastUpdated:      "2025-01-01",
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(p *models.DePINProject)
+		field    string
+		severity string
+	}{
+		{"recommended below minimum", func(p *models.DePINProject) { p.RAMGBRecommended = 2 }, "ram_gb_recommended", SeverityError},
+		{"cost range inverted", func(p *models.DePINProject) { p.EstimatedCostMin = 20 }, "estimated_monthly_cost_usd_min", SeverityError},
+		{"VRAM without GPU", func(p *models.DePINProject) { p.GPUVRAMGBMin = 8 }, "gpu_vram_gb_min", SeverityError},
+		{"GPU without VRAM", func(p *models.DePINProject) { p.GPURequired = true }, "gpu_vram_gb_min", SeverityWarning},
+		{"unknown storage type", func(p *models.DePINProject) { p.StorageType = "Tape" }, "storage_type", SeverityError},
+		{"unknown OS", func(p *models.DePINProject) { p.SupportedOS = "Linux,BeOS" }, "supported_os", SeverityError},
+		{"no review date", func(p *models.DePINProject) { p.LastUpdated = "" }, "last_updated", SeverityWarning},
+		{"invalid date", func(p *models.DePINProject) { p.LastUpdated = "01/01/2025" }, "last_updated", SeverityError},
+		{"future date", func(p *models.DePINProject) { p.LastUpdated = "2025-07-01" }, "last_updated", SeverityError},
+		{"stale date", func(p *models.DePINProject) { p.LastUpdated = "2023-01-01" }, "last_updated", SeverityWarning},
+	}
+
+	if issues := Lint([]models.DePINProject{valid}, LintOptions{Now: now}); len(issues) != 0 {
+		t.Fatalf("valid project has issues: %v", issues)
+	}
+
+	for _, tt := range tests {
+		project := valid
+		tt.modify(&project)
+
+		issues := Lint([]models.DePINProject{project}, LintOptions{Now: now})
+		if len(issues) != 1 || issues[0].Field != tt.field || issues[0].Severity != tt.severity || issues[0].Project != "Valid" {
+			t.Errorf("%s: issues = %v, want one %s on %s", tt.name, issues, tt.severity, tt.field)
+		}
+	}
+}
+
+func TestLintMaxSpecAge(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	project := models.DePINProject{Name: "Recent", LastUpdated: "2025-04-01"}
+
+	if issues := Lint([]models.DePINProject{project}, LintOptions{Now: now}); len(issues) != 0 {
+		t.Errorf("default max age: issues = %v", issues)
+	}
+	if issues := Lint([]models.DePINProject{project}, LintOptions{Now: now, MaxSpecAge: 30 * 24 * time.Hour}); len(issues) != 1 {
+		t.Errorf("30 day max age: issues = %v, want a stale warning", issues)
+	}
+}
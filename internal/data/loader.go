@@ -2,18 +2,21 @@ package data
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
 
-	"github/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/models"
 )
 
 // Loader handles loading DePIN project data from CSV files
 type Loader struct {
 	filePath string
+	strict   bool
 }
 
 // NewLoader creates a new data loader
@@ -23,6 +26,15 @@ func NewLoader(filePath string) *Loader {
 	}
 }
 
+// NewStrictLoader creates a loader that fails on any invalid line or
+// unparseable value instead of skipping or defaulting it
+func NewStrictLoader(filePath string) *Loader {
+	return &Loader{
+		filePath: filePath,
+		strict:   true,
+	}
+}
+
 // LoadDePINSpecs loads DePIN project specifications from CSV file
 func (l *Loader) LoadDePINSpecs() ([]models.DePINProject, error) {
 	file, err := os.Open(l.filePath)
@@ -31,7 +43,13 @@ func (l *Loader) LoadDePINSpecs() ([]models.DePINProject, error) {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
+	return l.ParseDePINSpecs(file)
+}
+
+// ParseDePINSpecs parses DePIN project specifications from CSV content, such
+// as an uploaded candidate dataset. The loader's path is used in messages.
+func (l *Loader) ParseDePINSpecs(r io.Reader) ([]models.DePINProject, error) {
+	reader := csv.NewReader(r)
 	reader.FieldsPerRecord = -1 // Allow variable number of fields
 
 	// Read header to create field mapping
@@ -43,6 +61,8 @@ func (l *Loader) LoadDePINSpecs() ([]models.DePINProject, error) {
 	fieldMap := createFieldMap(header)
 
 	var projects []models.DePINProject
+	var lineErrs []error
+	seen := make(map[string]int)
 
 	// Read data rows
 	lineNumber := 2 // Start from line 2 (after header)
@@ -56,21 +76,35 @@ func (l *Loader) LoadDePINSpecs() ([]models.DePINProject, error) {
 		}
 
 		project, err := l.parseProjectRecord(record, fieldMap, lineNumber)
+		if err == nil && l.strict && seen[project.Name] > 0 {
+			err = fmt.Errorf("duplicate project %q, first defined on line %d", project.Name, seen[project.Name])
+		}
 		if err != nil {
-			// Log warning but continue processing
-			fmt.Printf("Warning: Failed to parse line %d: %v\n", lineNumber, err)
+			if l.strict {
+				lineErrs = append(lineErrs, fmt.Errorf("line %d: %w", lineNumber, err))
+			} else {
+				// Log warning but continue processing
+				slog.Warn("Skipping invalid CSV line", "path", l.filePath, "line", lineNumber, "error", err)
+			}
 			lineNumber++
 			continue
 		}
 
+		seen[project.Name] = lineNumber
 		projects = append(projects, project)
 		lineNumber++
 	}
 
+	if len(lineErrs) > 0 {
+		return nil, fmt.Errorf("invalid CSV file '%s': %w", l.filePath, errors.Join(lineErrs...))
+	}
+
 	if len(projects) == 0 {
 		return nil, fmt.Errorf("no valid projects found in CSV file")
 	}
 
+	slog.Debug("Parsed CSV file", "path", l.filePath, "projects", len(projects), "lines", lineNumber-2)
+
 	return projects, nil
 }
 
@@ -89,6 +123,23 @@ func createFieldMap(header []string) map[string]int {
 func (l *Loader) parseProjectRecord(record []string, fieldMap map[string]int, lineNumber int) (models.DePINProject, error) {
 	project := models.DePINProject{}
 
+	// In strict mode, values that do not parse are errors rather than zero
+	var parseErrs []error
+	getIntField := func(record []string, fieldMap map[string]int, fieldNames ...string) int {
+		value, err := parseIntField(record, fieldMap, fieldNames...)
+		if err != nil && l.strict {
+			parseErrs = append(parseErrs, err)
+		}
+		return value
+	}
+	getBoolField := func(record []string, fieldMap map[string]int, fieldNames ...string) bool {
+		value, err := parseBoolField(record, fieldMap, fieldNames...)
+		if err != nil && l.strict {
+			parseErrs = append(parseErrs, err)
+		}
+		return value
+	}
+
 	// Project name (required)
 	project.Name = getStringField(record, fieldMap, "project_name", "name")
 	if project.Name == "" {
@@ -133,37 +184,29 @@ func (l *Loader) parseProjectRecord(record []string, fieldMap map[string]int, li
 	// Description
 	project.Description = getStringField(record, fieldMap, "description", "additional_requirements")
 
+	// Last review date of the specifications
+	project.LastUpdated = getStringField(record, fieldMap, "last_updated")
+
+	if len(parseErrs) > 0 {
+		messages := make([]string, len(parseErrs))
+		for i, err := range parseErrs {
+			messages[i] = err.Error()
+		}
+		return project, errors.New(strings.Join(messages, "; "))
+	}
+
 	// Validate required fields
-	if err := l.validateProject(project); err != nil {
+	if err := ValidateProject(project); err != nil {
 		return project, fmt.Errorf("validation failed: %w", err)
 	}
+	ApplyProjectDefaults(&project)
 
 	return project, nil
 }
 
-// validateProject validates that a project has required fields and sensible values
-func (l *Loader) validateProject(project models.DePINProject) error {
-	if project.Name == "" {
-		return fmt.Errorf("project name is required")
-	}
-
-	if project.CPUCoresMin < 0 || project.CPUCoresMin > 64 {
-		return fmt.Errorf("invalid CPU cores minimum: %d", project.CPUCoresMin)
-	}
-
-	if project.RAMGBMin < 0 || project.RAMGBMin > 1024 {
-		return fmt.Errorf("invalid RAM minimum: %d", project.RAMGBMin)
-	}
-
-	if project.StorageGBMin < 0 || project.StorageGBMin > 100000 {
-		return fmt.Errorf("invalid storage minimum: %d", project.StorageGBMin)
-	}
-
-	if project.NetworkMbpsMin < 0 || project.NetworkMbpsMin > 100000 {
-		return fmt.Errorf("invalid network speed minimum: %d", project.NetworkMbpsMin)
-	}
-
-	// Set defaults for missing optional fields
+// ApplyProjectDefaults fills in missing optional fields. It is applied to
+// every loaded line and to admin edits.
+func ApplyProjectDefaults(project *models.DePINProject) {
 	if project.Type == "" {
 		project.Type = "Unknown"
 	}
@@ -189,6 +232,30 @@ func (l *Loader) validateProject(project models.DePINProject) error {
 	if project.SupportedOS == "" {
 		project.SupportedOS = "Linux,Windows,macOS"
 	}
+}
+
+// ValidateProject checks that a project has a name and requirements within
+// sensible bounds. It is applied to every loaded line and to admin edits.
+func ValidateProject(project models.DePINProject) error {
+	if project.Name == "" {
+		return fmt.Errorf("project name is required")
+	}
+
+	if project.CPUCoresMin < 0 || project.CPUCoresMin > 64 {
+		return fmt.Errorf("invalid CPU cores minimum: %d", project.CPUCoresMin)
+	}
+
+	if project.RAMGBMin < 0 || project.RAMGBMin > 1024 {
+		return fmt.Errorf("invalid RAM minimum: %d", project.RAMGBMin)
+	}
+
+	if project.StorageGBMin < 0 || project.StorageGBMin > 100000 {
+		return fmt.Errorf("invalid storage minimum: %d", project.StorageGBMin)
+	}
+
+	if project.NetworkMbpsMin < 0 || project.NetworkMbpsMin > 100000 {
+		return fmt.Errorf("invalid network speed minimum: %d", project.NetworkMbpsMin)
+	}
 
 	return nil
 }
@@ -207,26 +274,45 @@ func getStringField(record []string, fieldMap map[string]int, fieldNames ...stri
 	return ""
 }
 
-func getIntField(record []string, fieldMap map[string]int, fieldNames ...string) int {
+// parseIntField returns the first non-empty field that parses as an integer,
+// or 0. Unparseable values are skipped in favor of later fields and
+// reported by the returned error, so only strict loading rejects them.
+func parseIntField(record []string, fieldMap map[string]int, fieldNames ...string) (int, error) {
+	var parseErr error
 	for _, fieldName := range fieldNames {
 		if idx, ok := fieldMap[fieldName]; ok && idx < len(record) {
 			value := strings.TrimSpace(record[idx])
-			if value != "" {
-				if intVal, err := strconv.Atoi(value); err == nil {
-					return intVal
+			if value == "" {
+				continue
+			}
+			intVal, err := strconv.Atoi(value)
+			if err != nil {
+				if parseErr == nil {
+					parseErr = fmt.Errorf("%s: %q is not an integer", fieldName, value)
 				}
+				continue
 			}
+			return intVal, parseErr
 		}
 	}
-	return 0
+	return 0, parseErr
 }
 
-func getBoolField(record []string, fieldMap map[string]int, fieldNames ...string) bool {
+// parseBoolField returns the first present boolean field, or false. Values
+// other than TRUE/FALSE, 1/0, YES/NO and Y/N yield false and an error.
+func parseBoolField(record []string, fieldMap map[string]int, fieldNames ...string) (bool, error) {
 	for _, fieldName := range fieldNames {
 		if idx, ok := fieldMap[fieldName]; ok && idx < len(record) {
 			value := strings.ToUpper(strings.TrimSpace(record[idx]))
-			return value == "TRUE" || value == "1" || value == "YES" || value == "Y"
+			switch value {
+			case "TRUE", "1", "YES", "Y":
+				return true, nil
+			case "FALSE", "0", "NO", "N", "":
+				return false, nil
+			default:
+				return false, fmt.Errorf("%s: %q is not a boolean", fieldName, record[idx])
+			}
 		}
 	}
-	return false
+	return false, nil
 }
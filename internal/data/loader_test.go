@@ -0,0 +1,93 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+const testHeader = "project_name,cpu_cores_min,ram_gb_min,storage_gb_min,gpu_required,network_speed_mbps_min\n"
+
+func TestParseDePINSpecs(t *testing.T) {
+	projects, err := NewLoader("test.csv").ParseDePINSpecs(strings.NewReader(testHeader +
+		"Alpha,2,4,100,FALSE,20\n" +
+		"Beta,4,8,500,yes,100\n"))
+	if err != nil {
+		t.Fatalf("ParseDePINSpecs: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2", len(projects))
+	}
+	if beta := projects[1]; beta.Name != "Beta" || beta.CPUCoresMin != 4 || !beta.GPURequired || beta.NetworkMbpsMin != 100 {
+		t.Errorf("Beta = %+v", beta)
+	}
+}
+
+func TestParseDePINSpecsLenientSkipsInvalidLines(t *testing.T) {
+	projects, err := NewLoader("test.csv").ParseDePINSpecs(strings.NewReader(testHeader +
+		"Alpha,2,4,100,FALSE,20\n" +
+		",1,1,1,FALSE,1\n" + // no name
+		"Gamma,999,4,100,FALSE,20\n")) // out of range
+	if err != nil {
+		t.Fatalf("ParseDePINSpecs: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Name != "Alpha" {
+		t.Errorf("projects = %+v, want only Alpha", projects)
+	}
+}
+
+func TestParseDePINSpecsStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    string
+		message string
+	}{
+		{"unparseable integer", "Alpha,two,4,100,FALSE,20\n", `cpu_cores_min: "two" is not an integer`},
+		{"unparseable boolean", "Alpha,2,4,100,maybe,20\n", `gpu_required: "maybe" is not a boolean`},
+		{"duplicate project", "Alpha,2,4,100,FALSE,20\nAlpha,2,4,100,FALSE,20\n", "line 3: duplicate project \"Alpha\", first defined on line 2"},
+		{"out of range", "Alpha,2,4000,100,FALSE,20\n", "invalid RAM minimum: 4000"},
+	}
+
+	for _, tt := range tests {
+		_, err := NewStrictLoader("test.csv").ParseDePINSpecs(strings.NewReader(testHeader + tt.rows))
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: error = %v, want it to mention %q", tt.name, err, tt.message)
+		}
+	}
+}
+
+func TestParseDePINSpecsFallsBackToAliasColumn(t *testing.T) {
+	csv := "project_name,cpu_cores_min,ram_gb_min,ram_min_gb\nAlpha,2,lots,8\n"
+
+	projects, err := NewLoader("test.csv").ParseDePINSpecs(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseDePINSpecs: %v", err)
+	}
+	if len(projects) != 1 || projects[0].RAMGBMin != 8 {
+		t.Errorf("projects = %+v, want RAM minimum 8 from ram_min_gb", projects)
+	}
+
+	_, err = NewStrictLoader("test.csv").ParseDePINSpecs(strings.NewReader(csv))
+	if err == nil || !strings.Contains(err.Error(), `ram_gb_min: "lots" is not an integer`) {
+		t.Errorf("strict error = %v, want the unparseable ram_gb_min", err)
+	}
+}
+
+func TestParseDePINSpecsEmpty(t *testing.T) {
+	if _, err := NewLoader("test.csv").ParseDePINSpecs(strings.NewReader(testHeader)); err == nil {
+		t.Error("ParseDePINSpecs accepted a file without projects")
+	}
+}
+
+func TestParseDePINSpecsAppliesDefaults(t *testing.T) {
+	projects, err := NewLoader("test.csv").ParseDePINSpecs(strings.NewReader(
+		"project_name,estimated_monthly_cost_usd_max\nAlpha,150\n"))
+	if err != nil {
+		t.Fatalf("ParseDePINSpecs: %v", err)
+	}
+
+	alpha := projects[0]
+	if alpha.Type != "Unknown" || alpha.NodeType != "Standard" || alpha.StorageType != "Any" ||
+		alpha.CostCategory != "High" || alpha.SupportedOS != "Linux,Windows,macOS" {
+		t.Errorf("Alpha = %+v, want defaults for the missing fields", alpha)
+	}
+}
@@ -0,0 +1,39 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// LoadReferenceSystems reads named SystemSpec profiles from a JSON file of the
+// form {"systems": [...]}, validating each against limits
+func LoadReferenceSystems(path string, limits models.SpecLimits) ([]models.ReferenceSystem, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reference systems '%s': %w", path, err)
+	}
+
+	var file struct {
+		Systems []models.ReferenceSystem `json:"systems"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse reference systems '%s': %w", path, err)
+	}
+
+	names := make(map[string]bool, len(file.Systems))
+	for _, system := range file.Systems {
+		if system.Name == "" || names[system.Name] {
+			return nil, fmt.Errorf("reference system %q: names must be unique and non-empty", system.Name)
+		}
+		names[system.Name] = true
+
+		if errs, _ := models.ValidateSystemSpec(system.System, limits); len(errs) > 0 {
+			return nil, fmt.Errorf("reference system %q: %s", system.Name, errs[0].Message)
+		}
+	}
+
+	return file.Systems, nil
+}
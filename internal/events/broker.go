@@ -0,0 +1,142 @@
+// Package events fans out change notifications to Server-Sent Events
+// subscribers and keeps recent events so clients can resume after a
+// disconnect.
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is how many recent events are kept for resuming clients
+const DefaultHistorySize = 1000
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is disconnected; it can resume with its last event ID
+const subscriberBuffer = 64
+
+// Event is a published change. IDs have the form "<epoch>-<sequence>", where
+// epoch identifies the broker instance, so IDs from before a restart are
+// recognized as not resumable.
+type Event struct {
+	ID   string
+	Type string
+	Data interface{}
+}
+
+// Broker publishes events to subscribers
+type Broker struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	history     []Event // Oldest first, at most historySize
+	historySize int
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// NewBroker creates a broker keeping the last historySize events
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: max(historySize, 1),
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish assigns the event the next ID, keeps it for resuming clients and
+// sends it to every subscriber. Subscribers that fall too far behind are
+// disconnected.
+func (b *Broker) Publish(eventType string, data interface{}) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event := Event{
+		ID:   fmt.Sprintf("%s-%d", b.epoch, b.seq),
+		Type: eventType,
+		Data: data,
+	}
+
+	b.history = append(b.history, event)
+	if excess := len(b.history) - b.historySize; excess > 0 {
+		b.history = b.history[excess:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return event
+}
+
+// Subscribe registers a subscriber. With the ID of the last event a client
+// received, the events published since are returned for replay; resumable
+// is false when those are no longer kept or the ID is from another broker
+// instance. The channel is closed by cancel, Close or when the subscriber
+// falls behind.
+func (b *Broker) Subscribe(lastEventID string) (replay []Event, resumable bool, events <-chan Event, cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	if b.closed {
+		close(ch)
+		return nil, true, ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	replay, resumable = b.since(lastEventID)
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return replay, resumable, ch, cancel
+}
+
+// since returns the kept events after lastEventID
+func (b *Broker) since(lastEventID string) ([]Event, bool) {
+	if lastEventID == "" {
+		return nil, true
+	}
+
+	epoch, value, found := strings.Cut(lastEventID, "-")
+	seq, err := strconv.ParseUint(value, 10, 64)
+	if !found || err != nil || epoch != b.epoch || seq > b.seq {
+		return nil, false
+	}
+
+	// The oldest kept event must directly follow the client's last one
+	if len(b.history) > 0 {
+		oldest := b.seq - uint64(len(b.history)) + 1
+		if seq+1 < oldest {
+			return nil, false
+		}
+		return append([]Event(nil), b.history[len(b.history)-int(b.seq-seq):]...), true
+	}
+	return nil, seq == b.seq
+}
+
+// Close disconnects every subscriber, e.g. at shutdown so open streams do
+// not hold it up. Later subscribers get a closed channel.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
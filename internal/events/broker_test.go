@@ -0,0 +1,127 @@
+package events
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// eventIDs lists the IDs of events
+func eventIDs(events []Event) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+// publishN publishes n events and returns them
+func publishN(broker *Broker, n int) []Event {
+	published := make([]Event, n)
+	for i := range published {
+		published[i] = broker.Publish("test", i)
+	}
+	return published
+}
+
+func TestPublishDeliversToSubscribers(t *testing.T) {
+	broker := NewBroker(DefaultHistorySize)
+	_, _, first, cancelFirst := broker.Subscribe("")
+	defer cancelFirst()
+	_, _, second, cancelSecond := broker.Subscribe("")
+	defer cancelSecond()
+
+	published := broker.Publish("project.changed", "data")
+	if published.Type != "project.changed" || published.Data != "data" {
+		t.Errorf("published = %+v", published)
+	}
+	for i, events := range []<-chan Event{first, second} {
+		if got := <-events; got.ID != published.ID {
+			t.Errorf("subscriber %d got %q, want %q", i, got.ID, published.ID)
+		}
+	}
+	if next := broker.Publish("test", nil); next.ID == published.ID {
+		t.Errorf("IDs repeat: %q", next.ID)
+	}
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	broker := NewBroker(2)
+	published := publishN(broker, 5)
+	other := NewBroker(2)
+	publishN(other, 5)
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		resumable   bool
+		replay      []Event
+	}{
+		{"new client", "", true, nil},
+		{"up to date", published[4].ID, true, nil},
+		{"missed kept events", published[2].ID, true, published[3:]},
+		{"missed dropped events", published[1].ID, false, nil},
+		{"other broker", other.Publish("test", nil).ID, false, nil},
+		{"future event", fmt.Sprintf("%s-%d", broker.epoch, 9), false, nil},
+		{"malformed", "not-an-id", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, resumable, _, cancel := broker.Subscribe(tt.lastEventID)
+			defer cancel()
+			if resumable != tt.resumable {
+				t.Errorf("resumable = %v, want %v", resumable, tt.resumable)
+			}
+			if !slices.Equal(eventIDs(replay), eventIDs(tt.replay)) {
+				t.Errorf("replay = %v, want %v", eventIDs(replay), eventIDs(tt.replay))
+			}
+		})
+	}
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	broker := NewBroker(DefaultHistorySize)
+	_, _, events, cancel := broker.Subscribe("")
+	defer cancel()
+
+	published := publishN(broker, subscriberBuffer+1)
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("received %d events before disconnect, want %d", received, subscriberBuffer)
+	}
+
+	// The dropped subscriber can resume from its last event
+	replay, resumable, _, cancelResume := broker.Subscribe(published[received-1].ID)
+	defer cancelResume()
+	if !resumable || len(replay) != 1 || replay[0].ID != published[subscriberBuffer].ID {
+		t.Errorf("resume = %v, %v, want the one missed event", eventIDs(replay), resumable)
+	}
+}
+
+func TestCancelAndClose(t *testing.T) {
+	broker := NewBroker(DefaultHistorySize)
+
+	_, _, cancelled, cancel := broker.Subscribe("")
+	cancel()
+	cancel() // Cancelling twice is harmless
+	if _, ok := <-cancelled; ok {
+		t.Error("cancelled subscriber still open")
+	}
+
+	_, _, open, _ := broker.Subscribe("")
+	broker.Close()
+	if _, ok := <-open; ok {
+		t.Error("subscriber open after Close")
+	}
+
+	_, _, late, cancelLate := broker.Subscribe("")
+	defer cancelLate()
+	if _, ok := <-late; ok {
+		t.Error("subscriber after Close got an open channel")
+	}
+	broker.Publish("test", nil) // Publishing after Close does not panic
+}
@@ -0,0 +1,361 @@
+// Package graphqlapi defines a GraphQL schema over the CompatibilityService,
+// letting clients select the project and prediction fields they need and
+// combine queries in one request. Field names match the REST JSON.
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// MaxPredictionsPerRequest bounds the predict fields, including aliases, one
+// request may resolve
+const MaxPredictionsPerRequest = 10
+
+// requestContextKey is the context key holding the *requestState
+type requestContextKey struct{}
+
+// requestState is the caller, default locale, rate limit charge and
+// prediction count of one request
+type requestState struct {
+	key         *auth.APIKey
+	locale      string
+	charge      func() error
+	predictions atomic.Int32
+}
+
+// WithRequest prepares ctx for executing one request on behalf of key, nil
+// for anonymous callers. locale is used by predictions without a locale
+// argument, e.g. negotiated from Accept-Language. The request itself pays for
+// its first prediction; charge, when set, is called for each further one and
+// its error fails that prediction.
+func WithRequest(ctx context.Context, key *auth.APIKey, locale string, charge func() error) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, &requestState{key: key, locale: locale, charge: charge})
+}
+
+// Error is a GraphQL error with a machine-readable code and, for invalid
+// input, the failed fields, reported in the error's extensions
+type Error struct {
+	Code       string
+	Message    string
+	Details    []models.FieldError
+	RetryAfter time.Duration // When to retry a RATE_LIMITED field
+}
+
+// Error codes reported in error extensions
+const (
+	CodeBadUserInput  = "BAD_USER_INPUT"
+	CodeNotFound      = "NOT_FOUND"
+	CodeForbidden     = "FORBIDDEN"
+	CodeLimitExceeded = "LIMIT_EXCEEDED"
+	CodeRateLimited   = "RATE_LIMITED"
+	CodeInternal      = "INTERNAL"
+)
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.Code}
+	if len(e.Details) > 0 {
+		extensions["details"] = e.Details
+	}
+	if e.RetryAfter > 0 {
+		extensions["retry_after"] = int(math.Ceil(e.RetryAfter.Seconds()))
+	}
+	return extensions
+}
+
+// count is one entry of a grouped project count
+type count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// projectList is the result of the projects query
+type projectList struct {
+	Projects         []models.DePINProject `json:"projects"`
+	Total            int                   `json:"total"`
+	DatasetVersion   int                   `json:"dataset_version"`
+	DatasetCreatedAt time.Time             `json:"dataset_created_at"`
+}
+
+// projectStats is the result of the summary query
+type projectStats struct {
+	Total            int       `json:"total"`
+	ByType           []count   `json:"by_type"`
+	ByCostCategory   []count   `json:"by_cost_category"`
+	HomeFriendly     int       `json:"home_friendly"`
+	GPURequired      int       `json:"gpu_required"`
+	DatasetVersion   int       `json:"dataset_version"`
+	DatasetCreatedAt time.Time `json:"dataset_created_at"`
+}
+
+// projectFilter narrows the projects and summary queries. Unset fields match
+// every project.
+type projectFilter struct {
+	Name         string `json:"name"` // Case-insensitive substring
+	Type         string `json:"type"`
+	CostCategory string `json:"cost_category"`
+	OS           string `json:"os"` // Listed in supported_os
+	HomeFriendly *bool  `json:"home_friendly"`
+	GPURequired  *bool  `json:"gpu_required"`
+}
+
+// matches reports whether project passes the filter
+func (f projectFilter) matches(project models.DePINProject) bool {
+	switch {
+	case f.Name != "" && !strings.Contains(strings.ToLower(project.Name), strings.ToLower(f.Name)):
+		return false
+	case f.Type != "" && !strings.EqualFold(project.Type, f.Type):
+		return false
+	case f.CostCategory != "" && !strings.EqualFold(project.CostCategory, f.CostCategory):
+		return false
+	case f.OS != "" && !supportsOS(project, f.OS):
+		return false
+	case f.HomeFriendly != nil && project.HomeFriendly != *f.HomeFriendly:
+		return false
+	case f.GPURequired != nil && project.GPURequired != *f.GPURequired:
+		return false
+	}
+	return true
+}
+
+func supportsOS(project models.DePINProject, os string) bool {
+	for _, supported := range strings.Split(project.SupportedOS, ",") {
+		if strings.EqualFold(strings.TrimSpace(supported), os) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolver resolves the query fields
+type resolver struct {
+	compatibilityService *service.CompatibilityService
+	metrics              *metrics.Metrics
+	limits               models.SpecLimits
+}
+
+// NewSchema builds the GraphQL schema backed by compatibilityService
+func NewSchema(compatibilityService *service.CompatibilityService, m *metrics.Metrics, limits models.SpecLimits) (graphql.Schema, error) {
+	r := &resolver{
+		compatibilityService: compatibilityService,
+		metrics:              m,
+		limits:               limits,
+	}
+
+	datasetArgs := graphql.FieldConfigArgument{
+		"dataset_version": &graphql.ArgumentConfig{Type: graphql.Int, Description: "Past dataset version to query"},
+		"as_of":           &graphql.ArgumentConfig{Type: graphql.String, Description: "Query the dataset live at this RFC 3339 time or YYYY-MM-DD date"},
+	}
+	filterArgs := graphql.FieldConfigArgument{
+		"filter": &graphql.ArgumentConfig{Type: projectFilterInput},
+	}
+	for name, arg := range datasetArgs {
+		filterArgs[name] = arg
+	}
+	predictArgs := graphql.FieldConfigArgument{
+		"system":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(systemInput)},
+		"locale":     &graphql.ArgumentConfig{Type: graphql.String, Description: "Message locale, e.g. \"es\"; defaults to English"},
+		"plain_text": &graphql.ArgumentConfig{Type: graphql.Boolean, Description: "Strip emojis from messages"},
+	}
+	for name, arg := range datasetArgs {
+		predictArgs[name] = arg
+	}
+
+	// Root fields are nullable so one failing field leaves the others' data
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"projects": &graphql.Field{
+				Type:        projectListType,
+				Description: "Projects of the live or a past dataset, optionally filtered",
+				Args:        filterArgs,
+				Resolve:     r.projects,
+			},
+			"summary": &graphql.Field{
+				Type:        projectStatsType,
+				Description: "Project counts of the live or a past dataset, optionally filtered",
+				Args:        filterArgs,
+				Resolve:     r.summary,
+			},
+			"predict": &graphql.Field{
+				Type:        predictionType,
+				Description: "Compatibility of a system with every project; needs the predict scope",
+				Args:        predictArgs,
+				Resolve:     r.predict,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func (r *resolver) projects(p graphql.ResolveParams) (interface{}, error) {
+	projects, version, err := r.filteredProjects(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return projectList{
+		Projects:         projects,
+		Total:            len(projects),
+		DatasetVersion:   version.Version,
+		DatasetCreatedAt: version.CreatedAt,
+	}, nil
+}
+
+func (r *resolver) summary(p graphql.ResolveParams) (interface{}, error) {
+	projects, version, err := r.filteredProjects(p)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := service.SummarizeProjects(projects)
+	return projectStats{
+		Total:            len(projects),
+		ByType:           sortedCounts(summary.ByType),
+		ByCostCategory:   sortedCounts(summary.ByCostCategory),
+		HomeFriendly:     summary.HomeFriendly,
+		GPURequired:      summary.GPURequired,
+		DatasetVersion:   version.Version,
+		DatasetCreatedAt: version.CreatedAt,
+	}, nil
+}
+
+func (r *resolver) predict(p graphql.ResolveParams) (interface{}, error) {
+	state, _ := p.Context.Value(requestContextKey{}).(*requestState)
+	if state != nil {
+		if state.key != nil && !state.key.HasScope(auth.ScopePredict) {
+			return nil, &Error{Code: CodeForbidden, Message: fmt.Sprintf("API key '%s' lacks the '%s' scope", state.key.Name, auth.ScopePredict)}
+		}
+		n := state.predictions.Add(1)
+		if n > MaxPredictionsPerRequest {
+			return nil, &Error{Code: CodeLimitExceeded, Message: fmt.Sprintf("At most %d predictions may be requested at once", MaxPredictionsPerRequest)}
+		}
+		if n > 1 && state.charge != nil {
+			if err := state.charge(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var system models.SystemSpec
+	if err := decodeArg(p.Args["system"], &system); err != nil {
+		return nil, &Error{Code: CodeBadUserInput, Message: "Invalid system: " + err.Error()}
+	}
+	fieldErrs, warnings := models.ValidateSystemSpec(system, r.limits)
+	if len(fieldErrs) > 0 {
+		return nil, &Error{
+			Code:    CodeBadUserInput,
+			Message: fmt.Sprintf("Invalid system specifications: %d field(s) failed validation", len(fieldErrs)),
+			Details: models.PrefixFields("system.", fieldErrs),
+		}
+	}
+
+	selector, err := datasetSelector(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	locale, _ := p.Args["locale"].(string)
+	if locale == "" && state != nil {
+		locale = state.locale
+	}
+	plainText, _ := p.Args["plain_text"].(bool)
+	result, err := r.compatibilityService.PredictCompatibilityWithOptions(p.Context, system, service.PredictOptions{
+		Locale:    locale,
+		PlainText: plainText,
+		Dataset:   selector,
+	})
+	if errors.Is(err, service.ErrDatasetVersionNotFound) {
+		return nil, &Error{Code: CodeNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return nil, &Error{Code: CodeInternal, Message: "Prediction failed: " + err.Error()}
+	}
+
+	r.metrics.ObservePrediction(result)
+	result.InputWarnings = models.PrefixFields("system.", warnings)
+	return result, nil
+}
+
+// filteredProjects returns the projects of the selected dataset that pass
+// the filter argument
+func (r *resolver) filteredProjects(p graphql.ResolveParams) ([]models.DePINProject, models.DatasetVersion, error) {
+	var filter projectFilter
+	if err := decodeArg(p.Args["filter"], &filter); err != nil {
+		return nil, models.DatasetVersion{}, &Error{Code: CodeBadUserInput, Message: "Invalid filter: " + err.Error()}
+	}
+
+	selector, err := datasetSelector(p.Args)
+	if err != nil {
+		return nil, models.DatasetVersion{}, err
+	}
+
+	projects, version, err := r.compatibilityService.ProjectsAt(selector)
+	if err != nil {
+		return nil, models.DatasetVersion{}, &Error{Code: CodeNotFound, Message: err.Error()}
+	}
+
+	matched := make([]models.DePINProject, 0, len(projects))
+	for _, project := range projects {
+		if filter.matches(project) {
+			matched = append(matched, project)
+		}
+	}
+	return matched, version, nil
+}
+
+// datasetSelector reads the dataset_version and as_of arguments, rejecting
+// negative versions and requests setting both
+func datasetSelector(args map[string]interface{}) (service.DatasetSelector, error) {
+	version, _ := args["dataset_version"].(int)
+	asOf, _ := args["as_of"].(string)
+
+	selector, err := service.ParseDatasetSelector(version, asOf)
+	if err != nil {
+		return service.DatasetSelector{}, &Error{Code: CodeBadUserInput, Message: err.Error()}
+	}
+	return selector, nil
+}
+
+// decodeArg copies an input object argument into a struct with matching
+// JSON tags. A missing argument leaves target unchanged.
+func decodeArg(arg interface{}, target interface{}) error {
+	if arg == nil {
+		return nil
+	}
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// sortedCounts converts grouped counts to a list ordered by name
+func sortedCounts(counts map[string]int) []count {
+	sorted := make([]count, 0, len(counts))
+	for name, n := range counts {
+		sorted = append(sorted, count{Name: name, Count: n})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
@@ -0,0 +1,298 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+// testSchema builds the schema over the repository dataset with two
+// versions, the second holding only the first three projects
+func testSchema(t *testing.T) (graphql.Schema, []models.DePINProject) {
+	t.Helper()
+	projects, _, err := storage.NewCSVSource(filepath.Join("..", "..", "data", "depin_specs.csv")).Load()
+	if err != nil {
+		t.Fatalf("load dataset: %v", err)
+	}
+	compatibilityService := service.NewCompatibilityService(projects)
+	if _, _, err := compatibilityService.SetProjects(projects[:3], service.DatasetSourceAdmin); err != nil {
+		t.Fatalf("set projects: %v", err)
+	}
+
+	schema, err := NewSchema(compatibilityService, metrics.New(), models.DefaultSpecLimits())
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+	return schema, projects
+}
+
+// execute runs query with ctx and decodes its data into data
+func execute(t *testing.T, schema graphql.Schema, ctx context.Context, query string, data interface{}) []gqlerrors.FormattedError {
+	t.Helper()
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: query, Context: ctx})
+	if data != nil && result.Data != nil {
+		encoded, err := json.Marshal(result.Data)
+		if err != nil {
+			t.Fatalf("encode data: %v", err)
+		}
+		if err := json.Unmarshal(encoded, data); err != nil {
+			t.Fatalf("decode data: %v", err)
+		}
+	}
+	return result.Errors
+}
+
+// errorCode returns the code extension of err
+func errorCode(err gqlerrors.FormattedError) string {
+	code, _ := err.Extensions["code"].(string)
+	return code
+}
+
+const desktopSystem = `{cpu_cores: 8, ram_gb: 32, storage_gb: 2000, has_ssd: true, network_mbps: 500, os: "Linux"}`
+
+var predictKey = &auth.APIKey{Name: "predictor", Scopes: []string{auth.ScopePredict, auth.ScopeRead}}
+
+func TestProjectsQuery(t *testing.T) {
+	schema, projects := testSchema(t)
+	ctx := WithRequest(context.Background(), nil, "en", nil)
+
+	tests := []struct {
+		name    string
+		args    string
+		total   int
+		version int
+	}{
+		{"live", "", 3, 2},
+		{"past version", "(dataset_version: 1)", len(projects), 1},
+		{"filter by name", fmt.Sprintf("(filter: {name: %q})", strings.ToUpper(projects[0].Name)), 1, 2},
+		{"filter on past version", fmt.Sprintf("(dataset_version: 1, filter: {type: %q})", projects[0].Type), projectsOfType(projects, projects[0].Type), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data struct {
+				Projects struct {
+					Projects       []struct{ Name string } `json:"projects"`
+					Total          int                     `json:"total"`
+					DatasetVersion int                     `json:"dataset_version"`
+				} `json:"projects"`
+			}
+			if errs := execute(t, schema, ctx, "{ projects"+tt.args+" { projects { name } total dataset_version } }", &data); len(errs) > 0 {
+				t.Fatalf("errors: %v", errs)
+			}
+			if data.Projects.Total != tt.total || len(data.Projects.Projects) != tt.total {
+				t.Errorf("total = %d with %d projects, want %d", data.Projects.Total, len(data.Projects.Projects), tt.total)
+			}
+			if data.Projects.DatasetVersion != tt.version {
+				t.Errorf("dataset_version = %d, want %d", data.Projects.DatasetVersion, tt.version)
+			}
+		})
+	}
+}
+
+// projectsOfType counts the projects of a type
+func projectsOfType(projects []models.DePINProject, projectType string) int {
+	n := 0
+	for _, project := range projects {
+		if strings.EqualFold(project.Type, projectType) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSummaryQuery(t *testing.T) {
+	schema, projects := testSchema(t)
+
+	var data struct {
+		Summary struct {
+			Total  int `json:"total"`
+			ByType []struct {
+				Name  string `json:"name"`
+				Count int    `json:"count"`
+			} `json:"by_type"`
+		} `json:"summary"`
+	}
+	query := "{ summary(dataset_version: 1) { total by_type { name count } } }"
+	if errs := execute(t, schema, context.Background(), query, &data); len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+	if data.Summary.Total != len(projects) {
+		t.Errorf("total = %d, want %d", data.Summary.Total, len(projects))
+	}
+	sum := 0
+	for i, entry := range data.Summary.ByType {
+		if i > 0 && entry.Name < data.Summary.ByType[i-1].Name {
+			t.Errorf("by_type not sorted: %q after %q", entry.Name, data.Summary.ByType[i-1].Name)
+		}
+		sum += entry.Count
+	}
+	if sum != len(projects) {
+		t.Errorf("by_type counts sum to %d, want %d", sum, len(projects))
+	}
+}
+
+func TestDatasetSelectorErrors(t *testing.T) {
+	schema, _ := testSchema(t)
+
+	tests := []struct {
+		name string
+		args string
+		code string
+	}{
+		{"both selectors", `(dataset_version: 1, as_of: "2024-01-01")`, CodeBadUserInput},
+		{"negative version", "(dataset_version: -1)", CodeBadUserInput},
+		{"malformed as_of", `(as_of: "yesterday")`, CodeBadUserInput},
+		{"unknown version", "(dataset_version: 99)", CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := execute(t, schema, context.Background(), "{ projects"+tt.args+" { total } }", nil)
+			if len(errs) != 1 || errorCode(errs[0]) != tt.code {
+				t.Fatalf("errors = %v, want one %s", errs, tt.code)
+			}
+		})
+	}
+}
+
+func TestPredictQuery(t *testing.T) {
+	schema, _ := testSchema(t)
+	ctx := WithRequest(context.Background(), predictKey, "es", nil)
+
+	var data struct {
+		Predict struct {
+			Summary struct {
+				TotalProjects int `json:"total_projects"`
+			} `json:"summary"`
+			Locale         string `json:"locale"`
+			DatasetVersion int    `json:"dataset_version"`
+		} `json:"predict"`
+	}
+	query := "{ predict(system: " + desktopSystem + ") { summary { total_projects } locale dataset_version } }"
+	if errs := execute(t, schema, ctx, query, &data); len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+	if data.Predict.Summary.TotalProjects != 3 || data.Predict.DatasetVersion != 2 {
+		t.Errorf("predicted %d projects of version %d, want 3 of version 2", data.Predict.Summary.TotalProjects, data.Predict.DatasetVersion)
+	}
+	if data.Predict.Locale != "es" {
+		t.Errorf("locale = %q, want the negotiated es", data.Predict.Locale)
+	}
+}
+
+func TestPredictInvalidSystem(t *testing.T) {
+	schema, _ := testSchema(t)
+	ctx := WithRequest(context.Background(), predictKey, "en", nil)
+
+	query := `{ predict(system: {cpu_cores: 0, ram_gb: 32, storage_gb: 2000, network_mbps: 500, os: "Linux"}) { locale } }`
+	errs := execute(t, schema, ctx, query, nil)
+	if len(errs) != 1 || errorCode(errs[0]) != CodeBadUserInput {
+		t.Fatalf("errors = %v, want one %s", errs, CodeBadUserInput)
+	}
+	details, _ := errs[0].Extensions["details"].([]models.FieldError)
+	if len(details) == 0 || details[0].Field != "system.cpu_cores" {
+		t.Errorf("details = %v, want system.cpu_cores first", errs[0].Extensions["details"])
+	}
+}
+
+func TestPredictRequiresScope(t *testing.T) {
+	schema, _ := testSchema(t)
+	reader := &auth.APIKey{Name: "reader", Scopes: []string{auth.ScopeRead}}
+	ctx := WithRequest(context.Background(), reader, "en", nil)
+
+	var data struct {
+		Predict  interface{} `json:"predict"`
+		Projects struct {
+			Total int `json:"total"`
+		} `json:"projects"`
+	}
+	query := "{ predict(system: " + desktopSystem + ") { locale } projects { total } }"
+	errs := execute(t, schema, ctx, query, &data)
+	if len(errs) != 1 || errorCode(errs[0]) != CodeForbidden {
+		t.Fatalf("errors = %v, want one %s", errs, CodeForbidden)
+	}
+	if data.Predict != nil || data.Projects.Total != 3 {
+		t.Errorf("data = %+v, want only projects resolved", data)
+	}
+}
+
+// aliasedPredictions returns a query with n aliased predict fields
+func aliasedPredictions(n int) string {
+	var query strings.Builder
+	query.WriteString("{")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&query, " p%d: predict(system: %s) { locale }", i, desktopSystem)
+	}
+	query.WriteString(" }")
+	return query.String()
+}
+
+func TestPredictLimitPerRequest(t *testing.T) {
+	schema, _ := testSchema(t)
+	ctx := WithRequest(context.Background(), predictKey, "en", nil)
+
+	errs := execute(t, schema, ctx, aliasedPredictions(MaxPredictionsPerRequest+2), nil)
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	for _, err := range errs {
+		if errorCode(err) != CodeLimitExceeded {
+			t.Errorf("code = %q, want %s", errorCode(err), CodeLimitExceeded)
+		}
+	}
+}
+
+func TestPredictChargesEachFurtherPrediction(t *testing.T) {
+	schema, _ := testSchema(t)
+
+	charges := 0
+	budget := 2
+	charge := func() error {
+		charges++
+		if charges > budget {
+			return &Error{Code: CodeRateLimited, Message: "Too many requests", RetryAfter: 1500 * time.Millisecond}
+		}
+		return nil
+	}
+	ctx := WithRequest(context.Background(), predictKey, "en", charge)
+
+	errs := execute(t, schema, ctx, aliasedPredictions(5), nil)
+	// The request pays for the first prediction, the budget for two more
+	if charges != 4 {
+		t.Errorf("charged %d times, want 4", charges)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	for _, err := range errs {
+		if errorCode(err) != CodeRateLimited {
+			t.Errorf("code = %q, want %s", errorCode(err), CodeRateLimited)
+		}
+		if retryAfter := err.Extensions["retry_after"]; retryAfter != 2 {
+			t.Errorf("retry_after = %v, want 2", retryAfter)
+		}
+	}
+}
+
+func TestSinglePredictionIsNotCharged(t *testing.T) {
+	schema, _ := testSchema(t)
+	charge := func() error { return errors.New("charged") }
+	ctx := WithRequest(context.Background(), predictKey, "en", charge)
+
+	if errs := execute(t, schema, ctx, aliasedPredictions(1), nil); len(errs) > 0 {
+		t.Fatalf("errors = %v, want none", errs)
+	}
+}
@@ -0,0 +1,168 @@
+package graphqlapi
+
+import "github.com/graphql-go/graphql"
+
+// Output types resolve with the default resolver, which reads struct fields
+// by their JSON names
+
+var projectType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Project",
+	Description: "A DePIN project specification",
+	Fields: graphql.Fields{
+		"name":               &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"type":               &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"node_type":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"cpu_cores_min":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"ram_gb_min":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"ram_gb_recommended": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"storage_gb_min":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"storage_type":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"gpu_required":       &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"gpu_vram_gb_min":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"network_mbps_min":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"supported_os":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"estimated_cost_min": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"estimated_cost_max": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"cost_category":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"home_friendly":      &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"description":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"last_updated":       &graphql.Field{Type: graphql.String, Description: "Date the specifications were last reviewed, YYYY-MM-DD"},
+	},
+})
+
+var projectListType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ProjectList",
+	Fields: graphql.Fields{
+		"projects":           &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(projectType)))},
+		"total":              &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"dataset_version":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"dataset_created_at": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var countType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Count",
+	Description: "Number of projects in a group",
+	Fields: graphql.Fields{
+		"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var projectStatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ProjectStats",
+	Description: "Project counts, groups ordered by name",
+	Fields: graphql.Fields{
+		"total":              &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"by_type":            &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(countType)))},
+		"by_cost_category":   &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(countType)))},
+		"home_friendly":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"gpu_required":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"dataset_version":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"dataset_created_at": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var compatibilityResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CompatibilityResult",
+	Description: "Analysis of a system for one project",
+	Fields: graphql.Fields{
+		"name":                 &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"compatible":           &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"compatibility_score":  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"performance_rating":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"estimated_cost":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"missing_requirements": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"missing_codes":        &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Message codes for missing_requirements"},
+		"recommended_upgrades": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"warnings":             &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	},
+})
+
+var predictionSummaryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PredictionSummary",
+	Fields: graphql.Fields{
+		"total_projects":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"compatible_count":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"incompatible_count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"compatibility_rate": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"average_score":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"system_rating":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var fieldErrorType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "FieldError",
+	Description: "An invalid or suspicious input field",
+	Fields: graphql.Fields{
+		"field":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"rule":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"message": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"min":     &graphql.Field{Type: graphql.Int},
+		"max":     &graphql.Field{Type: graphql.Int},
+		"allowed": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	},
+})
+
+var predictionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Prediction",
+	Fields: graphql.Fields{
+		"compatible_projects":   &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(compatibilityResultType))},
+		"incompatible_projects": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(compatibilityResultType))},
+		"summary":               &graphql.Field{Type: graphql.NewNonNull(predictionSummaryType)},
+		"recommendations":       &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"locale":                &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"input_warnings":        &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(fieldErrorType)), Description: "Suspicious but accepted system fields"},
+		"dataset_version":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"dataset_created_at":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"scoring_strategy":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"generated_at":          &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var projectFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ProjectFilter",
+	Description: "Narrows projects; unset fields match every project",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":          &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Case-insensitive substring of the name"},
+		"type":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"cost_category": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"os":            &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "An operating system listed in supported_os"},
+		"home_friendly": &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		"gpu_required":  &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+	},
+})
+
+var gpuInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "GPUInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"model":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"vram_gb": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var storageDeviceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "StorageDeviceInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"type":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String), Description: "NVMe, SSD or HDD"},
+		"capacity_gb": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"free_gb":     &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Unset means the full capacity"},
+	},
+})
+
+var systemInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SystemInput",
+	Description: "System specifications, validated as for POST /predict",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"cpu_cores":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"ram_gb":          &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"storage_gb":      &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Required unless storage_devices is set"},
+		"has_ssd":         &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		"has_gpu":         &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		"gpu_vram_gb":     &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"network_mbps":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"os":              &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String), Description: "Windows, Linux or macOS"},
+		"gpus":            &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(gpuInput))},
+		"storage_devices": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(storageDeviceInput))},
+	},
+})
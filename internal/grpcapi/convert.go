@@ -0,0 +1,124 @@
+package grpcapi
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/simoncrean/api-predict/internal/models"
+	predictv1 "github.com/simoncrean/api-predict/proto/predict/v1"
+)
+
+// systemFromProto converts a request system to the model
+func systemFromProto(system *predictv1.SystemSpec) models.SystemSpec {
+	spec := models.SystemSpec{
+		CPUCores:    int(system.GetCpuCores()),
+		RAMGB:       int(system.GetRamGb()),
+		StorageGB:   int(system.GetStorageGb()),
+		HasSSD:      system.GetHasSsd(),
+		HasGPU:      system.GetHasGpu(),
+		GPUVRAMGB:   int(system.GetGpuVramGb()),
+		NetworkMbps: int(system.GetNetworkMbps()),
+		OS:          system.GetOs(),
+	}
+	for _, gpu := range system.GetGpus() {
+		spec.GPUs = append(spec.GPUs, models.GPU{
+			Model:  gpu.GetModel(),
+			VRAMGB: int(gpu.GetVramGb()),
+		})
+	}
+	for _, drive := range system.GetStorageDevices() {
+		spec.StorageDevices = append(spec.StorageDevices, models.StorageDevice{
+			Type:       drive.GetType(),
+			CapacityGB: int(drive.GetCapacityGb()),
+			FreeGB:     int(drive.GetFreeGb()),
+		})
+	}
+	return spec
+}
+
+// predictionToProto converts a prediction to its message
+func predictionToProto(result *models.PredictionResponse) *predictv1.PredictResponse {
+	response := &predictv1.PredictResponse{
+		CompatibleProjects:   resultsToProto(result.CompatibleProjects),
+		IncompatibleProjects: resultsToProto(result.IncompatibleProjects),
+		Summary: &predictv1.PredictionSummary{
+			TotalProjects:     int32(result.Summary.TotalProjects),
+			CompatibleCount:   int32(result.Summary.CompatibleCount),
+			IncompatibleCount: int32(result.Summary.IncompatibleCount),
+			CompatibilityRate: result.Summary.CompatibilityRate,
+			AverageScore:      result.Summary.AverageScore,
+			SystemRating:      result.Summary.SystemRating,
+		},
+		Recommendations:  result.Recommendations,
+		Locale:           result.Locale,
+		DatasetVersion:   int32(result.DatasetVersion),
+		DatasetCreatedAt: timestamppb.New(result.DatasetCreatedAt),
+		ScoringStrategy:  result.ScoringStrategy,
+		GeneratedAt:      timestamppb.New(result.GeneratedAt),
+	}
+	for _, warning := range result.InputWarnings {
+		response.InputWarnings = append(response.InputWarnings, &predictv1.FieldError{
+			Field:   warning.Field,
+			Rule:    warning.Rule,
+			Message: warning.Message,
+		})
+	}
+	return response
+}
+
+func resultsToProto(results []models.CompatibilityResult) []*predictv1.CompatibilityResult {
+	converted := make([]*predictv1.CompatibilityResult, len(results))
+	for i, result := range results {
+		converted[i] = &predictv1.CompatibilityResult{
+			Name:                result.Name,
+			Compatible:          result.Compatible,
+			CompatibilityScore:  result.CompatibilityScore,
+			PerformanceRating:   result.PerformanceRating,
+			EstimatedCost:       result.EstimatedCost,
+			MissingRequirements: result.MissingRequirements,
+			MissingCodes:        result.MissingCodes,
+			RecommendedUpgrades: result.RecommendedUpgrades,
+			Warnings:            result.Warnings,
+		}
+	}
+	return converted
+}
+
+// projectToProto converts a project to its message
+func projectToProto(project models.DePINProject) *predictv1.Project {
+	return &predictv1.Project{
+		Name:             project.Name,
+		Type:             project.Type,
+		NodeType:         project.NodeType,
+		CpuCoresMin:      int32(project.CPUCoresMin),
+		RamGbMin:         int32(project.RAMGBMin),
+		RamGbRecommended: int32(project.RAMGBRecommended),
+		StorageGbMin:     int32(project.StorageGBMin),
+		StorageType:      project.StorageType,
+		GpuRequired:      project.GPURequired,
+		GpuVramGbMin:     int32(project.GPUVRAMGBMin),
+		NetworkMbpsMin:   int32(project.NetworkMbpsMin),
+		SupportedOs:      project.SupportedOS,
+		EstimatedCostMin: int32(project.EstimatedCostMin),
+		EstimatedCostMax: int32(project.EstimatedCostMax),
+		CostCategory:     project.CostCategory,
+		HomeFriendly:     project.HomeFriendly,
+		Description:      project.Description,
+		LastUpdated:      project.LastUpdated,
+	}
+}
+
+func projectSummaryToProto(summary models.ProjectSummary) *predictv1.ProjectSummary {
+	converted := &predictv1.ProjectSummary{
+		ByType:         make(map[string]int32, len(summary.ByType)),
+		ByCostCategory: make(map[string]int32, len(summary.ByCostCategory)),
+		HomeFriendly:   int32(summary.HomeFriendly),
+		GpuRequired:    int32(summary.GPURequired),
+	}
+	for name, count := range summary.ByType {
+		converted.ByType[name] = int32(count)
+	}
+	for name, count := range summary.ByCostCategory {
+		converted.ByCostCategory[name] = int32(count)
+	}
+	return converted
+}
@@ -0,0 +1,263 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+	predictv1 "github.com/simoncrean/api-predict/proto/predict/v1"
+)
+
+// RateLimiters are the rate limiter stores of the REST route groups, shared
+// so a client's REST and gRPC calls draw from the same buckets
+type RateLimiters struct {
+	Default *ratelimit.Limiter
+	Predict *ratelimit.Limiter
+	Read    *ratelimit.Limiter
+}
+
+// methodPolicy is the rate limiter and scope applied to an RPC
+type methodPolicy struct {
+	limiter func(RateLimiters) *ratelimit.Limiter
+	scope   string // Empty when any caller may use the RPC
+	perItem bool   // The handler charges each item with chargeItem instead of the call
+}
+
+// methodPolicies mirror the REST routes of each RPC
+var methodPolicies = map[string]methodPolicy{
+	predictv1.PredictService_Predict_FullMethodName: {
+		limiter: func(l RateLimiters) *ratelimit.Limiter { return l.Predict },
+		scope:   auth.ScopePredict,
+	},
+	predictv1.PredictService_BatchPredict_FullMethodName: {
+		limiter: func(l RateLimiters) *ratelimit.Limiter { return l.Predict },
+		scope:   auth.ScopePredict,
+		perItem: true,
+	},
+	predictv1.PredictService_ListProjects_FullMethodName: {
+		limiter: func(l RateLimiters) *ratelimit.Limiter { return l.Read },
+		scope:   auth.ScopeRead,
+	},
+	predictv1.PredictService_Health_FullMethodName: {
+		limiter: func(l RateLimiters) *ratelimit.Limiter { return l.Default },
+	},
+}
+
+// Guard authenticates, authorizes, rate limits and logs calls the way the
+// REST middleware does. Keys come from the "x-api-key" metadata entry or an
+// "authorization: Bearer" token.
+type Guard struct {
+	keys          auth.KeyStore
+	requireAPIKey bool
+	limiters      RateLimiters
+	usage         *auth.UsageTracker
+	metrics       *metrics.Metrics
+	logger        *slog.Logger
+}
+
+// NewGuard creates a guard. Calls without a key are let through anonymously
+// unless requireAPIKey is set; invalid keys are always rejected.
+func NewGuard(keys auth.KeyStore, requireAPIKey bool, limiters RateLimiters, usage *auth.UsageTracker, m *metrics.Metrics, logger *slog.Logger) *Guard {
+	return &Guard{
+		keys:          keys,
+		requireAPIKey: requireAPIKey,
+		limiters:      limiters,
+		usage:         usage,
+		metrics:       m,
+		logger:        logger,
+	}
+}
+
+// ServerOptions returns the interceptors applying the guard to every call
+func (g *Guard) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(g.unary),
+		grpc.StreamInterceptor(g.stream),
+	}
+}
+
+func (g *Guard) unary(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	client, charge, err := g.admit(ctx, info.FullMethod)
+	if err != nil {
+		g.log(ctx, info.FullMethod, client, start, err)
+		return nil, err
+	}
+
+	response, err := handler(withItemCharge(ctx, charge), request)
+	g.log(ctx, info.FullMethod, client, start, err)
+	return response, err
+}
+
+func (g *Guard) stream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	client, charge, err := g.admit(stream.Context(), info.FullMethod)
+	if err == nil {
+		err = handler(srv, &chargedStream{ServerStream: stream, ctx: withItemCharge(stream.Context(), charge)})
+	}
+	g.log(stream.Context(), info.FullMethod, client, start, err)
+	return err
+}
+
+// chargedStream overrides the context of a stream so its handler can
+// charge items
+type chargedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *chargedStream) Context() context.Context {
+	return s.ctx
+}
+
+// itemChargeKey is the context key of the per-item charge function
+type itemChargeKey struct{}
+
+// withItemCharge returns ctx carrying charge, or ctx when charge is nil
+func withItemCharge(ctx context.Context, charge func() error) context.Context {
+	if charge == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, itemChargeKey{}, charge)
+}
+
+// chargeItem charges one item of a per-item method to the caller's rate
+// limit and quota, returning a ResourceExhausted error once they run out.
+// Calls that did not pass through a Guard are not charged.
+func chargeItem(ctx context.Context) error {
+	if charge, ok := ctx.Value(itemChargeKey{}).(func() error); ok {
+		return charge()
+	}
+	return nil
+}
+
+// admit checks the caller's API key, scope, rate limit and quota for method,
+// returning the client key the call is limited by. Per-item methods are only
+// authenticated and authorized here; the returned function charges each item.
+func (g *Guard) admit(ctx context.Context, method string) (string, func() error, error) {
+	client := "ip:" + peerIP(ctx)
+
+	var key *auth.APIKey
+	if presented := presentedAPIKey(ctx); presented != "" {
+		var ok bool
+		if key, ok = g.keys.Lookup(presented); !ok {
+			return client, nil, status.Error(codes.Unauthenticated, "Invalid or disabled API key")
+		}
+		client = "key:" + key.Name
+	} else if g.requireAPIKey {
+		return client, nil, status.Error(codes.Unauthenticated, "API key required")
+	}
+
+	policy, ok := methodPolicies[method]
+	if !ok {
+		return client, nil, nil
+	}
+
+	if key != nil && policy.scope != "" && !key.HasScope(policy.scope) {
+		return client, nil, status.Errorf(codes.PermissionDenied, "API key '%s' lacks the '%s' scope", key.Name, policy.scope)
+	}
+
+	limiter := policy.limiter(g.limiters)
+	charge := func() error {
+		return g.charge(limiter, method, client, key)
+	}
+	if policy.perItem {
+		return client, charge, nil
+	}
+	return client, nil, charge()
+}
+
+// charge takes one request from the caller's rate limit and quota
+func (g *Guard) charge(limiter *ratelimit.Limiter, method, client string, key *auth.APIKey) error {
+	charge := ratelimit.ChargeRequest(limiter, g.usage, client, key)
+	if charge.Allowed {
+		return nil
+	}
+
+	if g.metrics != nil {
+		g.metrics.ObserveRateLimitRejection(method)
+	}
+	if charge.QuotaExceeded {
+		return resourceExhausted("Daily quota exceeded for this API key.", charge.RetryAfter)
+	}
+	return resourceExhausted("Too many requests. Please try again later.", charge.RetryAfter)
+}
+
+// log records a finished call like the REST request log
+func (g *Guard) log(ctx context.Context, method, client string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		slog.String("client_ip", peerIP(ctx)),
+		slog.String("client_key", client),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("errors", status.Convert(err).Message()))
+	}
+
+	g.logger.LogAttrs(ctx, level, "gRPC call", attrs...)
+}
+
+// resourceExhausted builds a ResourceExhausted error telling the caller
+// when to retry
+func resourceExhausted(message string, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, message)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// presentedAPIKey extracts the key from x-api-key or authorization: Bearer
+func presentedAPIKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get("x-api-key"); len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+		return strings.TrimSpace(values[0])
+	}
+	if values := md.Get("authorization"); len(values) > 0 {
+		scheme, token, found := strings.Cut(values[0], " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// peerIP returns the caller's IP address
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
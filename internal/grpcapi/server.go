@@ -0,0 +1,252 @@
+// Package grpcapi serves the gRPC interface defined in proto/predict/v1 from
+// the same CompatibilityService as the REST API.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/i18n"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+	predictv1 "github.com/simoncrean/api-predict/proto/predict/v1"
+)
+
+// MaxBatchSize is the most systems a BatchPredict call may check
+const MaxBatchSize = 100
+
+// Server implements PredictService
+type Server struct {
+	predictv1.UnimplementedPredictServiceServer
+
+	compatibilityService *service.CompatibilityService
+	metrics              *metrics.Metrics
+	readiness            *health.Readiness
+	limits               models.SpecLimits
+}
+
+// NewServer creates a PredictService backed by compatibilityService
+func NewServer(compatibilityService *service.CompatibilityService, m *metrics.Metrics, readiness *health.Readiness, limits models.SpecLimits) *Server {
+	return &Server{
+		compatibilityService: compatibilityService,
+		metrics:              m,
+		readiness:            readiness,
+		limits:               limits,
+	}
+}
+
+// Register adds the service to a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	predictv1.RegisterPredictServiceServer(registrar, s)
+}
+
+// Predict checks a system against every project
+func (s *Server) Predict(ctx context.Context, request *predictv1.PredictRequest) (*predictv1.PredictResponse, error) {
+	result, st := s.predict(ctx, request)
+	if st != nil {
+		return nil, st.Err()
+	}
+	return predictionToProto(result), nil
+}
+
+// BatchPredict checks each system of the batch in order, streaming one
+// result per request. Failed requests are reported in their result so the
+// rest of the batch still runs. Each request is charged to the caller's rate
+// limit and quota like a Predict call; once they run out the stream ends
+// with ResourceExhausted after the results already sent.
+func (s *Server) BatchPredict(request *predictv1.BatchPredictRequest, stream grpc.ServerStreamingServer[predictv1.BatchPredictResponse]) error {
+	requests := request.GetRequests()
+	switch {
+	case len(requests) == 0:
+		return invalidArgument("Invalid request format", []models.FieldError{
+			{Field: "requests", Rule: models.RuleRequired, Message: "requests is required"},
+		}).Err()
+	case len(requests) > MaxBatchSize:
+		return invalidArgument("Batch too large", []models.FieldError{
+			{Field: "requests", Rule: models.RuleMax, Message: fmt.Sprintf("requests must contain at most %d systems", MaxBatchSize)},
+		}).Err()
+	}
+
+	ctx := stream.Context()
+	for i, item := range requests {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		if err := chargeItem(ctx); err != nil {
+			return err
+		}
+
+		response := &predictv1.BatchPredictResponse{Index: int32(i)}
+		if result, st := s.predict(ctx, item); st != nil {
+			response.Outcome = &predictv1.BatchPredictResponse_Error{Error: batchError(st)}
+		} else {
+			response.Outcome = &predictv1.BatchPredictResponse_Prediction{Prediction: predictionToProto(result)}
+		}
+
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListProjects lists the projects of the live or a past dataset version
+func (s *Server) ListProjects(ctx context.Context, request *predictv1.ListProjectsRequest) (*predictv1.ListProjectsResponse, error) {
+	selector, st := datasetSelector(request.GetDatasetVersion(), request.GetAsOf())
+	if st != nil {
+		return nil, st.Err()
+	}
+
+	projects, version, err := s.compatibilityService.ProjectsAt(selector)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	response := &predictv1.ListProjectsResponse{
+		Projects:         make([]*predictv1.Project, len(projects)),
+		Total:            int32(len(projects)),
+		Summary:          projectSummaryToProto(service.SummarizeProjects(projects)),
+		DatasetVersion:   int32(version.Version),
+		DatasetCreatedAt: timestamppb.New(version.CreatedAt),
+	}
+	for i, project := range projects {
+		response.Projects[i] = projectToProto(project)
+	}
+	return response, nil
+}
+
+// Health reports service health. Status is "healthy" when ready, "draining"
+// during shutdown and "degraded" otherwise.
+func (s *Server) Health(ctx context.Context, request *predictv1.HealthRequest) (*predictv1.HealthResponse, error) {
+	healthStatus := "healthy"
+	if ready, _ := s.readiness.Check(); !ready {
+		healthStatus = "degraded"
+		if s.readiness.Draining() {
+			healthStatus = "draining"
+		}
+	}
+
+	return &predictv1.HealthResponse{
+		Status:         healthStatus,
+		Version:        "1.0.0",
+		ProjectsLoaded: int32(len(s.compatibilityService.GetProjects())),
+		Uptime:         durationpb.New(s.compatibilityService.GetUptime()),
+		Timestamp:      timestamppb.Now(),
+	}, nil
+}
+
+// predict validates and runs one prediction request
+func (s *Server) predict(ctx context.Context, request *predictv1.PredictRequest) (*models.PredictionResponse, *status.Status) {
+	if request.GetSystem() == nil {
+		return nil, invalidArgument("Invalid request format", []models.FieldError{
+			{Field: "system", Rule: models.RuleRequired, Message: "system is required"},
+		})
+	}
+
+	system := systemFromProto(request.GetSystem())
+	fieldErrs, warnings := models.ValidateSystemSpec(system, s.limits)
+	if len(fieldErrs) > 0 {
+		return nil, invalidArgument(fmt.Sprintf("Invalid system specifications: %d field(s) failed validation", len(fieldErrs)),
+			models.PrefixFields("system.", fieldErrs))
+	}
+
+	selector, st := datasetSelector(request.GetDatasetVersion(), request.GetAsOf())
+	if st != nil {
+		return nil, st
+	}
+
+	// Request locale takes precedence over accept-language metadata
+	locale := request.GetLocale()
+	if md, ok := metadata.FromIncomingContext(ctx); locale == "" && ok {
+		if values := md.Get("accept-language"); len(values) > 0 {
+			locale = i18n.Negotiate(values[0])
+		}
+	}
+
+	result, err := s.compatibilityService.PredictCompatibilityWithOptions(ctx, system, service.PredictOptions{
+		Locale:    locale,
+		PlainText: request.GetPlainText(),
+		Dataset:   selector,
+	})
+	if errors.Is(err, service.ErrDatasetVersionNotFound) {
+		return nil, status.New(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.New(codes.Internal, "Prediction failed: "+err.Error())
+	}
+
+	s.metrics.ObservePrediction(result)
+	result.InputWarnings = models.PrefixFields("system.", warnings)
+	return result, nil
+}
+
+// datasetSelector validates a dataset version and as_of time, rejecting
+// negative versions and requests setting both
+func datasetSelector(version int32, asOf *timestamppb.Timestamp) (service.DatasetSelector, *status.Status) {
+	var asOfTime time.Time
+	if asOf != nil {
+		if err := asOf.CheckValid(); err != nil {
+			return service.DatasetSelector{}, status.New(codes.InvalidArgument, "as_of must be a valid timestamp")
+		}
+		asOfTime = asOf.AsTime()
+	}
+
+	selector, err := service.NewDatasetSelector(int(version), asOfTime)
+	if err != nil {
+		return service.DatasetSelector{}, status.New(codes.InvalidArgument, err.Error())
+	}
+	return selector, nil
+}
+
+// invalidArgument builds an InvalidArgument status carrying the failed
+// fields as BadRequest details
+func invalidArgument(message string, fieldErrs []models.FieldError) *status.Status {
+	st := status.New(codes.InvalidArgument, message)
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Message,
+			Reason:      fieldErr.Rule,
+		}
+	}
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// batchError reports a failed batch request, including its field violations
+func batchError(st *status.Status) *predictv1.BatchError {
+	batchErr := &predictv1.BatchError{
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	}
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.GetFieldViolations() {
+			batchErr.Details = append(batchErr.Details, &predictv1.FieldError{
+				Field:   violation.GetField(),
+				Rule:    violation.GetReason(),
+				Message: violation.GetDescription(),
+			})
+		}
+	}
+	return batchErr
+}
@@ -0,0 +1,384 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+	predictv1 "github.com/simoncrean/api-predict/proto/predict/v1"
+)
+
+// testOptions configures the in-process server
+type testOptions struct {
+	predictLimit  ratelimit.Limit
+	requireAPIKey bool
+}
+
+// testServer is the gRPC service on an in-memory listener
+type testServer struct {
+	client   predictv1.PredictServiceClient
+	service  *service.CompatibilityService
+	projects []models.DePINProject // Dataset version 1
+}
+
+// newTestServer serves the repository dataset with two versions, the second
+// holding only the first three projects
+func newTestServer(t *testing.T, opts testOptions) *testServer {
+	t.Helper()
+	if opts.predictLimit.Burst == 0 {
+		opts.predictLimit = ratelimit.Limit{Rate: 1000, Burst: 1000}
+	}
+
+	projects, _, err := storage.NewCSVSource(filepath.Join("..", "..", "data", "depin_specs.csv")).Load()
+	if err != nil {
+		t.Fatalf("load dataset: %v", err)
+	}
+	compatibilityService := service.NewCompatibilityService(projects)
+	if _, _, err := compatibilityService.SetProjects(projects[:3], service.DatasetSourceAdmin); err != nil {
+		t.Fatalf("set projects: %v", err)
+	}
+
+	keys, err := auth.NewMemoryKeyStore([]auth.APIKey{
+		{Name: "predictor", Key: "k-predict", Scopes: []string{auth.ScopePredict, auth.ScopeRead}},
+		{Name: "reader", Key: "k-read", Scopes: []string{auth.ScopeRead}},
+		{Name: "metered", Key: "k-quota", Scopes: []string{auth.ScopePredict}, DailyQuota: 2},
+	})
+	if err != nil {
+		t.Fatalf("key store: %v", err)
+	}
+
+	newLimiter := func(limit ratelimit.Limit) *ratelimit.Limiter {
+		return ratelimit.NewLimiter(rate.Limit(limit.Rate), limit.Burst, 100, time.Hour)
+	}
+	limiters := RateLimiters{
+		Default: newLimiter(ratelimit.Limit{Rate: 1000, Burst: 1000}),
+		Predict: newLimiter(opts.predictLimit),
+		Read:    newLimiter(ratelimit.Limit{Rate: 1000, Burst: 1000}),
+	}
+
+	appMetrics := metrics.New()
+	readiness := health.NewReadiness()
+	readiness.DatasetLoaded(len(projects), time.Now())
+	guard := NewGuard(keys, opts.requireAPIKey, limiters, auth.NewUsageTracker(), appMetrics, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(guard.ServerOptions()...)
+	NewServer(compatibilityService, appMetrics, readiness, models.DefaultSpecLimits()).Register(grpcServer)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &testServer{
+		client:   predictv1.NewPredictServiceClient(conn),
+		service:  compatibilityService,
+		projects: projects,
+	}
+}
+
+// withKey returns a context presenting an API key
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+// desktop is a valid system compatible with some projects
+func desktop() *predictv1.PredictRequest {
+	return &predictv1.PredictRequest{System: &predictv1.SystemSpec{
+		CpuCores: 8, RamGb: 32, StorageGb: 2000, HasSsd: true, NetworkMbps: 500, Os: "Linux",
+	}}
+}
+
+// retryDelay returns the RetryInfo delay of a ResourceExhausted error
+func retryDelay(t *testing.T, err error) time.Duration {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("error = %v, want ResourceExhausted", err)
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	t.Fatalf("ResourceExhausted without RetryInfo: %v", err)
+	return 0
+}
+
+func TestPredict(t *testing.T) {
+	server := newTestServer(t, testOptions{})
+
+	response, err := server.client.Predict(withKey("k-predict"), desktop())
+	if err != nil {
+		t.Fatalf("Predict: %v", err)
+	}
+	if response.GetDatasetVersion() != 2 || len(response.GetCompatibleProjects())+len(response.GetIncompatibleProjects()) != 3 {
+		t.Errorf("response = dataset %d with %d+%d projects, want version 2 with 3",
+			response.GetDatasetVersion(), len(response.GetCompatibleProjects()), len(response.GetIncompatibleProjects()))
+	}
+	if response.GetSummary().GetSystemRating() == "" || response.GetLocale() != "en" {
+		t.Errorf("summary = %v, locale %q", response.GetSummary(), response.GetLocale())
+	}
+
+	request := desktop()
+	request.DatasetVersion = 1
+	if response, err := server.client.Predict(withKey("k-predict"), request); err != nil || response.GetDatasetVersion() != 1 {
+		t.Errorf("Predict at version 1 = %v, %v", response.GetDatasetVersion(), err)
+	}
+}
+
+func TestPredictInvalidSystem(t *testing.T) {
+	server := newTestServer(t, testOptions{})
+
+	request := desktop()
+	request.System.CpuCores = 0
+	_, err := server.client.Predict(withKey("k-predict"), request)
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("error = %v, want InvalidArgument", err)
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	if len(fields) != 1 || fields[0] != "system.cpu_cores" {
+		t.Errorf("field violations = %v, want system.cpu_cores", fields)
+	}
+}
+
+// batch runs BatchPredict and collects the results and the final error
+func batch(t *testing.T, server *testServer, key string, requests ...*predictv1.PredictRequest) ([]*predictv1.BatchPredictResponse, error) {
+	t.Helper()
+	stream, err := server.client.BatchPredict(withKey(key), &predictv1.BatchPredictRequest{Requests: requests})
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []*predictv1.BatchPredictResponse
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return responses, nil
+		}
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, response)
+	}
+}
+
+func TestBatchPredict(t *testing.T) {
+	server := newTestServer(t, testOptions{})
+
+	invalid := desktop()
+	invalid.System.RamGb = -1
+	responses, err := batch(t, server, "k-predict", desktop(), invalid, desktop())
+	if err != nil {
+		t.Fatalf("BatchPredict: %v", err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("got %d results, want 3", len(responses))
+	}
+
+	for i, response := range responses {
+		if response.GetIndex() != int32(i) {
+			t.Errorf("result %d has index %d", i, response.GetIndex())
+		}
+	}
+	if responses[0].GetPrediction() == nil || responses[2].GetPrediction() == nil {
+		t.Error("valid requests around the failing one have no prediction")
+	}
+	batchErr := responses[1].GetError()
+	if batchErr == nil || batchErr.GetCode() != codes.InvalidArgument.String() ||
+		len(batchErr.GetDetails()) != 1 || batchErr.GetDetails()[0].GetField() != "system.ram_gb" {
+		t.Errorf("failing request result = %v", responses[1])
+	}
+}
+
+func TestBatchPredictValidatesSize(t *testing.T) {
+	server := newTestServer(t, testOptions{})
+
+	if _, err := batch(t, server, "k-predict"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty batch error = %v, want InvalidArgument", err)
+	}
+
+	requests := make([]*predictv1.PredictRequest, MaxBatchSize+1)
+	for i := range requests {
+		requests[i] = desktop()
+	}
+	if _, err := batch(t, server, "k-predict", requests...); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("oversized batch error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestBatchPredictChargesEachItem(t *testing.T) {
+	server := newTestServer(t, testOptions{predictLimit: ratelimit.Limit{Rate: 0.5, Burst: 2}})
+
+	responses, err := batch(t, server, "k-predict", desktop(), desktop(), desktop())
+	if len(responses) != 2 {
+		t.Errorf("got %d results with a burst of 2, want 2", len(responses))
+	}
+	if delay := retryDelay(t, err); delay <= 0 || delay > 2*time.Second {
+		t.Errorf("RetryInfo delay = %v, want up to 2s", delay)
+	}
+
+	// The batch used the bucket a Predict call draws from
+	if _, err := server.client.Predict(withKey("k-predict"), desktop()); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Predict after the batch = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestBatchPredictChargesQuota(t *testing.T) {
+	server := newTestServer(t, testOptions{})
+
+	responses, err := batch(t, server, "k-quota", desktop(), desktop(), desktop())
+	if len(responses) != 2 {
+		t.Errorf("got %d results with a quota of 2, want 2", len(responses))
+	}
+	if delay := retryDelay(t, err); delay <= 0 || delay > 24*time.Hour {
+		t.Errorf("RetryInfo delay = %v, want the time until midnight UTC", delay)
+	}
+	if _, err := server.client.Predict(withKey("k-quota"), desktop()); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Predict after the quota ran out = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestListProjects(t *testing.T) {
+	server := newTestServer(t, testOptions{})
+	first := server.service.DatasetVersions()[1] // Newest first
+
+	tests := []struct {
+		name    string
+		request *predictv1.ListProjectsRequest
+		version int32
+		total   int
+		code    codes.Code
+	}{
+		{"live", &predictv1.ListProjectsRequest{}, 2, 3, codes.OK},
+		{"dataset_version", &predictv1.ListProjectsRequest{DatasetVersion: 1}, 1, len(server.projects), codes.OK},
+		{"as_of", &predictv1.ListProjectsRequest{AsOf: timestamppb.New(first.CreatedAt)}, 1, len(server.projects), codes.OK},
+		{"as_of now", &predictv1.ListProjectsRequest{AsOf: timestamppb.Now()}, 2, 3, codes.OK},
+		{"as_of before the first version", &predictv1.ListProjectsRequest{AsOf: timestamppb.New(first.CreatedAt.Add(-time.Hour))}, 0, 0, codes.NotFound},
+		{"unknown version", &predictv1.ListProjectsRequest{DatasetVersion: 99}, 0, 0, codes.NotFound},
+		{"negative version", &predictv1.ListProjectsRequest{DatasetVersion: -1}, 0, 0, codes.InvalidArgument},
+		{"both", &predictv1.ListProjectsRequest{DatasetVersion: 1, AsOf: timestamppb.Now()}, 0, 0, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		response, err := server.client.ListProjects(withKey("k-read"), tt.request)
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.code)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if response.GetDatasetVersion() != tt.version || int(response.GetTotal()) != tt.total || len(response.GetProjects()) != tt.total {
+			t.Errorf("%s: version %d with %d projects, want version %d with %d",
+				tt.name, response.GetDatasetVersion(), response.GetTotal(), tt.version, tt.total)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	server := newTestServer(t, testOptions{requireAPIKey: true})
+
+	// Health needs a key when keys are required, but no scope
+	response, err := server.client.Health(withKey("k-read"), &predictv1.HealthRequest{})
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if response.GetStatus() != "healthy" || response.GetProjectsLoaded() != 3 || response.GetTimestamp() == nil {
+		t.Errorf("response = %v", response)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	server := newTestServer(t, testOptions{})
+	required := newTestServer(t, testOptions{requireAPIKey: true})
+
+	bearer := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer k-predict")
+
+	tests := []struct {
+		name   string
+		server *testServer
+		ctx    context.Context
+		code   codes.Code
+	}{
+		{"anonymous", server, context.Background(), codes.OK},
+		{"bearer token", server, bearer, codes.OK},
+		{"bad key", server, withKey("wrong"), codes.Unauthenticated},
+		{"bad key on health", server, withKey("wrong"), codes.Unauthenticated},
+		{"missing key when required", required, context.Background(), codes.Unauthenticated},
+		{"missing scope", server, withKey("k-read"), codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		var err error
+		if tt.name == "bad key on health" {
+			_, err = tt.server.client.Health(tt.ctx, &predictv1.HealthRequest{})
+		} else {
+			_, err = tt.server.client.Predict(tt.ctx, desktop())
+		}
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.code)
+		}
+	}
+
+	// A missing scope also stops a batch before it runs
+	if _, err := batch(t, server, "k-read", desktop()); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("BatchPredict without the predict scope = %v, want PermissionDenied", err)
+	}
+}
+
+func TestRateLimitRetryInfo(t *testing.T) {
+	server := newTestServer(t, testOptions{predictLimit: ratelimit.Limit{Rate: 0.5, Burst: 1}})
+
+	if _, err := server.client.Predict(withKey("k-predict"), desktop()); err != nil {
+		t.Fatalf("first Predict: %v", err)
+	}
+	_, err := server.client.Predict(withKey("k-predict"), desktop())
+	if delay := retryDelay(t, err); delay <= 0 || delay > 2*time.Second {
+		t.Errorf("RetryInfo delay = %v, want up to 2s", delay)
+	}
+
+	// Other clients and other groups keep their own buckets
+	if _, err := server.client.Predict(context.Background(), desktop()); err != nil {
+		t.Errorf("anonymous Predict: %v", err)
+	}
+	if _, err := server.client.ListProjects(withKey("k-predict"), &predictv1.ListProjectsRequest{}); err != nil {
+		t.Errorf("ListProjects: %v", err)
+	}
+}
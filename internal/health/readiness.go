@@ -0,0 +1,118 @@
+package health
+
+import (
+	"sync"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// Check names reported by readiness
+const (
+	CheckDataset  = "dataset"
+	CheckReload   = "reload"
+	CheckShutdown = "shutdown"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// Readiness tracks whether the service should receive traffic: the dataset
+// must be loaded, the last reload must have succeeded and the server must not
+// be draining for shutdown
+type Readiness struct {
+	mu             sync.RWMutex
+	projects       int
+	loadedAt       time.Time
+	reloadErr      error
+	reloadFailedAt time.Time
+	draining       bool
+}
+
+// NewReadiness creates a tracker that is not ready until a dataset is loaded
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// DatasetLoaded records a successful dataset load or reload
+func (r *Readiness) DatasetLoaded(projects int, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.projects = projects
+	r.loadedAt = at
+	r.reloadErr = nil
+	r.reloadFailedAt = time.Time{}
+}
+
+// ReloadFailed records a failed dataset reload. The previous dataset keeps
+// serving, but the service reports not ready until a reload succeeds.
+func (r *Readiness) ReloadFailed(err error, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reloadErr = err
+	r.reloadFailedAt = at
+}
+
+// StartDraining marks the service as shutting down
+func (r *Readiness) StartDraining() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.draining = true
+}
+
+// Draining reports whether the service is shutting down
+func (r *Readiness) Draining() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.draining
+}
+
+// Check evaluates every readiness condition
+func (r *Readiness) Check() (bool, []models.HealthCheckResult) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	checks := make([]models.HealthCheckResult, 0, 3)
+
+	dataset := models.HealthCheckResult{Name: CheckDataset, Status: StatusPass}
+	if r.projects == 0 {
+		dataset.Status = StatusFail
+		dataset.Message = "no projects loaded"
+	} else {
+		loadedAt := r.loadedAt
+		dataset.Since = &loadedAt
+	}
+	checks = append(checks, dataset)
+
+	reload := models.HealthCheckResult{Name: CheckReload, Status: StatusPass}
+	if r.reloadErr != nil {
+		failedAt := r.reloadFailedAt
+		reload.Status = StatusFail
+		reload.Message = r.reloadErr.Error()
+		reload.Since = &failedAt
+	}
+	checks = append(checks, reload)
+
+	shutdown := models.HealthCheckResult{Name: CheckShutdown, Status: StatusPass}
+	if r.draining {
+		shutdown.Status = StatusFail
+		shutdown.Message = "draining connections for shutdown"
+	}
+	checks = append(checks, shutdown)
+
+	ready := true
+	for _, check := range checks {
+		if check.Status != StatusPass {
+			ready = false
+		}
+	}
+
+	return ready, checks
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// statuses maps check names to their status
+func statuses(checks []models.HealthCheckResult) map[string]string {
+	byName := make(map[string]string, len(checks))
+	for _, check := range checks {
+		byName[check.Name] = check.Status
+	}
+	return byName
+}
+
+func TestReadiness(t *testing.T) {
+	r := NewReadiness()
+	loadedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name   string
+		apply  func()
+		ready  bool
+		failed string // The failing check, if any
+	}{
+		{"no dataset", func() {}, false, CheckDataset},
+		{"loaded", func() { r.DatasetLoaded(12, loadedAt) }, true, ""},
+		{"reload failed", func() { r.ReloadFailed(errors.New("bad row"), loadedAt.Add(time.Hour)) }, false, CheckReload},
+		{"reloaded", func() { r.DatasetLoaded(13, loadedAt.Add(2*time.Hour)) }, true, ""},
+		{"draining", r.StartDraining, false, CheckShutdown},
+	}
+	for _, step := range steps {
+		step.apply()
+		ready, checks := r.Check()
+		if ready != step.ready {
+			t.Errorf("%s: ready = %v, want %v", step.name, ready, step.ready)
+		}
+		for name, status := range statuses(checks) {
+			want := StatusPass
+			if name == step.failed {
+				want = StatusFail
+			}
+			if status != want {
+				t.Errorf("%s: check %s = %s, want %s", step.name, name, status, want)
+			}
+		}
+	}
+
+	if !r.Draining() {
+		t.Error("Draining = false after StartDraining")
+	}
+}
+
+func TestReadinessReportsReloadError(t *testing.T) {
+	r := NewReadiness()
+	loadedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	failedAt := loadedAt.Add(time.Minute)
+	r.DatasetLoaded(3, loadedAt)
+	r.ReloadFailed(errors.New("bad row"), failedAt)
+
+	_, checks := r.Check()
+	for _, check := range checks {
+		switch check.Name {
+		case CheckDataset:
+			if check.Since == nil || !check.Since.Equal(loadedAt) {
+				t.Errorf("dataset since = %v, want %v", check.Since, loadedAt)
+			}
+		case CheckReload:
+			if check.Message != "bad row" || check.Since == nil || !check.Since.Equal(failedAt) {
+				t.Errorf("reload check = %+v", check)
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultLocale is used when no supported locale is requested
+const DefaultLocale = "en"
+
+// Localizer renders catalog messages for a single locale
+type Localizer struct {
+	locale    string
+	plainText bool
+}
+
+// NewLocalizer creates a localizer for the given locale. Unsupported locales
+// fall back to DefaultLocale. When plainText is set, emojis are stripped from
+// every rendered message.
+func NewLocalizer(locale string, plainText bool) *Localizer {
+	return &Localizer{
+		locale:    Resolve(locale),
+		plainText: plainText,
+	}
+}
+
+// Locale returns the locale messages are rendered in
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T renders the message for code, formatting args into its template.
+// Missing translations fall back to English and then to the code itself.
+func (l *Localizer) T(code string, args ...interface{}) string {
+	template, ok := catalogs[l.locale][code]
+	if !ok {
+		template, ok = catalogs[DefaultLocale][code]
+	}
+	if !ok {
+		template = code
+	}
+
+	message := template
+	if len(args) > 0 {
+		message = fmt.Sprintf(template, args...)
+	}
+
+	if l.plainText {
+		message = StripEmoji(message)
+	}
+
+	return message
+}
+
+// Supported returns the list of locales with a message catalog
+func Supported() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Resolve maps a locale tag such as "es-MX" to a supported catalog,
+// returning DefaultLocale when nothing matches
+func Resolve(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return DefaultLocale
+	}
+
+	if _, ok := catalogs[tag]; ok {
+		return tag
+	}
+
+	// Fall back from region-specific tags to the base language
+	if base, _, found := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-"); found {
+		if _, ok := catalogs[base]; ok {
+			return base
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Negotiate picks the best supported locale from an Accept-Language header.
+// Languages weighted q=0 are not acceptable and never chosen.
+func Negotiate(acceptLanguage string) string {
+	best := DefaultLocale
+	bestQuality := -1.0
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil || parsed <= 0 {
+				continue
+			}
+			quality = parsed
+		}
+
+		locale := Resolve(tag)
+		if locale == DefaultLocale && !strings.HasPrefix(strings.ToLower(tag), DefaultLocale) {
+			// Unsupported language, don't let it win over a real match
+			continue
+		}
+
+		if quality > bestQuality {
+			best = locale
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// StripEmoji removes emoji and pictographic symbols from a message so it can
+// be shown on plain-text clients
+func StripEmoji(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range s {
+		if isEmoji(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return strings.Join(strings.FieldsFunc(b.String(), unicode.IsSpace), " ")
+}
+
+// isEmoji reports whether r belongs to one of the emoji or symbol blocks
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, transport, etc.
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Arrows and stars
+		return true
+	case r == 0xFE0F || r == 0x200D: // Variation selector and zero-width joiner
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{"empty", "", "en"},
+		{"exact", "es", "es"},
+		{"region falls back to base", "es-MX", "es"},
+		{"highest quality wins", "en;q=0.5, es;q=0.9", "es"},
+		{"unsupported is skipped", "fr, es;q=0.1", "es"},
+		{"only unsupported", "fr, de", "en"},
+		{"wildcard ignored", "*", "en"},
+		{"malformed quality skipped", "es;q=abc", "en"},
+		{"q=0 is not acceptable", "es;q=0", "en"},
+		{"q=0 loses to a lower weight", "es;q=0, en;q=0.1", "en"},
+		{"q=0.0 is not acceptable", "fr, es;q=0.0", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := map[string]string{
+		"":      "en",
+		"ES":    "es",
+		"es_AR": "es",
+		"fr-FR": "en",
+	}
+	for tag, want := range tests {
+		if got := Resolve(tag); got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestLocalizerT(t *testing.T) {
+	es := NewLocalizer("es", false)
+	if got, want := es.T(MissingCPUCores, 4, 2), "Núcleos de CPU: se necesitan 4, hay 2"; got != want {
+		t.Errorf("T = %q, want %q", got, want)
+	}
+
+	if got := es.T("unknown.code"); got != "unknown.code" {
+		t.Errorf("T(unknown) = %q, want the code", got)
+	}
+
+	plain := NewLocalizer("en", true)
+	if got := plain.T(RecommendationExcellent); strings.ContainsRune(got, '🎉') || !strings.HasPrefix(got, "Excellent!") {
+		t.Errorf("plain text T = %q, want emoji stripped", got)
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for code := range catalogs[DefaultLocale] {
+			if _, ok := catalog[code]; !ok {
+				t.Errorf("locale %q is missing %q", locale, code)
+			}
+		}
+	}
+}
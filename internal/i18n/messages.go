@@ -0,0 +1,86 @@
+package i18n
+
+// Message codes used across compatibility results and recommendations
+const (
+	// Missing requirements
+	MissingCPUCores = "missing.cpu_cores"
+	MissingRAM      = "missing.ram"
+	MissingStorage  = "missing.storage"
+	MissingSSD      = "missing.ssd"
+	MissingGPU      = "missing.gpu"
+	MissingGPUVRAM  = "missing.gpu_vram"
+	MissingNetwork  = "missing.network"
+	MissingOS       = "missing.os"
+
+	// Per-project upgrades and warnings
+	UpgradeRAMRecommended  = "upgrade.ram_recommended"
+	WarningNotHomeFriendly = "warning.not_home_friendly"
+
+	// Overall recommendations
+	RecommendationExcellent    = "recommendation.excellent"
+	RecommendationGood         = "recommendation.good"
+	RecommendationFair         = "recommendation.fair"
+	RecommendationLimited      = "recommendation.limited"
+	RecommendationBestProjects = "recommendation.best_projects"
+
+	// Hardware upgrade suggestions
+	SuggestUpgradeRAM     = "suggest.upgrade_ram"
+	SuggestUpgradeCPU     = "suggest.upgrade_cpu"
+	SuggestUpgradeGPU     = "suggest.upgrade_gpu"
+	SuggestUpgradeStorage = "suggest.upgrade_storage"
+	SuggestUpgradeNetwork = "suggest.upgrade_network"
+)
+
+// catalogs maps locale to message code to template
+var catalogs = map[string]map[string]string{
+	"en": {
+		MissingCPUCores: "CPU cores: need %d, have %d",
+		MissingRAM:      "RAM: need %dGB, have %dGB",
+		MissingStorage:  "Storage: need %dGB, have %dGB",
+		MissingSSD:      "SSD storage required",
+		MissingGPU:      "Dedicated GPU required",
+		MissingGPUVRAM:  "GPU VRAM: need %dGB, have %dGB",
+		MissingNetwork:  "Network speed: need %dMbps, have %dMbps",
+		MissingOS:       "OS not supported: need one of [%s], have %s",
+
+		UpgradeRAMRecommended:  "RAM upgrade to %dGB recommended for optimal performance",
+		WarningNotHomeFriendly: "This project may not be suitable for home use",
+
+		RecommendationExcellent:    "🎉 Excellent! Your system is compatible with most DePIN projects.",
+		RecommendationGood:         "👍 Good compatibility! Your system works well with many DePIN projects.",
+		RecommendationFair:         "⚠️ Fair compatibility. Consider upgrading for better project support.",
+		RecommendationLimited:      "📈 Limited compatibility. Upgrades recommended for better DePIN support.",
+		RecommendationBestProjects: "🚀 Recommended projects for your system: %s",
+
+		SuggestUpgradeRAM:     "💾 Consider upgrading RAM for better project compatibility",
+		SuggestUpgradeCPU:     "🖥️ A CPU upgrade would significantly improve project support",
+		SuggestUpgradeGPU:     "🎮 Adding a dedicated GPU would unlock AI and compute-intensive projects",
+		SuggestUpgradeStorage: "💿 Consider upgrading to SSD storage or increasing capacity",
+		SuggestUpgradeNetwork: "🌐 Faster internet connection would improve project compatibility",
+	},
+	"es": {
+		MissingCPUCores: "Núcleos de CPU: se necesitan %d, hay %d",
+		MissingRAM:      "RAM: se necesitan %dGB, hay %dGB",
+		MissingStorage:  "Almacenamiento: se necesitan %dGB, hay %dGB",
+		MissingSSD:      "Se requiere almacenamiento SSD",
+		MissingGPU:      "Se requiere una GPU dedicada",
+		MissingGPUVRAM:  "VRAM de GPU: se necesitan %dGB, hay %dGB",
+		MissingNetwork:  "Velocidad de red: se necesitan %dMbps, hay %dMbps",
+		MissingOS:       "Sistema operativo no soportado: se necesita uno de [%s], hay %s",
+
+		UpgradeRAMRecommended:  "Se recomienda ampliar la RAM a %dGB para un rendimiento óptimo",
+		WarningNotHomeFriendly: "Este proyecto puede no ser adecuado para uso doméstico",
+
+		RecommendationExcellent:    "🎉 ¡Excelente! Tu sistema es compatible con la mayoría de los proyectos DePIN.",
+		RecommendationGood:         "👍 ¡Buena compatibilidad! Tu sistema funciona bien con muchos proyectos DePIN.",
+		RecommendationFair:         "⚠️ Compatibilidad aceptable. Considera mejorar tu equipo para admitir más proyectos.",
+		RecommendationLimited:      "📈 Compatibilidad limitada. Se recomiendan mejoras para un mejor soporte DePIN.",
+		RecommendationBestProjects: "🚀 Proyectos recomendados para tu sistema: %s",
+
+		SuggestUpgradeRAM:     "💾 Considera ampliar la RAM para mejorar la compatibilidad",
+		SuggestUpgradeCPU:     "🖥️ Una mejor CPU ampliaría notablemente los proyectos compatibles",
+		SuggestUpgradeGPU:     "🎮 Añadir una GPU dedicada desbloquearía proyectos de IA y de cómputo intensivo",
+		SuggestUpgradeStorage: "💿 Considera pasar a almacenamiento SSD o aumentar la capacidad",
+		SuggestUpgradeNetwork: "🌐 Una conexión a internet más rápida mejoraría la compatibilidad",
+	},
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// New creates a structured logger writing JSON records to w, or logfmt-style
+// text when format is "text", filtered at the given level. Records logged
+// with a context carrying a request ID include it as "request_id".
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "", "json":
+		return slog.New(contextHandler{slog.NewJSONHandler(w, opts)}), nil
+	case "text":
+		return slog.New(contextHandler{slog.NewTextHandler(w, opts)}), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected json or text", format)
+	}
+}
+
+// ParseLevel maps LOG_LEVEL values (debug, info, warn, error) to slog levels
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", level)
+	}
+}
+
+// requestIDKey is the context key for the request ID
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the request ID, which the loggers
+// created by New add to every record logged with that context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds context values such as the request ID to records
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{" INFO ", slog.LevelInfo, false},
+		{"warning", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewFiltersByLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", "json")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"msg":"kept"`) {
+		t.Errorf("output = %q, want only the warn record", buf.String())
+	}
+}
+
+func TestNewRejectsUnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("New accepted format xml")
+	}
+}
+
+func TestRequestIDAdded(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.With("component", "test").InfoContext(ctx, "handled")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON record %q: %v", buf.String(), err)
+	}
+	if record["request_id"] != "req-123" || record["component"] != "test" {
+		t.Errorf("record = %v, want request_id and component", record)
+	}
+
+	buf.Reset()
+	logger.Info("no context")
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("record without a request ID = %q", buf.String())
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "text")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logger.InfoContext(WithRequestID(context.Background(), "abc"), "handled", "status", 200)
+	if out := buf.String(); !strings.Contains(out, "msg=handled") || !strings.Contains(out, "request_id=abc") {
+		t.Errorf("output = %q", out)
+	}
+}
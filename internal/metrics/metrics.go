@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+const namespace = "depin"
+
+// Metrics holds the Prometheus collectors exported by the API
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requestsTotal       *prometheus.CounterVec
+	requestDuration     *prometheus.HistogramVec
+	rateLimitRejections *prometheus.CounterVec
+
+	predictionsTotal      prometheus.Counter
+	compatibleProjects    prometheus.Histogram
+	compatibilityScore    prometheus.Histogram
+	projectOutcomesTotal  *prometheus.CounterVec
+	datasetProjects       prometheus.Gauge
+	datasetLastReloadTime prometheus.Gauge
+}
+
+// New creates a metrics set registered on its own registry, including Go
+// runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Total HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		rateLimitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_rejections_total",
+			Help:      "Requests rejected by the rate limiter, by route.",
+		}, []string{"route"}),
+
+		predictionsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "predictions_total",
+			Help:      "Total compatibility predictions served.",
+		}),
+
+		compatibleProjects: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "prediction_compatible_projects",
+			Help:      "Number of compatible projects per prediction.",
+			Buckets:   []float64{0, 1, 2, 3, 5, 8, 13, 21, 34, 55},
+		}),
+
+		compatibilityScore: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "prediction_compatibility_score",
+			Help:      "Distribution of per-project compatibility scores.",
+			Buckets:   []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0},
+		}),
+
+		projectOutcomesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "prediction_project_outcomes_total",
+			Help:      "Per-project prediction outcomes.",
+		}, []string{"project", "compatible"}),
+
+		datasetProjects: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "dataset_projects",
+			Help:      "Number of DePIN projects currently loaded.",
+		}),
+
+		datasetLastReloadTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "dataset_last_reload_timestamp_seconds",
+			Help:      "Unix time the dataset was last loaded.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestsTotal,
+		m.requestDuration,
+		m.rateLimitRejections,
+		m.predictionsTotal,
+		m.compatibleProjects,
+		m.compatibilityScore,
+		m.projectOutcomesTotal,
+		m.datasetProjects,
+		m.datasetLastReloadTime,
+	)
+
+	return m
+}
+
+// Handler returns an http.Handler serving the text exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a completed HTTP request
+func (m *Metrics) ObserveRequest(method, route string, status int, latency time.Duration) {
+	code := strconv.Itoa(status)
+	m.requestsTotal.WithLabelValues(method, route, code).Inc()
+	m.requestDuration.WithLabelValues(method, route, code).Observe(latency.Seconds())
+}
+
+// ObserveRateLimitRejection records a request rejected by the rate limiter
+func (m *Metrics) ObserveRateLimitRejection(route string) {
+	m.rateLimitRejections.WithLabelValues(route).Inc()
+}
+
+// ObservePrediction records the outcome distribution of a prediction
+func (m *Metrics) ObservePrediction(response *models.PredictionResponse) {
+	m.predictionsTotal.Inc()
+	m.compatibleProjects.Observe(float64(response.Summary.CompatibleCount))
+
+	for _, result := range response.CompatibleProjects {
+		m.compatibilityScore.Observe(result.CompatibilityScore)
+		m.projectOutcomesTotal.WithLabelValues(result.Name, "true").Inc()
+	}
+	for _, result := range response.IncompatibleProjects {
+		m.compatibilityScore.Observe(result.CompatibilityScore)
+		m.projectOutcomesTotal.WithLabelValues(result.Name, "false").Inc()
+	}
+}
+
+// SetDataset records the loaded dataset size and load time
+func (m *Metrics) SetDataset(projects int, loadedAt time.Time) {
+	m.datasetProjects.Set(float64(projects))
+	m.datasetLastReloadTime.Set(float64(loadedAt.Unix()))
+}
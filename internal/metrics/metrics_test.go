@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// scrape returns the text exposition served by m
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("Content-Type = %q, want the text format", contentType)
+	}
+	body, _ := io.ReadAll(recorder.Body)
+	return string(body)
+}
+
+func TestExposition(t *testing.T) {
+	m := New()
+	m.ObserveRequest(http.MethodPost, "/api/v1/predict", http.StatusOK, 30*time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/api/v1/predict", http.StatusOK, 10*time.Millisecond)
+	m.ObserveRateLimitRejection("/api/v1/predict")
+	m.SetDataset(42, time.Unix(1700000000, 0))
+	m.ObservePrediction(&models.PredictionResponse{
+		CompatibleProjects:   []models.CompatibilityResult{{Name: "Helium", CompatibilityScore: 0.9}},
+		IncompatibleProjects: []models.CompatibilityResult{{Name: "Filecoin", CompatibilityScore: 0.2}},
+		Summary:              models.PredictionSummary{CompatibleCount: 1},
+	})
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`depin_http_requests_total{method="POST",route="/api/v1/predict",status="200"} 2`,
+		`depin_http_request_duration_seconds_count{method="POST",route="/api/v1/predict",status="200"} 2`,
+		`depin_rate_limit_rejections_total{route="/api/v1/predict"} 1`,
+		`depin_dataset_projects 42`,
+		`depin_dataset_last_reload_timestamp_seconds 1.7e+09`,
+		`depin_predictions_total 1`,
+		`depin_prediction_compatible_projects_count 1`,
+		`depin_prediction_compatibility_score_count 2`,
+		`depin_prediction_project_outcomes_total{compatible="true",project="Helium"} 1`,
+		`depin_prediction_project_outcomes_total{compatible="false",project="Filecoin"} 1`,
+		"# TYPE depin_http_request_duration_seconds histogram",
+		"go_goroutines ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("exposition lacks %q", want)
+		}
+	}
+}
+
+func TestRegistriesAreIndependent(t *testing.T) {
+	first, second := New(), New()
+	first.ObserveRateLimitRejection("/a")
+
+	if body := scrape(t, second); strings.Contains(body, `route="/a"`) {
+		t.Error("second registry reports the first's observations")
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+// GPUCards returns the system's graphics cards, describing the flat
+// HasGPU/GPUVRAMGB fields as a single card when no list was given
+func (s SystemSpec) GPUCards() []GPU {
+	if len(s.GPUs) > 0 {
+		return s.GPUs
+	}
+	if s.HasGPU {
+		return []GPU{{VRAMGB: s.GPUVRAMGB}}
+	}
+	return nil
+}
+
+// HasDedicatedGPU reports whether the system has at least one graphics card
+func (s SystemSpec) HasDedicatedGPU() bool {
+	return len(s.GPUCards()) > 0
+}
+
+// BestGPUVRAMGB returns the VRAM of the largest card. Projects run on a
+// single card, so this is what GPU requirements are matched against.
+func (s SystemSpec) BestGPUVRAMGB() int {
+	best := 0
+	for _, gpu := range s.GPUCards() {
+		best = max(best, gpu.VRAMGB)
+	}
+	return best
+}
+
+// TotalGPUVRAMGB returns the VRAM summed across all cards
+func (s SystemSpec) TotalGPUVRAMGB() int {
+	total := 0
+	for _, gpu := range s.GPUCards() {
+		total += gpu.VRAMGB
+	}
+	return total
+}
+
+// Drives returns the system's storage devices, describing the flat
+// StorageGB/HasSSD fields as a single drive when no list was given
+func (s SystemSpec) Drives() []StorageDevice {
+	if len(s.StorageDevices) > 0 {
+		return s.StorageDevices
+	}
+	if s.StorageGB == 0 {
+		return nil
+	}
+
+	deviceType := StorageHDD
+	if s.HasSSD {
+		deviceType = StorageSSD
+	}
+	return []StorageDevice{{Type: deviceType, CapacityGB: s.StorageGB}}
+}
+
+// IsSolidState reports whether the drive is an SSD or NVMe drive
+func (d StorageDevice) IsSolidState() bool {
+	return d.Type == StorageSSD || d.Type == StorageNVMe
+}
+
+// AvailableGB returns the drive's free space, or its capacity when unknown
+func (d StorageDevice) AvailableGB() int {
+	if d.FreeGB > 0 {
+		return d.FreeGB
+	}
+	return d.CapacityGB
+}
+
+// HasSSDStorage reports whether any drive is solid state
+func (s SystemSpec) HasSSDStorage() bool {
+	for _, drive := range s.Drives() {
+		if drive.IsSolidState() {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalStorageGB returns the capacity summed across all drives
+func (s SystemSpec) TotalStorageGB() int {
+	total := 0
+	for _, drive := range s.Drives() {
+		total += drive.CapacityGB
+	}
+	return total
+}
+
+// AvailableStorageGB returns the most space available on a single drive, only
+// considering solid state drives when solidState is set. A node keeps its data
+// on one drive, so space is not pooled across drives.
+func (s SystemSpec) AvailableStorageGB(solidState bool) int {
+	best := 0
+	for _, drive := range s.Drives() {
+		if solidState && !drive.IsSolidState() {
+			continue
+		}
+		best = max(best, drive.AvailableGB())
+	}
+	return best
+}
@@ -2,16 +2,44 @@ package models
 
 import "time"
 
-// SystemSpec represents a user's system specifications
+// SystemSpec represents a user's system specifications. Field ranges are
+// defined by SpecLimits and checked by ValidateSystemSpec.
 type SystemSpec struct {
-	CPUCores    int    `json:"cpu_cores" binding:"required,min=1,max=64"`
-	RAMGB       int    `json:"ram_gb" binding:"required,min=1,max=128"`
-	StorageGB   int    `json:"storage_gb" binding:"required,min=32,max=8192"`
+	CPUCores    int    `json:"cpu_cores"`
+	RAMGB       int    `json:"ram_gb"`
+	StorageGB   int    `json:"storage_gb"`
 	HasSSD      bool   `json:"has_ssd"`
 	HasGPU      bool   `json:"has_gpu"`
-	GPUVRAMGB   int    `json:"gpu_vram_gb" binding:"min=0,max=48"`
-	NetworkMbps int    `json:"network_mbps" binding:"required,min=1,max=10000"`
-	OS          string `json:"os" binding:"required,oneof=Windows Linux macOS"`
+	GPUVRAMGB   int    `json:"gpu_vram_gb"`
+	NetworkMbps int    `json:"network_mbps"`
+	OS          string `json:"os"`
+
+	// Detailed hardware, taking precedence over the flat fields when set
+	GPUs           []GPU           `json:"gpus,omitempty"`
+	StorageDevices []StorageDevice `json:"storage_devices,omitempty"`
+}
+
+// GPU describes a single graphics card
+type GPU struct {
+	Model  string `json:"model,omitempty"`
+	VRAMGB int    `json:"vram_gb"`
+}
+
+// Storage device types
+const (
+	StorageNVMe = "NVMe"
+	StorageSSD  = "SSD"
+	StorageHDD  = "HDD"
+)
+
+// StorageDeviceTypes lists the accepted StorageDevice.Type values
+var StorageDeviceTypes = []string{StorageNVMe, StorageSSD, StorageHDD}
+
+// StorageDevice describes a single drive
+type StorageDevice struct {
+	Type       string `json:"type"` // "NVMe", "SSD" or "HDD"
+	CapacityGB int    `json:"capacity_gb"`
+	FreeGB     int    `json:"free_gb,omitempty"` // 0 means unknown, treated as the full capacity
 }
 
 // DePINProject represents a DePIN project specification
@@ -33,6 +61,7 @@ type DePINProject struct {
 	CostCategory     string `json:"cost_category"`
 	HomeFriendly     bool   `json:"home_friendly"`
 	Description      string `json:"description"`
+	LastUpdated      string `json:"last_updated,omitempty"` // Date the specifications were last reviewed, YYYY-MM-DD
 }
 
 // CompatibilityResult represents the compatibility analysis for a single project
@@ -43,13 +72,22 @@ type CompatibilityResult struct {
 	PerformanceRating   string   `json:"performance_rating"`
 	EstimatedCost       string   `json:"estimated_cost"`
 	MissingRequirements []string `json:"missing_requirements"`
+	MissingCodes        []string `json:"missing_codes,omitempty"` // Message codes for MissingRequirements
 	RecommendedUpgrades []string `json:"recommended_upgrades"`
 	Warnings            []string `json:"warnings,omitempty"`
 }
 
 // PredictionRequest represents the API request for compatibility prediction
 type PredictionRequest struct {
-	System SystemSpec `json:"system" binding:"required"`
+	System    SystemSpec `json:"system" binding:"required"`
+	Locale    string     `json:"locale,omitempty"`     // Overrides Accept-Language, e.g. "es"
+	PlainText bool       `json:"plain_text,omitempty"` // Strip emojis from messages
+	Persist   bool       `json:"persist,omitempty"`    // Store the result for GET /predictions/{id}
+
+	// Predict against a past dataset, by version or by the time it was live
+	// (RFC 3339 or YYYY-MM-DD). At most one may be set.
+	DatasetVersion int    `json:"dataset_version,omitempty"`
+	AsOf           string `json:"as_of,omitempty"`
 }
 
 // PredictionResponse represents the API response with compatibility results
@@ -58,6 +96,14 @@ type PredictionResponse struct {
 	IncompatibleProjects []CompatibilityResult `json:"incompatible_projects"`
 	Summary              PredictionSummary     `json:"summary"`
 	Recommendations      []string              `json:"recommendations"`
+	Locale               string                `json:"locale"`
+	InputWarnings        []FieldError          `json:"input_warnings,omitempty"` // Suspicious but accepted system fields
+	RequestID            string                `json:"request_id,omitempty"`
+	DatasetVersion       int                   `json:"dataset_version"`
+	DatasetCreatedAt     time.Time             `json:"dataset_created_at"`
+	ScoringStrategy      string                `json:"scoring_strategy"`
+	PredictionID         string                `json:"prediction_id,omitempty"`  // Set when the result was persisted
+	DeletionToken        string                `json:"deletion_token,omitempty"` // Returned once with prediction_id, never stored
 	GeneratedAt          time.Time             `json:"generated_at"`
 }
 
@@ -80,11 +126,69 @@ type HealthResponse struct {
 	Timestamp      time.Time `json:"timestamp"`
 }
 
+// HealthCheckResult reports a single readiness condition
+type HealthCheckResult struct {
+	Name    string     `json:"name"`
+	Status  string     `json:"status"` // "pass" or "fail"
+	Message string     `json:"message,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// MetricsResponse summarizes service and dataset statistics
+type MetricsResponse struct {
+	ServiceInfo          ServiceInfo    `json:"service_info"`
+	ProjectsLoadedTotal  int            `json:"projects_loaded_total"`
+	ProjectsByType       map[string]int `json:"projects_by_type"`
+	ProjectsByCost       map[string]int `json:"projects_by_cost"`
+	ProjectsHomeFriendly int            `json:"projects_home_friendly"`
+	ProjectsGPURequired  int            `json:"projects_gpu_required"`
+	DatasetVersion       int            `json:"dataset_version"`
+	Timestamp            int64          `json:"timestamp"` // Unix seconds
+}
+
+// ServiceInfo identifies the running service
+type ServiceInfo struct {
+	Name          string  `json:"name"`
+	Version       string  `json:"version"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+// ReadinessResponse represents the readiness probe response
+type ReadinessResponse struct {
+	Status    string              `json:"status"` // "ready" or "not_ready"
+	Checks    []HealthCheckResult `json:"checks"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
 // ProjectsResponse represents the response for listing all projects
 type ProjectsResponse struct {
+	Projects         []DePINProject `json:"projects"`
+	Total            int            `json:"total"`
+	Summary          ProjectSummary `json:"summary"`
+	DatasetVersion   int            `json:"dataset_version"`
+	DatasetCreatedAt time.Time      `json:"dataset_created_at"`
+}
+
+// DatasetVersion describes a retained version of the project dataset
+type DatasetVersion struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"` // When the version went live
+	Source    string    `json:"source"`     // "initial", "reload" or "admin"
+	Projects  int       `json:"projects"`
+	Checksum  string    `json:"checksum"`
+}
+
+// DatasetSnapshot is a dataset version with its projects, as persisted
+type DatasetSnapshot struct {
+	Version  DatasetVersion `json:"version"`
 	Projects []DePINProject `json:"projects"`
-	Total    int            `json:"total"`
-	Summary  ProjectSummary `json:"summary"`
+}
+
+// DatasetVersionsResponse lists retained dataset versions, newest first
+type DatasetVersionsResponse struct {
+	Versions []DatasetVersion `json:"versions"`
+	Current  int              `json:"current"`
+	Total    int              `json:"total"`
 }
 
 // ProjectSummary provides statistics about loaded projects
@@ -95,12 +199,226 @@ type ProjectSummary struct {
 	GPURequired    int            `json:"gpu_required"`
 }
 
+// ReferenceSystem is a named SystemSpec used to gauge the effect of dataset changes
+type ReferenceSystem struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	System      SystemSpec `json:"system"`
+}
+
+// ProfileImpact describes how a reference system's predictions change
+// between two datasets
+type ProfileImpact struct {
+	Profile            string        `json:"profile"`
+	CompatibleBefore   int           `json:"compatible_before"`
+	CompatibleAfter    int           `json:"compatible_after"`
+	AverageScoreBefore float64       `json:"average_score_before"`
+	AverageScoreAfter  float64       `json:"average_score_after"`
+	Gained             []string      `json:"gained"` // Newly compatible projects
+	Lost               []string      `json:"lost"`   // No longer compatible projects
+	ScoreChanges       []ScoreChange `json:"score_changes"`
+}
+
+// ScoreChange is a compatibility score move for a project in both datasets
+type ScoreChange struct {
+	Project string  `json:"project"`
+	Before  float64 `json:"before"`
+	After   float64 `json:"after"`
+	Delta   float64 `json:"delta"`
+}
+
+// ImpactReport summarizes how a dataset change affects the reference systems
+type ImpactReport struct {
+	Profiles         []ProfileImpact `json:"profiles"`
+	ProfilesAffected int             `json:"profiles_affected"` // Profiles gaining or losing a project
+	ProjectsGained   int             `json:"projects_gained"`   // Gains summed across profiles
+	ProjectsLost     int             `json:"projects_lost"`     // Losses summed across profiles
+	GeneratedAt      time.Time       `json:"generated_at"`
+}
+
+// ReferenceSystemsResponse lists the configured reference systems
+type ReferenceSystemsResponse struct {
+	Systems []ReferenceSystem `json:"systems"`
+	Total   int               `json:"total"`
+}
+
+// SavedProfile is a named SystemSpec kept for repeated predictions
+type SavedProfile struct {
+	ID             string             `json:"id"`
+	Name           string             `json:"name"`
+	System         SystemSpec         `json:"system"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	LastEvaluation *ProfileEvaluation `json:"last_evaluation,omitempty"` // Unset until the profile is first predicted
+	Webhook        *ProfileWebhook    `json:"webhook,omitempty"`
+
+	// Changing the webhook or deleting the profile needs the access token,
+	// the owning API key or an admin key
+	AccessToken     string `json:"access_token,omitempty"`      // Returned once on creation, never stored
+	AccessTokenHash string `json:"access_token_hash,omitempty"` // SHA-256 of the access token, not shown to readers
+	Owner           string `json:"owner,omitempty"`             // API key that created it, not shown to readers
+}
+
+// ProfileWebhook receives events when dataset changes make a saved profile
+// gain or lose compatible projects
+type ProfileWebhook struct {
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // HMAC key for event signatures, only shown when registered
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookRequest registers a webhook for a saved profile. Without a secret
+// one is generated.
+type WebhookRequest struct {
+	URL    string `json:"url" binding:"required,url,max=2048"`
+	Secret string `json:"secret,omitempty" binding:"omitempty,min=16,max=256"`
+}
+
+// Webhook event types
+const (
+	WebhookEventCompatibilityChanged = "profile.compatibility_changed"
+)
+
+// WebhookEvent is the JSON body POSTed to a profile webhook
+type WebhookEvent struct {
+	ID                     string         `json:"id"`
+	Type                   string         `json:"type"`
+	CreatedAt              time.Time      `json:"created_at"`
+	Profile                WebhookProfile `json:"profile"`
+	DatasetVersion         int            `json:"dataset_version"`
+	PreviousDatasetVersion int            `json:"previous_dataset_version"`
+	DatasetSource          string         `json:"dataset_source"` // What created the dataset version, e.g. "reload"
+	NewlyCompatible        []string       `json:"newly_compatible"`
+	NewlyIncompatible      []string       `json:"newly_incompatible"`
+	CompatibleCount        int            `json:"compatible_count"`
+}
+
+// WebhookProfile identifies the saved profile an event is about
+type WebhookProfile struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebhookDelivery records one attempt to deliver a webhook event
+type WebhookDelivery struct {
+	ID         int64     `json:"id"`
+	ProfileID  string    `json:"profile_id"`
+	EventID    string    `json:"event_id"`
+	URL        string    `json:"url"`
+	Attempt    int       `json:"attempt"`
+	Delivered  bool      `json:"delivered"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// WebhookDeliveriesResponse lists webhook delivery attempts, newest first
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int               `json:"total"`
+}
+
+// ProfileEvaluation is the outcome of a prediction for a saved profile, kept
+// to report what changed at the next prediction
+type ProfileEvaluation struct {
+	DatasetVersion int                `json:"dataset_version"`
+	EvaluatedAt    time.Time          `json:"evaluated_at"`
+	Compatible     []string           `json:"compatible"` // Compatible project names, sorted
+	Scores         map[string]float64 `json:"scores"`     // Compatibility score of every project
+	AverageScore   float64            `json:"average_score"`
+}
+
+// SavedProfileRequest creates a saved system profile
+type SavedProfileRequest struct {
+	Name   string     `json:"name" binding:"required,max=100"`
+	System SystemSpec `json:"system" binding:"required"`
+}
+
+// ProfileChanges describes how a saved profile's prediction changed since its
+// previous evaluation
+type ProfileChanges struct {
+	SinceDatasetVersion int       `json:"since_dataset_version"`
+	SinceEvaluatedAt    time.Time `json:"since_evaluated_at"`
+	ProfileImpact
+}
+
+// ProfilePredictionResponse is a fresh prediction for a saved profile.
+// Changes is null at the first prediction.
+type ProfilePredictionResponse struct {
+	Profile    SavedProfile        `json:"profile"`
+	Prediction *PredictionResponse `json:"prediction"`
+	Changes    *ProfileChanges     `json:"changes"`
+}
+
+// StoredPrediction is a persisted prediction result. It never changes once
+// stored.
+type StoredPrediction struct {
+	ID        string             `json:"id"`
+	System    SystemSpec         `json:"system"`
+	Result    PredictionResponse `json:"result"`
+	Owner     string             `json:"owner,omitempty"` // API key that stored it, not shown to readers
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"` // Unset when predictions are kept forever
+
+	DeletionTokenHash string `json:"deletion_token_hash,omitempty"` // SHA-256 of the deletion token, not shown to readers
+}
+
+// Actions recorded in the project change history
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// ProjectChangeRecord is an entry in the project change history. Before is
+// unset for creates and After for deletes.
+type ProjectChangeRecord struct {
+	ID             int64         `json:"id"`
+	Action         string        `json:"action"`
+	Project        string        `json:"project"`
+	Actor          string        `json:"actor"`
+	Timestamp      time.Time     `json:"timestamp"`
+	DatasetVersion int           `json:"dataset_version,omitempty"` // Version the edit created
+	Before         *DePINProject `json:"before,omitempty"`
+	After          *DePINProject `json:"after,omitempty"`
+}
+
+// ProjectHistoryResponse lists project changes, newest first
+type ProjectHistoryResponse struct {
+	Changes []ProjectChangeRecord `json:"changes"`
+	Total   int                   `json:"total"`
+}
+
+// APIKeyUsage reports request counters for a single API key
+type APIKeyUsage struct {
+	Name          string     `json:"name"`
+	Scopes        []string   `json:"scopes"`
+	Disabled      bool       `json:"disabled,omitempty"`
+	DailyQuota    int        `json:"daily_quota"`
+	RequestsToday int        `json:"requests_today"`
+	TotalRequests int64      `json:"total_requests"`
+	RateLimited   int64      `json:"rate_limited"`
+	QuotaExceeded int64      `json:"quota_exceeded"`
+	LastUsed      *time.Time `json:"last_used,omitempty"`
+}
+
+// APIKeyUsageResponse represents the admin response listing key usage
+type APIKeyUsageResponse struct {
+	Keys      []APIKeyUsage `json:"keys"`
+	Total     int           `json:"total"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
-	Error   string    `json:"error"`
-	Message string    `json:"message,omitempty"`
-	Code    int       `json:"code"`
-	Time    time.Time `json:"timestamp"`
+	Error      string       `json:"error"`
+	Message    string       `json:"message,omitempty"`
+	Code       int          `json:"code"`
+	RequestID  string       `json:"request_id,omitempty"`
+	RetryAfter int          `json:"retry_after,omitempty"` // Seconds, for 429 responses
+	Details    []FieldError `json:"details,omitempty"`     // Per-field validation failures
+	Time       time.Time    `json:"timestamp"`
 }
 
 // Performance ratings
@@ -113,10 +431,12 @@ const (
 
 // System categories for rating
 const (
-	SystemEntry    = "Entry Level"
-	SystemMidRange = "Mid-Range"
-	SystemHighEnd  = "High-End"
-	SystemExtreme  = "Extreme"
+	SystemEntry       = "Entry Level"
+	SystemMidRange    = "Mid-Range"
+	SystemHighEnd     = "High-End"
+	SystemExtreme     = "Extreme"
+	SystemWorkstation = "Workstation"
+	SystemServer      = "Server-Class"
 )
 
 // Compatibility thresholds
@@ -141,53 +461,83 @@ func GetPerformanceRating(score float64) string {
 	}
 }
 
-// GetSystemRating categorizes system based on specifications
+// GetSystemRating categorizes system based on specifications. Consumer
+// hardware tops out at Extreme; workstation and server hardware (many cores,
+// hundreds of GB of RAM, datacenter GPUs, multi-gigabit links) rates higher.
 func GetSystemRating(spec SystemSpec) string {
 	score := 0
 
 	// CPU scoring
-	if spec.CPUCores >= 12 {
+	switch {
+	case spec.CPUCores >= 64:
+		score += 5
+	case spec.CPUCores >= 24:
+		score += 4
+	case spec.CPUCores >= 12:
 		score += 3
-	} else if spec.CPUCores >= 8 {
+	case spec.CPUCores >= 8:
 		score += 2
-	} else if spec.CPUCores >= 4 {
+	case spec.CPUCores >= 4:
 		score += 1
 	}
 
 	// RAM scoring
-	if spec.RAMGB >= 32 {
+	switch {
+	case spec.RAMGB >= 256:
+		score += 5
+	case spec.RAMGB >= 96:
+		score += 4
+	case spec.RAMGB >= 32:
 		score += 3
-	} else if spec.RAMGB >= 16 {
+	case spec.RAMGB >= 16:
 		score += 2
-	} else if spec.RAMGB >= 8 {
+	case spec.RAMGB >= 8:
 		score += 1
 	}
 
-	// GPU scoring
-	if spec.HasGPU && spec.GPUVRAMGB >= 12 {
+	// GPU scoring, using the best card and the combined VRAM of all cards
+	bestVRAM, totalVRAM := spec.BestGPUVRAMGB(), spec.TotalGPUVRAMGB()
+	switch {
+	case !spec.HasDedicatedGPU():
+	case bestVRAM >= 80 || totalVRAM >= 160:
+		score += 5
+	case bestVRAM >= 32 || totalVRAM >= 48:
+		score += 4
+	case bestVRAM >= 12:
 		score += 3
-	} else if spec.HasGPU && spec.GPUVRAMGB >= 6 {
+	case bestVRAM >= 6:
 		score += 2
-	} else if spec.HasGPU {
+	default:
 		score += 1
 	}
 
 	// Storage scoring
-	if spec.HasSSD && spec.StorageGB >= 1000 {
+	hasSSD, storage := spec.HasSSDStorage(), spec.TotalStorageGB()
+	switch {
+	case hasSSD && storage >= 8000:
+		score += 3
+	case hasSSD && storage >= 1000:
 		score += 2
-	} else if spec.HasSSD || spec.StorageGB >= 500 {
+	case hasSSD || storage >= 500:
 		score += 1
 	}
 
 	// Network scoring
-	if spec.NetworkMbps >= 500 {
+	switch {
+	case spec.NetworkMbps >= 10000:
+		score += 3
+	case spec.NetworkMbps >= 500:
 		score += 2
-	} else if spec.NetworkMbps >= 100 {
+	case spec.NetworkMbps >= 100:
 		score += 1
 	}
 
 	// Categorize based on total score
 	switch {
+	case score >= 19:
+		return SystemServer
+	case score >= 16:
+		return SystemWorkstation
 	case score >= 12:
 		return SystemExtreme
 	case score >= 8:
@@ -198,3 +548,42 @@ func GetSystemRating(spec SystemSpec) string {
 		return SystemEntry
 	}
 }
+
+// Types of events on the change stream
+const (
+	StreamEventDatasetReloaded = "dataset.reloaded" // The CSV was imported at startup or on reload
+	StreamEventProjectChanged  = "project.changed"  // An admin edited a project
+	StreamEventSummaryChanged  = "summary.changed"  // Project summary statistics changed
+	StreamEventReset           = "stream.reset"     // Missed events cannot be replayed
+)
+
+// DatasetEvent reports a new dataset version imported from the CSV
+type DatasetEvent struct {
+	Version         int       `json:"version"`
+	PreviousVersion int       `json:"previous_version"`
+	Source          string    `json:"source"`
+	Projects        int       `json:"projects"`
+	Checksum        string    `json:"checksum"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// SummaryEvent reports changed project summary statistics
+type SummaryEvent struct {
+	DatasetVersion int            `json:"dataset_version"`
+	Summary        ProjectSummary `json:"summary"`
+	Previous       ProjectSummary `json:"previous"`
+}
+
+// StreamResetEvent tells a resuming client that events were missed and it
+// should fetch current state again
+type StreamResetEvent struct {
+	Reason string `json:"reason"`
+}
+
+// GraphQLRequest is a GraphQL query sent as a JSON body, or as query
+// parameters with variables JSON-encoded
+type GraphQLRequest struct {
+	Query         string                 `json:"query" form:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables,omitempty" form:"-"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import "testing"
+
+func TestGetSystemRating(t *testing.T) {
+	dualGPU := []GPU{{VRAMGB: 24}, {VRAMGB: 24}}
+	eightH100 := make([]GPU, 8)
+	for i := range eightH100 {
+		eightH100[i] = GPU{Model: "H100", VRAMGB: 80}
+	}
+
+	tests := []struct {
+		name string
+		spec SystemSpec
+		want string
+	}{
+		{"netbook", SystemSpec{CPUCores: 2, RAMGB: 4, StorageGB: 250, NetworkMbps: 50}, SystemEntry},
+		{"office desktop", SystemSpec{CPUCores: 4, RAMGB: 16, StorageGB: 500, HasSSD: true, NetworkMbps: 100}, SystemMidRange},
+		{"gaming desktop", SystemSpec{CPUCores: 8, RAMGB: 16, StorageGB: 500, HasSSD: true, HasGPU: true, GPUVRAMGB: 8, NetworkMbps: 500}, SystemHighEnd},
+		{"enthusiast desktop", SystemSpec{CPUCores: 12, RAMGB: 32, StorageGB: 1000, HasSSD: true, HasGPU: true, GPUVRAMGB: 12, NetworkMbps: 500}, SystemExtreme},
+		{"single-GPU workstation", SystemSpec{CPUCores: 24, RAMGB: 128, StorageGB: 2000, HasSSD: true, GPUs: dualGPU[:1], NetworkMbps: 1000}, SystemExtreme},
+		{"dual-GPU workstation", SystemSpec{CPUCores: 24, RAMGB: 128, StorageGB: 2000, HasSSD: true, GPUs: dualGPU, NetworkMbps: 1000}, SystemWorkstation},
+		{"GPU server", SystemSpec{CPUCores: 64, RAMGB: 512, StorageGB: 8000, HasSSD: true, GPUs: eightH100, NetworkMbps: 10000}, SystemServer},
+	}
+	for _, tt := range tests {
+		if got := GetSystemRating(tt.spec); got != tt.want {
+			t.Errorf("%s: rating = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGPUCards(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        SystemSpec
+		cards       int
+		best, total int
+	}{
+		{"none", SystemSpec{}, 0, 0, 0},
+		{"flat fields", SystemSpec{HasGPU: true, GPUVRAMGB: 8}, 1, 8, 8},
+		{"VRAM without GPU", SystemSpec{GPUVRAMGB: 8}, 0, 0, 0},
+		{"list wins", SystemSpec{HasGPU: true, GPUVRAMGB: 8, GPUs: []GPU{{VRAMGB: 24}, {VRAMGB: 16}}}, 2, 24, 40},
+	}
+	for _, tt := range tests {
+		if got := len(tt.spec.GPUCards()); got != tt.cards {
+			t.Errorf("%s: %d cards, want %d", tt.name, got, tt.cards)
+		}
+		if got := tt.spec.HasDedicatedGPU(); got != (tt.cards > 0) {
+			t.Errorf("%s: HasDedicatedGPU = %v", tt.name, got)
+		}
+		if best, total := tt.spec.BestGPUVRAMGB(), tt.spec.TotalGPUVRAMGB(); best != tt.best || total != tt.total {
+			t.Errorf("%s: VRAM best %d total %d, want %d and %d", tt.name, best, total, tt.best, tt.total)
+		}
+	}
+}
+
+func TestDrives(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          SystemSpec
+		drives        int
+		ssd           bool
+		total         int
+		best, bestSSD int
+	}{
+		{"none", SystemSpec{}, 0, false, 0, 0, 0},
+		{"flat HDD", SystemSpec{StorageGB: 2000}, 1, false, 2000, 2000, 0},
+		{"flat SSD", SystemSpec{StorageGB: 500, HasSSD: true}, 1, true, 500, 500, 500},
+		{"list wins", SystemSpec{StorageGB: 100, StorageDevices: []StorageDevice{
+			{Type: StorageNVMe, CapacityGB: 1000, FreeGB: 400},
+			{Type: StorageHDD, CapacityGB: 8000},
+		}}, 2, true, 9000, 8000, 400},
+	}
+	for _, tt := range tests {
+		if got := len(tt.spec.Drives()); got != tt.drives {
+			t.Errorf("%s: %d drives, want %d", tt.name, got, tt.drives)
+		}
+		if got := tt.spec.HasSSDStorage(); got != tt.ssd {
+			t.Errorf("%s: HasSSDStorage = %v, want %v", tt.name, got, tt.ssd)
+		}
+		if got := tt.spec.TotalStorageGB(); got != tt.total {
+			t.Errorf("%s: TotalStorageGB = %d, want %d", tt.name, got, tt.total)
+		}
+		if best, bestSSD := tt.spec.AvailableStorageGB(false), tt.spec.AvailableStorageGB(true); best != tt.best || bestSSD != tt.bestSSD {
+			t.Errorf("%s: available %d, on SSD %d, want %d and %d", tt.name, best, bestSSD, tt.best, tt.bestSSD)
+		}
+	}
+}
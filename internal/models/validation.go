@@ -0,0 +1,314 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SupportedOperatingSystems lists the accepted SystemSpec.OS values
+var SupportedOperatingSystems = []string{"Windows", "Linux", "macOS"}
+
+// Range is an inclusive integer bound
+type Range struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// SpecLimits bounds the numeric SystemSpec fields. It is the single source of
+// the ranges used by request validation, /docs and the OpenAPI document.
+type SpecLimits struct {
+	CPUCores    Range `json:"cpu_cores"`
+	RAMGB       Range `json:"ram_gb"`
+	StorageGB   Range `json:"storage_gb"`
+	GPUVRAMGB   Range `json:"gpu_vram_gb"` // Per card
+	GPUs        Range `json:"gpus"`        // Number of cards in the gpus list
+	NetworkMbps Range `json:"network_mbps"`
+
+	StorageDevices Range `json:"storage_devices"` // Number of drives in the storage_devices list
+}
+
+// DefaultSpecLimits returns the default SystemSpec ranges. They are generous
+// enough for server-class hosts: dual-socket CPUs, terabytes of RAM, 8-GPU
+// nodes with datacenter cards and 400Gbps links.
+func DefaultSpecLimits() SpecLimits {
+	return SpecLimits{
+		CPUCores:    Range{Min: 1, Max: 1024},
+		RAMGB:       Range{Min: 1, Max: 8192},
+		StorageGB:   Range{Min: 32, Max: 1048576},
+		GPUVRAMGB:   Range{Min: 0, Max: 256},
+		GPUs:        Range{Min: 0, Max: 16},
+		NetworkMbps: Range{Min: 1, Max: 400000},
+
+		StorageDevices: Range{Min: 0, Max: 64},
+	}
+}
+
+// Set replaces the range for a field named by its JSON name
+func (l *SpecLimits) Set(field string, r Range) error {
+	if r.Min < 0 || r.Min > r.Max {
+		return fmt.Errorf("invalid range %d:%d for %s", r.Min, r.Max, field)
+	}
+
+	switch field {
+	case "cpu_cores":
+		l.CPUCores = r
+	case "ram_gb":
+		l.RAMGB = r
+	case "storage_gb":
+		l.StorageGB = r
+	case "gpu_vram_gb":
+		l.GPUVRAMGB = r
+	case "gpus":
+		l.GPUs = r
+	case "network_mbps":
+		l.NetworkMbps = r
+	case "storage_devices":
+		l.StorageDevices = r
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+// FieldRule describes validation for a single SystemSpec field, by JSON name
+type FieldRule struct {
+	Field    string
+	Required bool
+	Range    *Range
+	OneOf    []string
+}
+
+// SystemSpecRules returns the validation rules for SystemSpec under limits
+func (l SpecLimits) SystemSpecRules() []FieldRule {
+	return []FieldRule{
+		{Field: "cpu_cores", Required: true, Range: &l.CPUCores},
+		{Field: "ram_gb", Required: true, Range: &l.RAMGB},
+		{Field: "storage_gb", Range: &l.StorageGB}, // Required unless storage_devices is set
+		{Field: "gpu_vram_gb", Range: &l.GPUVRAMGB},
+		{Field: "network_mbps", Required: true, Range: &l.NetworkMbps},
+		{Field: "os", Required: true, OneOf: SupportedOperatingSystems},
+	}
+}
+
+// Validation rule names reported in FieldError.Rule
+const (
+	RuleRequired    = "required"
+	RuleMin         = "min"
+	RuleMax         = "max"
+	RuleOneOf       = "oneof"
+	RuleType        = "type"
+	RuleConsistency = "consistency"
+)
+
+// FieldError describes a single invalid or suspicious request field
+type FieldError struct {
+	Field   string      `json:"field"`
+	Rule    string      `json:"rule"`
+	Message string      `json:"message"`
+	Min     *int        `json:"min,omitempty"`
+	Max     *int        `json:"max,omitempty"`
+	Allowed []string    `json:"allowed,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// PrefixFields prepends prefix to the field paths of fields, e.g. to report
+// SystemSpec errors as "system.cpu_cores"
+func PrefixFields(prefix string, fields []FieldError) []FieldError {
+	for i := range fields {
+		fields[i].Field = prefix + fields[i].Field
+	}
+	return fields
+}
+
+// ssdPlausibleMaxGB is the largest total storage treated as plausible for a
+// system whose only storage is SSD
+const ssdPlausibleMaxGB = 16384
+
+// ValidateSystemSpec checks spec against limits and for internal consistency.
+// Errors make the spec unusable; warnings flag suspicious but accepted input.
+func ValidateSystemSpec(spec SystemSpec, limits SpecLimits) (errs []FieldError, warnings []FieldError) {
+	values := map[string]interface{}{
+		"cpu_cores":    spec.CPUCores,
+		"ram_gb":       spec.RAMGB,
+		"storage_gb":   spec.StorageGB,
+		"gpu_vram_gb":  spec.GPUVRAMGB,
+		"network_mbps": spec.NetworkMbps,
+		"os":           spec.OS,
+	}
+
+	for _, rule := range limits.SystemSpecRules() {
+		if rule.Field == "storage_gb" && spec.StorageGB == 0 {
+			if len(spec.StorageDevices) == 0 {
+				errs = append(errs, FieldError{Field: rule.Field, Rule: RuleRequired, Message: "storage_gb is required unless storage_devices is set"})
+			}
+			continue
+		}
+		if err, ok := checkRule(rule, values[rule.Field]); !ok {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, countError("gpus", len(spec.GPUs), limits.GPUs)...)
+	for i, gpu := range spec.GPUs {
+		rule := FieldRule{Field: fmt.Sprintf("gpus[%d].vram_gb", i), Required: true, Range: &limits.GPUVRAMGB}
+		if err, ok := checkRule(rule, gpu.VRAMGB); !ok {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, countError("storage_devices", len(spec.StorageDevices), limits.StorageDevices)...)
+	capacity := Range{Min: 1, Max: limits.StorageGB.Max}
+	for i, drive := range spec.StorageDevices {
+		field := fmt.Sprintf("storage_devices[%d]", i)
+		typeRule := FieldRule{Field: field + ".type", Required: true, OneOf: StorageDeviceTypes}
+		if err, ok := checkRule(typeRule, drive.Type); !ok {
+			errs = append(errs, err)
+		}
+		capacityRule := FieldRule{Field: field + ".capacity_gb", Required: true, Range: &capacity}
+		if err, ok := checkRule(capacityRule, drive.CapacityGB); !ok {
+			errs = append(errs, err)
+		}
+		if drive.FreeGB < 0 {
+			zero := 0
+			errs = append(errs, FieldError{
+				Field:   field + ".free_gb",
+				Rule:    RuleMin,
+				Message: fmt.Sprintf("%s.free_gb must be at least 0", field),
+				Min:     &zero,
+				Value:   drive.FreeGB,
+			})
+		} else if drive.FreeGB > drive.CapacityGB {
+			errs = append(errs, FieldError{
+				Field:   field + ".free_gb",
+				Rule:    RuleMax,
+				Message: fmt.Sprintf("%s.free_gb cannot exceed capacity_gb (%d)", field, drive.CapacityGB),
+				Max:     &drive.CapacityGB,
+				Value:   drive.FreeGB,
+			})
+		}
+	}
+
+	// Consistency checks between fields
+	if len(spec.GPUs) > 0 && (spec.HasGPU || spec.GPUVRAMGB > 0) {
+		warnings = append(warnings, FieldError{
+			Field:   "gpus",
+			Rule:    RuleConsistency,
+			Message: "gpus is set, so has_gpu and gpu_vram_gb are ignored",
+		})
+	}
+
+	if len(spec.GPUs) == 0 && spec.HasGPU && spec.GPUVRAMGB == 0 {
+		warnings = append(warnings, FieldError{
+			Field:   "gpu_vram_gb",
+			Rule:    RuleConsistency,
+			Message: "has_gpu is true but gpu_vram_gb is 0; projects with a VRAM minimum will be reported incompatible",
+			Value:   spec.GPUVRAMGB,
+		})
+	}
+
+	if len(spec.GPUs) == 0 && !spec.HasGPU && spec.GPUVRAMGB > 0 {
+		warnings = append(warnings, FieldError{
+			Field:   "has_gpu",
+			Rule:    RuleConsistency,
+			Message: "gpu_vram_gb is set but has_gpu is false; integrated graphics do not satisfy dedicated GPU requirements",
+			Value:   spec.HasGPU,
+		})
+	}
+
+	if len(spec.StorageDevices) > 0 && (spec.StorageGB > 0 || spec.HasSSD) {
+		warnings = append(warnings, FieldError{
+			Field:   "storage_devices",
+			Rule:    RuleConsistency,
+			Message: "storage_devices is set, so storage_gb and has_ssd are ignored",
+		})
+	}
+
+	if len(spec.StorageDevices) == 0 && spec.HasSSD && spec.StorageGB > ssdPlausibleMaxGB {
+		warnings = append(warnings, FieldError{
+			Field:   "storage_gb",
+			Rule:    RuleConsistency,
+			Message: fmt.Sprintf("%dGB is unusually large for SSD-only storage; has_ssd is treated as applying to all of it", spec.StorageGB),
+			Value:   spec.StorageGB,
+		})
+	}
+
+	return errs, warnings
+}
+
+// countError reports a list with fewer or more entries than the limit allows
+func countError(field string, count int, limit Range) []FieldError {
+	lo, hi := limit.Min, limit.Max
+	switch {
+	case count < lo:
+		return []FieldError{{
+			Field:   field,
+			Rule:    RuleMin,
+			Message: fmt.Sprintf("%s must list at least %d entries", field, lo),
+			Min:     &lo,
+			Max:     &hi,
+			Value:   count,
+		}}
+	case count > hi:
+		return []FieldError{{
+			Field:   field,
+			Rule:    RuleMax,
+			Message: fmt.Sprintf("%s must list at most %d entries", field, hi),
+			Min:     &lo,
+			Max:     &hi,
+			Value:   count,
+		}}
+	}
+	return nil
+}
+
+// checkRule validates a single value, returning the failure if any
+func checkRule(rule FieldRule, value interface{}) (FieldError, bool) {
+	switch v := value.(type) {
+	case int:
+		if rule.Required && v == 0 && (rule.Range == nil || rule.Range.Min > 0) {
+			return FieldError{Field: rule.Field, Rule: RuleRequired, Message: rule.Field + " is required"}, false
+		}
+		if rule.Range == nil {
+			return FieldError{}, true
+		}
+
+		lo, hi := rule.Range.Min, rule.Range.Max
+		if v < lo {
+			return FieldError{
+				Field:   rule.Field,
+				Rule:    RuleMin,
+				Message: fmt.Sprintf("%s must be between %d and %d", rule.Field, lo, hi),
+				Min:     &lo,
+				Max:     &hi,
+				Value:   v,
+			}, false
+		}
+		if v > hi {
+			return FieldError{
+				Field:   rule.Field,
+				Rule:    RuleMax,
+				Message: fmt.Sprintf("%s must be between %d and %d", rule.Field, lo, hi),
+				Min:     &lo,
+				Max:     &hi,
+				Value:   v,
+			}, false
+		}
+
+	case string:
+		if rule.Required && v == "" {
+			return FieldError{Field: rule.Field, Rule: RuleRequired, Message: rule.Field + " is required", Allowed: rule.OneOf}, false
+		}
+		if len(rule.OneOf) > 0 && v != "" && !slices.Contains(rule.OneOf, v) {
+			return FieldError{
+				Field:   rule.Field,
+				Rule:    RuleOneOf,
+				Message: fmt.Sprintf("%s must be one of %s", rule.Field, strings.Join(rule.OneOf, ", ")),
+				Allowed: rule.OneOf,
+				Value:   v,
+			}, false
+		}
+	}
+
+	return FieldError{}, true
+}
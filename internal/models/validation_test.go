@@ -0,0 +1,243 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+// validSpec is a spec passing validation without warnings
+func validSpec() SystemSpec {
+	return SystemSpec{CPUCores: 8, RAMGB: 32, StorageGB: 1000, HasSSD: true, NetworkMbps: 500, OS: "Linux"}
+}
+
+// fieldRules lists "field:rule" for each entry of fields
+func fieldRules(fields []FieldError) []string {
+	rules := make([]string, len(fields))
+	for i, field := range fields {
+		rules[i] = field.Field + ":" + field.Rule
+	}
+	return rules
+}
+
+func TestValidateSystemSpecErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SystemSpec)
+		want   []string
+	}{
+		{"valid", func(*SystemSpec) {}, nil},
+		{"missing fields", func(s *SystemSpec) { *s = SystemSpec{} }, []string{
+			"cpu_cores:required", "ram_gb:required", "storage_gb:required", "network_mbps:required", "os:required",
+		}},
+		{"below minimum", func(s *SystemSpec) { s.StorageGB = 16 }, []string{"storage_gb:min"}},
+		{"above maximum", func(s *SystemSpec) { s.CPUCores = 2048 }, []string{"cpu_cores:max"}},
+		{"unknown OS", func(s *SystemSpec) { s.OS = "linux" }, []string{"os:oneof"}},
+		{"several fields", func(s *SystemSpec) { s.RAMGB = -1; s.NetworkMbps = 500000 }, []string{"ram_gb:min", "network_mbps:max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			tt.modify(&spec)
+			errs, _ := ValidateSystemSpec(spec, DefaultSpecLimits())
+			if got := fieldRules(errs); !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSystemSpecErrorDetails(t *testing.T) {
+	spec := validSpec()
+	spec.CPUCores = 2048
+	spec.OS = "BeOS"
+
+	errs, _ := ValidateSystemSpec(spec, DefaultSpecLimits())
+	if len(errs) != 2 {
+		t.Fatalf("errors = %v, want 2", fieldRules(errs))
+	}
+	cpu, os := errs[0], errs[1]
+	if cpu.Min == nil || *cpu.Min != 1 || cpu.Max == nil || *cpu.Max != 1024 || cpu.Value != 2048 {
+		t.Errorf("cpu_cores error = %+v", cpu)
+	}
+	if !slices.Equal(os.Allowed, SupportedOperatingSystems) || os.Value != "BeOS" {
+		t.Errorf("os error = %+v", os)
+	}
+}
+
+func TestValidateSystemSpecWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SystemSpec)
+		want   []string
+	}{
+		{"GPU without VRAM", func(s *SystemSpec) { s.HasGPU = true }, []string{"gpu_vram_gb:consistency"}},
+		{"VRAM without GPU", func(s *SystemSpec) { s.GPUVRAMGB = 8 }, []string{"has_gpu:consistency"}},
+		{"huge SSD", func(s *SystemSpec) { s.StorageGB = 20000 }, []string{"storage_gb:consistency"}},
+		{"huge HDD", func(s *SystemSpec) { s.StorageGB = 20000; s.HasSSD = false }, nil},
+		{"consistent GPU", func(s *SystemSpec) { s.HasGPU = true; s.GPUVRAMGB = 8 }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			tt.modify(&spec)
+			errs, warnings := ValidateSystemSpec(spec, DefaultSpecLimits())
+			if len(errs) > 0 {
+				t.Fatalf("errors = %v, want none", fieldRules(errs))
+			}
+			if got := fieldRules(warnings); !slices.Equal(got, tt.want) {
+				t.Errorf("warnings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixFields(t *testing.T) {
+	fields := PrefixFields("system.", []FieldError{{Field: "cpu_cores"}, {Field: "gpus[0].vram_gb"}})
+	if got := []string{fields[0].Field, fields[1].Field}; !slices.Equal(got, []string{"system.cpu_cores", "system.gpus[0].vram_gb"}) {
+		t.Errorf("fields = %v", got)
+	}
+	if PrefixFields("system.", nil) != nil {
+		t.Error("nil fields not kept nil")
+	}
+}
+
+func TestSpecLimitsSet(t *testing.T) {
+	limits := DefaultSpecLimits()
+	if err := limits.Set("cpu_cores", Range{Min: 1, Max: 4096}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if limits.CPUCores.Max != 4096 {
+		t.Errorf("cpu_cores = %+v, want max 4096", limits.CPUCores)
+	}
+
+	for _, tt := range []struct {
+		field string
+		r     Range
+	}{
+		{"cpu_cores", Range{Min: 8, Max: 4}},
+		{"ram_gb", Range{Min: -1, Max: 4}},
+		{"bogus", Range{Min: 1, Max: 4}},
+	} {
+		if err := limits.Set(tt.field, tt.r); err == nil {
+			t.Errorf("Set(%s, %+v) accepted", tt.field, tt.r)
+		}
+	}
+}
+
+func TestValidateSystemSpecServerClass(t *testing.T) {
+	spec := SystemSpec{CPUCores: 256, RAMGB: 4096, StorageGB: 500000, NetworkMbps: 400000, OS: "Linux"}
+	for i := 0; i < 8; i++ {
+		spec.GPUs = append(spec.GPUs, GPU{Model: "H200", VRAMGB: 141})
+	}
+	if errs, _ := ValidateSystemSpec(spec, DefaultSpecLimits()); len(errs) > 0 {
+		t.Errorf("server rejected: %v", fieldRules(errs))
+	}
+
+	limits := DefaultSpecLimits()
+	limits.GPUs = Range{Min: 0, Max: 4}
+	spec.GPUs[2].VRAMGB = 512
+	errs, _ := ValidateSystemSpec(spec, limits)
+	if got, want := fieldRules(errs), []string{"gpus:max", "gpus[2].vram_gb:max"}; !slices.Equal(got, want) {
+		t.Errorf("errors = %v, want %v", got, want)
+	}
+}
+
+func TestValidateSystemSpecGPUListWarning(t *testing.T) {
+	spec := validSpec()
+	spec.HasGPU = true
+	spec.GPUVRAMGB = 8
+	spec.GPUs = []GPU{{VRAMGB: 24}}
+
+	_, warnings := ValidateSystemSpec(spec, DefaultSpecLimits())
+	if got := fieldRules(warnings); !slices.Equal(got, []string{"gpus:consistency"}) {
+		t.Errorf("warnings = %v, want gpus:consistency", got)
+	}
+}
+
+func TestValidateStorageDevices(t *testing.T) {
+	tests := []struct {
+		name     string
+		devices  []StorageDevice
+		errs     []string
+		warnings []string
+	}{
+		{"valid", []StorageDevice{{Type: StorageNVMe, CapacityGB: 1000, FreeGB: 200}, {Type: StorageHDD, CapacityGB: 8000}}, nil, []string{"storage_devices:consistency"}},
+		{"unknown type", []StorageDevice{{Type: "Tape", CapacityGB: 1000}}, []string{"storage_devices[0].type:oneof"}, nil},
+		{"missing fields", []StorageDevice{{}}, []string{"storage_devices[0].type:required", "storage_devices[0].capacity_gb:required"}, nil},
+		{"free over capacity", []StorageDevice{{Type: StorageSSD, CapacityGB: 500, FreeGB: 600}}, []string{"storage_devices[0].free_gb:max"}, nil},
+		{"negative free", []StorageDevice{{Type: StorageSSD, CapacityGB: 500, FreeGB: -1}}, []string{"storage_devices[0].free_gb:min"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			spec.StorageDevices = tt.devices
+			errs, warnings := ValidateSystemSpec(spec, DefaultSpecLimits())
+			if got := fieldRules(errs); !slices.Equal(got, tt.errs) {
+				t.Errorf("errors = %v, want %v", got, tt.errs)
+			}
+			if len(tt.errs) == 0 {
+				if got := fieldRules(warnings); !slices.Equal(got, tt.warnings) {
+					t.Errorf("warnings = %v, want %v", got, tt.warnings)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateStorageDevicesReplaceStorageGB(t *testing.T) {
+	spec := validSpec()
+	spec.StorageGB = 0
+	spec.HasSSD = false
+	spec.StorageDevices = []StorageDevice{{Type: StorageSSD, CapacityGB: 500}}
+
+	errs, warnings := ValidateSystemSpec(spec, DefaultSpecLimits())
+	if len(errs) > 0 || len(warnings) > 0 {
+		t.Errorf("errors = %v, warnings = %v, want none", fieldRules(errs), fieldRules(warnings))
+	}
+
+	limits := DefaultSpecLimits()
+	limits.StorageDevices = Range{Min: 0, Max: 1}
+	spec.StorageDevices = append(spec.StorageDevices, StorageDevice{Type: StorageHDD, CapacityGB: 4000})
+	errs, _ = ValidateSystemSpec(spec, limits)
+	if got := fieldRules(errs); !slices.Equal(got, []string{"storage_devices:max"}) {
+		t.Errorf("errors = %v, want storage_devices:max", got)
+	}
+}
+
+func TestListCountLimits(t *testing.T) {
+	limits := DefaultSpecLimits()
+	limits.GPUs = Range{Min: 1, Max: 2}
+	limits.StorageDevices = Range{Min: 1, Max: 1}
+	drive := StorageDevice{Type: StorageSSD, CapacityGB: 500}
+
+	tests := []struct {
+		name   string
+		gpus   int
+		drives int
+		want   []string
+	}{
+		{"within limits", 1, 1, nil},
+		{"too few", 0, 0, []string{"gpus:min", "storage_devices:min"}},
+		{"too many", 3, 2, []string{"gpus:max", "storage_devices:max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			for i := 0; i < tt.gpus; i++ {
+				spec.GPUs = append(spec.GPUs, GPU{VRAMGB: 8})
+			}
+			for i := 0; i < tt.drives; i++ {
+				spec.StorageDevices = append(spec.StorageDevices, drive)
+			}
+			errs, _ := ValidateSystemSpec(spec, limits)
+			if got := fieldRules(errs); !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+			for _, err := range errs {
+				if *err.Min != 1 || err.Value == nil {
+					t.Errorf("%s: min = %d, value = %v", err.Field, *err.Min, err.Value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]*PathItem `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components holds reusable schemas
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// PathItem is a single operation on a path
+type PathItem struct {
+	Summary     string               `json:"summary,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's JSON body
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a single response
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps a schema for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route is a registered method and path, in gin's ":param" syntax
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Operation documents a route. Request and response values are example
+// instances of the Go types used on the wire; a nil value means no JSON body.
+type Operation struct {
+	Summary     string
+	Tags        []string
+	Parameters  []Parameter
+	Request     interface{}
+	RequestType string // Request content type, defaults to application/json
+	Responses   map[int]interface{}
+	ContentType string // Response content type, defaults to application/json
+}
+
+// Generate builds an OpenAPI document for routes, describing each with the
+// matching entry in operations keyed by "METHOD /path"
+func Generate(info Info, routes []Route, operations map[string]Operation) *Document {
+	registry := newSchemaRegistry()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]*PathItem),
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	for _, route := range routes {
+		path, pathParams := convertPath(route.Path)
+		op, documented := operations[route.Method+" "+route.Path]
+
+		item := &PathItem{
+			Summary:     op.Summary,
+			OperationID: operationID(route),
+			Tags:        op.Tags,
+			Parameters:  append(pathParams, op.Parameters...),
+			Responses:   make(map[string]*Response),
+		}
+
+		if op.Request != nil {
+			requestType := op.RequestType
+			if requestType == "" {
+				requestType = "application/json"
+			}
+			item.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{requestType: {Schema: registry.ref(op.Request)}},
+			}
+		}
+
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+
+		for status, body := range op.Responses {
+			response := &Response{Description: http.StatusText(status)}
+			if body != nil {
+				response.Content = map[string]MediaType{contentType: {Schema: registry.ref(body)}}
+			}
+			item.Responses[strconv.Itoa(status)] = response
+		}
+
+		if !documented || len(item.Responses) == 0 {
+			item.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*PathItem)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = item
+	}
+
+	doc.Components.Schemas = registry.components
+	return doc
+}
+
+// Schema returns the component schema generated for the named type
+func (d *Document) Schema(name string) *Schema {
+	return d.Components.Schemas[name]
+}
+
+// convertPath rewrites gin ":param" and "*param" segments to "{param}"
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives a stable identifier such as "post_api_v1_predict"
+func operationID(route Route) string {
+	replacer := strings.NewReplacer("/", "_", ":", "", "*", "", "{", "", "}", "", ".", "_")
+	id := strings.Trim(replacer.Replace(route.Path), "_")
+	if id == "" {
+		id = "root"
+	}
+	return strings.ToLower(route.Method) + "_" + id
+}
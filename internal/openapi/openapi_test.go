@@ -0,0 +1,357 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/api"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/openapi"
+	"github.com/simoncrean/api-predict/internal/server"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestTypes are the JSON request bodies the API binds, by schema name
+var requestTypes = map[string]reflect.Type{
+	"PredictionRequest":   reflect.TypeOf(models.PredictionRequest{}),
+	"SavedProfileRequest": reflect.TypeOf(models.SavedProfileRequest{}),
+	"WebhookRequest":      reflect.TypeOf(models.WebhookRequest{}),
+	"GraphQLRequest":      reflect.TypeOf(models.GraphQLRequest{}),
+	"DePINProject":        reflect.TypeOf(models.DePINProject{}),
+}
+
+// limitTypes have no binding tags; their rules come from SpecLimits and are
+// checked by TestSystemSpecMatchesValidation
+var limitTypes = []string{"SystemSpec", "GPU", "StorageDevice"}
+
+// generate builds the document the server publishes, from the real routes
+func generate(t *testing.T, limits models.SpecLimits) *openapi.Document {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router, err := server.NewRouter(server.Config{SpecLimits: limits}, server.Components{})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return api.GenerateOpenAPI(router, limits)
+}
+
+func TestRequestBodiesCovered(t *testing.T) {
+	doc := generate(t, models.DefaultSpecLimits())
+
+	for path, methods := range doc.Paths {
+		for method, item := range methods {
+			if item.RequestBody == nil {
+				continue
+			}
+			media, ok := item.RequestBody.Content["application/json"]
+			if !ok {
+				continue
+			}
+			name := strings.TrimPrefix(media.Schema.Ref, "#/components/schemas/")
+			if _, ok := requestTypes[name]; !ok {
+				t.Errorf("%s %s takes %q, which requestTypes does not check", method, path, name)
+			}
+		}
+	}
+}
+
+func TestSchemasMatchBindingTags(t *testing.T) {
+	doc := generate(t, models.DefaultSpecLimits())
+
+	for name, requestType := range requestTypes {
+		checkBindingTags(t, doc, name, requestType)
+	}
+}
+
+// checkBindingTags compares a component schema with the binding tags of its
+// Go type, then does the same for the nested named structs
+func checkBindingTags(t *testing.T, doc *openapi.Document, name string, structType reflect.Type) {
+	t.Helper()
+
+	schema := doc.Schema(name)
+	if schema == nil {
+		t.Errorf("schema %s is missing", name)
+		return
+	}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		property, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if property == "" || property == "-" {
+			continue
+		}
+		rules := parseBinding(field.Tag.Get("binding"))
+
+		_, required := rules["required"]
+		inSpec := slices.Contains(schema.Required, property)
+		if !slices.Contains(limitTypes, name) && required != inSpec {
+			t.Errorf("%s.%s: required in spec = %v, binding tag says %v", name, property, inSpec, required)
+		}
+
+		propertySchema := schema.Properties[property]
+		if propertySchema == nil {
+			t.Errorf("%s.%s is missing from the schema", name, property)
+			continue
+		}
+		checkBound(t, name+"."+property, propertySchema, rules["min"], true)
+		checkBound(t, name+"."+property, propertySchema, rules["max"], false)
+
+		nested := field.Type
+		for nested.Kind() == reflect.Ptr || nested.Kind() == reflect.Slice {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.PkgPath() == structType.PkgPath() {
+			checkBindingTags(t, doc, nested.Name(), nested)
+		}
+	}
+}
+
+// checkBound checks a min or max binding rule against the property schema
+func checkBound(t *testing.T, field string, schema *openapi.Schema, rule string, lower bool) {
+	t.Helper()
+	if rule == "" {
+		return
+	}
+	want, err := strconv.Atoi(rule)
+	if err != nil {
+		t.Fatalf("%s: invalid bound %q", field, rule)
+	}
+
+	var got *int
+	switch schema.Type {
+	case "string":
+		got = schema.MaxLength
+		if lower {
+			got = schema.MinLength
+		}
+	default:
+		bound := schema.Maximum
+		if lower {
+			bound = schema.Minimum
+		}
+		if bound != nil {
+			n := int(*bound)
+			got = &n
+		}
+	}
+
+	if got == nil || *got != want {
+		t.Errorf("%s: spec bound (lower=%v) = %v, binding tag says %d", field, lower, got, want)
+	}
+}
+
+// parseBinding splits a binding tag into rule names and values, treating
+// gte and lte as min and max
+func parseBinding(tag string) map[string]string {
+	rules := make(map[string]string)
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "gte":
+			key = "min"
+		case "lte":
+			key = "max"
+		}
+		if key != "" {
+			rules[key] = value
+		}
+	}
+	return rules
+}
+
+func TestPredictionRequestRequiresSystem(t *testing.T) {
+	doc := generate(t, models.DefaultSpecLimits())
+
+	schema := doc.Schema("PredictionRequest")
+	if schema == nil || !slices.Contains(schema.Required, "system") {
+		t.Fatalf("PredictionRequest schema = %+v, want system required", schema)
+	}
+	if ref := schema.Properties["system"].Ref; ref != "#/components/schemas/SystemSpec" {
+		t.Errorf("system property refers to %q", ref)
+	}
+}
+
+func TestSystemSpecMatchesValidation(t *testing.T) {
+	// Non-default limits show the published ranges follow the configuration
+	limits := models.DefaultSpecLimits()
+	limits.CPUCores = models.Range{Min: 2, Max: 64}
+	limits.NetworkMbps = models.Range{Min: 5, Max: 10000}
+	limits.GPUs = models.Range{Min: 1, Max: 4}
+
+	schema := generate(t, limits).Schema("SystemSpec")
+	if schema == nil {
+		t.Fatal("schema SystemSpec is missing")
+	}
+
+	for _, rule := range limits.SystemSpecRules() {
+		property := schema.Properties[rule.Field]
+		if property == nil {
+			t.Errorf("SystemSpec.%s is missing from the schema", rule.Field)
+			continue
+		}
+		if got := slices.Contains(schema.Required, rule.Field); got != rule.Required {
+			t.Errorf("SystemSpec.%s: required in spec = %v, rule says %v", rule.Field, got, rule.Required)
+		}
+		if !slices.Equal(property.Enum, rule.OneOf) {
+			t.Errorf("SystemSpec.%s: enum = %v, rule says %v", rule.Field, property.Enum, rule.OneOf)
+		}
+		if rule.Range == nil {
+			continue
+		}
+		if property.Minimum == nil || property.Maximum == nil {
+			t.Errorf("SystemSpec.%s: spec has no range, rule says %v", rule.Field, *rule.Range)
+			continue
+		}
+		lo, hi := int(*property.Minimum), int(*property.Maximum)
+		if lo != rule.Range.Min || hi != rule.Range.Max {
+			t.Errorf("SystemSpec.%s: spec range %d-%d, rule says %v", rule.Field, lo, hi, *rule.Range)
+		}
+
+		// The published bounds are exactly what validation accepts
+		for value, valid := range map[int]bool{lo - 1: false, lo: true, hi: true, hi + 1: false} {
+			errs := validateWith(t, rule.Field, value, limits)
+			if rejected := slices.Contains(errs, rule.Field); rejected == valid {
+				t.Errorf("SystemSpec.%s = %d: rejected = %v, spec range is %d-%d", rule.Field, value, rejected, lo, hi)
+			}
+		}
+	}
+
+	gpu := generate(t, limits).Schema("GPU")
+	if vram := gpu.Properties["vram_gb"]; !slices.Contains(gpu.Required, "vram_gb") || vram.Minimum == nil || vram.Maximum == nil ||
+		int(*vram.Minimum) != limits.GPUVRAMGB.Min || int(*vram.Maximum) != limits.GPUVRAMGB.Max {
+		t.Errorf("GPU.vram_gb schema = %+v, want required with range %v", vram, limits.GPUVRAMGB)
+	}
+
+	if gpus := schema.Properties["gpus"]; gpus.MaxItems == nil || *gpus.MaxItems != limits.GPUs.Max || gpus.MinItems == nil || *gpus.MinItems != limits.GPUs.Min {
+		t.Errorf("SystemSpec.gpus items = %v-%v, want %v", gpus.MinItems, gpus.MaxItems, limits.GPUs)
+	}
+	if drives := schema.Properties["storage_devices"]; drives.MaxItems == nil || *drives.MaxItems != limits.StorageDevices.Max {
+		t.Errorf("SystemSpec.storage_devices maxItems = %v, want %d", drives.MaxItems, limits.StorageDevices.Max)
+	}
+}
+
+// validateWith validates a valid spec with one field replaced, returning the
+// names of the rejected fields
+func validateWith(t *testing.T, field string, value int, limits models.SpecLimits) []string {
+	t.Helper()
+
+	base, err := json.Marshal(models.SystemSpec{CPUCores: 8, RAMGB: 16, StorageGB: 512, NetworkMbps: 100, OS: "Linux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(base, &fields); err != nil {
+		t.Fatal(err)
+	}
+	fields[field] = value
+	modified, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spec models.SystemSpec
+	if err := json.Unmarshal(modified, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.GPUVRAMGB > 0 {
+		spec.HasGPU = true
+	}
+
+	errs, _ := models.ValidateSystemSpec(spec, limits)
+	var rejected []string
+	for _, err := range errs {
+		rejected = append(rejected, err.Field)
+	}
+	return rejected
+}
+
+func TestGenerate(t *testing.T) {
+	operations := map[string]openapi.Operation{
+		"GET /items/:id": {
+			Summary:   "Get an item",
+			Responses: map[int]interface{}{http.StatusOK: models.DePINProject{}, http.StatusNotFound: nil},
+		},
+	}
+	routes := []openapi.Route{{Method: "GET", Path: "/items/:id"}, {Method: "DELETE", Path: "/items/:id"}}
+	doc := openapi.Generate(openapi.Info{Title: "test"}, routes, operations)
+
+	item := doc.Paths["/items/{id}"]["get"]
+	if item == nil {
+		t.Fatalf("paths = %v, want /items/{id}", doc.Paths)
+	}
+	if item.OperationID != "get_items_id" || item.Summary != "Get an item" {
+		t.Errorf("operation = %q %q", item.OperationID, item.Summary)
+	}
+	if len(item.Parameters) != 1 || item.Parameters[0].Name != "id" || item.Parameters[0].In != "path" || !item.Parameters[0].Required {
+		t.Errorf("parameters = %+v, want the required id path parameter", item.Parameters)
+	}
+	if ok := item.Responses["200"]; ok == nil || ok.Content["application/json"].Schema.Ref != "#/components/schemas/DePINProject" {
+		t.Errorf("200 response = %+v", ok)
+	}
+	if notFound := item.Responses["404"]; notFound == nil || notFound.Content != nil {
+		t.Errorf("404 response = %+v, want no body", notFound)
+	}
+	if doc.Schema("DePINProject") == nil {
+		t.Error("response schema not in components")
+	}
+
+	// Undocumented routes are still listed
+	if undocumented := doc.Paths["/items/{id}"]["delete"]; undocumented == nil || undocumented.Responses["200"] == nil {
+		t.Errorf("undocumented route = %+v", undocumented)
+	}
+}
+
+func TestEveryRouteDocumented(t *testing.T) {
+	doc := generate(t, models.DefaultSpecLimits())
+
+	ids := make(map[string]string)
+	for path, methods := range doc.Paths {
+		if strings.ContainsAny(path, ":*") {
+			t.Errorf("path %q keeps gin parameter syntax", path)
+		}
+		for method, item := range methods {
+			if item.Summary == "" {
+				t.Errorf("%s %s has no entry in operations", method, path)
+			}
+			if other, ok := ids[item.OperationID]; ok {
+				t.Errorf("%s %s and %s share operationId %q", method, path, other, item.OperationID)
+			}
+			ids[item.OperationID] = method + " " + path
+		}
+	}
+}
+
+func TestDocumentServed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	routes, err := server.NewRouter(server.Config{SpecLimits: models.DefaultSpecLimits()}, server.Components{})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	router := gin.New()
+	router.GET("/openapi.json", api.OpenAPIHandler(routes, models.DefaultSpecLimits()))
+	router.GET("/docs/ui", api.DocsUI)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var doc openapi.Document
+	if recorder.Code != http.StatusOK || json.Unmarshal(recorder.Body.Bytes(), &doc) != nil || doc.OpenAPI != "3.0.3" {
+		t.Fatalf("GET /openapi.json = %d: %.200s", recorder.Code, recorder.Body)
+	}
+	if doc.Paths["/api/v1/predict"]["post"] == nil {
+		t.Error("served document lacks POST /api/v1/predict")
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/docs/ui", nil))
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("GET /docs/ui = %d with %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is an OpenAPI 3 schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry builds component schemas from Go types, keyed by type name
+type schemaRegistry struct {
+	components map[string]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{components: make(map[string]*Schema)}
+}
+
+// ref returns a schema for v, registering named struct types as components
+func (r *schemaRegistry) ref(v interface{}) *Schema {
+	return r.schemaFor(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		if _, ok := r.components[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			r.components[t.Name()] = &Schema{}
+			*r.components[t.Name()] = *r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return r.structSchema(t)
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	default:
+		return &Schema{}
+	}
+}
+
+// structSchema describes a struct using its json tags for property names and
+// its binding tags for validation rules
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schemaFor(field.Type)
+		if property.Ref == "" {
+			required := applyBindingRules(property, field.Tag.Get("binding"))
+			if required {
+				schema.Required = append(schema.Required, name)
+			}
+		} else if bindingRequired(field.Tag.Get("binding")) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// applyBindingRules copies validator rules onto the schema and reports
+// whether the field is required
+func applyBindingRules(schema *Schema, binding string) bool {
+	required := false
+
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required":
+			required = true
+		case "min", "gte":
+			applyBound(schema, value, true)
+		case "max", "lte":
+			applyBound(schema, value, false)
+		case "oneof":
+			schema.Enum = strings.Fields(value)
+		}
+	}
+
+	return required
+}
+
+// applyBound sets a numeric or length bound depending on the schema type
+func applyBound(schema *Schema, value string, lower bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &n
+		} else {
+			schema.Maximum = &n
+		}
+	case "string":
+		length := int(n)
+		if lower {
+			schema.MinLength = &length
+		} else {
+			schema.MaxLength = &length
+		}
+	}
+}
+
+func bindingRequired(binding string) bool {
+	for _, rule := range strings.Split(binding, ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe returns the inline object schema for a struct value, with nested
+// named types left as references
+func Describe(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return newSchemaRegistry().structSchema(t)
+}
+
+// Summary renders a property as a short human-readable rule such as
+// "integer (1-64), required"
+func (s *Schema) Summary(required bool) string {
+	text := s.Type
+	if s.Ref != "" {
+		text = s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+
+	switch {
+	case s.Minimum != nil && s.Maximum != nil:
+		text += " (" + formatNumber(*s.Minimum) + "-" + formatNumber(*s.Maximum) + ")"
+	case s.Minimum != nil:
+		text += " (>= " + formatNumber(*s.Minimum) + ")"
+	case s.Maximum != nil:
+		text += " (<= " + formatNumber(*s.Maximum) + ")"
+	}
+
+	if s.Items != nil {
+		text += " of " + s.Items.Summary(false)
+	}
+	switch {
+	case s.MinItems != nil && s.MaxItems != nil:
+		text += " (" + strconv.Itoa(*s.MinItems) + " to " + strconv.Itoa(*s.MaxItems) + ")"
+	case s.MaxItems != nil:
+		text += " (up to " + strconv.Itoa(*s.MaxItems) + ")"
+	}
+
+	if len(s.Enum) > 0 {
+		text += " (" + strings.Join(s.Enum, "/") + ")"
+	}
+
+	if required {
+		text += ", required"
+	}
+	return text
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// Constraint describes validation rules for a property that are not expressed
+// as binding tags, such as configurable ranges
+type Constraint struct {
+	Property string
+	Required bool
+	Minimum  *float64
+	Maximum  *float64
+	MinItems *int
+	MaxItems *int
+	Enum     []string
+}
+
+// Constrain applies constraints to the properties of an object schema
+func (s *Schema) Constrain(constraints []Constraint) {
+	for _, constraint := range constraints {
+		name := constraint.Property
+		property, ok := s.Properties[name]
+		if !ok {
+			continue
+		}
+
+		property.Minimum = constraint.Minimum
+		property.Maximum = constraint.Maximum
+		property.MinItems = constraint.MinItems
+		property.MaxItems = constraint.MaxItems
+		property.Enum = constraint.Enum
+
+		if constraint.Required && !slices.Contains(s.Required, name) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
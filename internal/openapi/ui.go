@@ -0,0 +1,11 @@
+package openapi
+
+import (
+	_ "embed"
+)
+
+// UI is a self-contained HTML page that renders the document served at
+// /api/v1/openapi.json without any external assets
+//
+//go:embed ui/index.html
+var UI []byte
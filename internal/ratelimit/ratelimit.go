@@ -0,0 +1,218 @@
+// Package ratelimit holds the per-client token buckets and the API key quota
+// check shared by the REST, GraphQL and gRPC interfaces, so a client draws
+// from the same budget whichever one it calls.
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+)
+
+// Limit configures a token bucket: Rate requests per second refilling a
+// bucket of Burst requests
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Decision is the outcome of taking a token from a client's bucket
+type Decision struct {
+	Allowed    bool
+	Limit      int           // Bucket size
+	Remaining  int           // Whole tokens left after this request
+	Reset      time.Duration // Time until the bucket is full again
+	Window     time.Duration // Time to refill an empty bucket
+	RetryAfter time.Duration // Time until the next token, when rejected
+}
+
+// Charge is the outcome of charging one request to a client's bucket and its
+// API key's daily quota
+type Charge struct {
+	Decision
+	QuotaExceeded bool // The bucket allowed the request but the quota is used up
+}
+
+// ChargeRequest takes a token from client's bucket and, when that succeeds
+// and key is set, one unit of the key's daily quota. key may be nil for
+// anonymous clients. Rejections are recorded in usage against the key. When
+// the quota is used up, the charge is not allowed and RetryAfter is the time
+// until quotas reset.
+func ChargeRequest(limiter *Limiter, usage *auth.UsageTracker, client string, key *auth.APIKey) Charge {
+	charge := Charge{Decision: limiter.TakeFor(client, key)}
+	if !charge.Allowed {
+		if key != nil {
+			usage.RecordRateLimited(key.Name)
+		}
+		return charge
+	}
+
+	if key != nil && !usage.Consume(key) {
+		charge.Allowed = false
+		charge.QuotaExceeded = true
+		charge.RetryAfter = UntilNextUTCDay(time.Now())
+	}
+	return charge
+}
+
+// UntilNextUTCDay returns the time from now until daily quotas reset
+func UntilNextUTCDay(now time.Time) time.Duration {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return midnight.Sub(now)
+}
+
+// Limiter manages rate limiting per client (IP address or API key).
+// The store is bounded: the least recently seen client is evicted when full,
+// and clients idle for longer than the idle TTL are removed by StartCleanup.
+type Limiter struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // Front is the most recently seen client
+	r          rate.Limit
+	b          int
+	maxEntries int
+	idleTTL    time.Duration
+	now        func() time.Time
+}
+
+// limiterEntry is a single client's bucket
+type limiterEntry struct {
+	key      string
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewLimiter creates a rate limiter store holding at most maxEntries
+// clients, evicting clients idle for longer than idleTTL
+func NewLimiter(r rate.Limit, b int, maxEntries int, idleTTL time.Duration) *Limiter {
+	return &Limiter{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		r:          r,
+		b:          b,
+		maxEntries: maxEntries,
+		idleTTL:    idleTTL,
+		now:        time.Now,
+	}
+}
+
+// TakeFor takes a token from client's bucket, using the rate and burst of
+// key when it sets them and the store's defaults otherwise. key may be nil.
+func (i *Limiter) TakeFor(client string, key *auth.APIKey) Decision {
+	r, b := i.r, i.b
+	if key != nil && key.RatePerSecond > 0 {
+		r = rate.Limit(key.RatePerSecond)
+	}
+	if key != nil && key.Burst > 0 {
+		b = key.Burst
+	}
+	return i.Take(client, r, b)
+}
+
+// Allow checks if a request from the given IP is allowed
+func (i *Limiter) Allow(ip string) bool {
+	return i.Take(ip, i.r, i.b).Allowed
+}
+
+// Take consumes a token for key, creating its bucket with the given rate and
+// burst on first use, and reports the resulting bucket state
+func (i *Limiter) Take(key string, r rate.Limit, b int) Decision {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	entry := i.entry(key, r, b, now)
+
+	allowed := entry.limiter.AllowN(now, 1)
+	tokens := entry.limiter.TokensAt(now)
+
+	decision := Decision{
+		Allowed:   allowed,
+		Limit:     b,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+	}
+
+	if r > 0 && r != rate.Inf {
+		perToken := float64(time.Second) / float64(r)
+		decision.Window = time.Duration(float64(b) * perToken)
+		decision.Reset = time.Duration((float64(b) - tokens) * perToken)
+		if !allowed {
+			decision.RetryAfter = time.Duration((1 - tokens) * perToken)
+		}
+	}
+
+	return decision
+}
+
+// Len returns the number of clients currently tracked
+func (i *Limiter) Len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.lru.Len()
+}
+
+// entry returns the bucket for key, marking it as recently seen. Callers must hold mu.
+func (i *Limiter) entry(key string, r rate.Limit, b int, now time.Time) *limiterEntry {
+	if element, ok := i.entries[key]; ok {
+		entry := element.Value.(*limiterEntry)
+		entry.lastSeen = now
+		i.lru.MoveToFront(element)
+		return entry
+	}
+
+	// Make room by evicting the least recently seen clients
+	for i.maxEntries > 0 && i.lru.Len() >= i.maxEntries {
+		i.remove(i.lru.Back())
+	}
+
+	entry := &limiterEntry{
+		key:      key,
+		limiter:  rate.NewLimiter(r, b),
+		lastSeen: now,
+	}
+	i.entries[key] = i.lru.PushFront(entry)
+	return entry
+}
+
+// remove deletes an element from the store. Callers must hold mu.
+func (i *Limiter) remove(element *list.Element) {
+	entry := i.lru.Remove(element).(*limiterEntry)
+	delete(i.entries, entry.key)
+}
+
+// StartCleanup periodically evicts idle clients until ctx is cancelled
+func (i *Limiter) StartCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				i.cleanup()
+			}
+		}
+	}()
+}
+
+// cleanup removes clients not seen within the idle TTL
+func (i *Limiter) cleanup() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	cutoff := i.now().Add(-i.idleTTL)
+	for element := i.lru.Back(); element != nil; element = i.lru.Back() {
+		if element.Value.(*limiterEntry).lastSeen.After(cutoff) {
+			break
+		}
+		i.remove(element)
+	}
+}
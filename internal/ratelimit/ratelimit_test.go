@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+)
+
+// newTestLimiter returns a limiter on a clock the test advances
+func newTestLimiter(r rate.Limit, b, maxEntries int) (*Limiter, *time.Time) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(r, b, maxEntries, time.Minute)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestTake(t *testing.T) {
+	limiter, now := newTestLimiter(2, 3, 10)
+
+	for i := 2; i >= 0; i-- {
+		decision := limiter.Take("ip:1", 2, 3)
+		if !decision.Allowed || decision.Remaining != i || decision.Limit != 3 {
+			t.Fatalf("request %d: decision = %+v", 3-i, decision)
+		}
+	}
+
+	decision := limiter.Take("ip:1", 2, 3)
+	if decision.Allowed || decision.RetryAfter != 500*time.Millisecond || decision.Window != 1500*time.Millisecond {
+		t.Errorf("over the burst: decision = %+v, want a 500ms retry", decision)
+	}
+	if !limiter.Take("ip:2", 2, 3).Allowed {
+		t.Error("another client shares the bucket")
+	}
+
+	*now = now.Add(500 * time.Millisecond)
+	if decision := limiter.Take("ip:1", 2, 3); !decision.Allowed || decision.Reset != 1500*time.Millisecond {
+		t.Errorf("after refill: decision = %+v", decision)
+	}
+}
+
+func TestTakeForKeyLimits(t *testing.T) {
+	limiter, _ := newTestLimiter(1, 1, 10)
+	key := &auth.APIKey{Name: "bulk", RatePerSecond: 100, Burst: 5}
+
+	for i := 0; i < 5; i++ {
+		if !limiter.TakeFor("key:bulk", key).Allowed {
+			t.Fatalf("request %d within the key's burst rejected", i+1)
+		}
+	}
+	if limiter.TakeFor("key:bulk", key).Allowed {
+		t.Error("request over the key's burst allowed")
+	}
+
+	limiter.TakeFor("ip:1", nil)
+	if limiter.TakeFor("ip:1", nil).Allowed {
+		t.Error("anonymous client did not get the default burst of 1")
+	}
+}
+
+func TestLimiterEvictsLeastRecentlySeen(t *testing.T) {
+	limiter, now := newTestLimiter(1, 1, 2)
+
+	limiter.Take("a", 1, 1)
+	limiter.Take("b", 1, 1)
+	limiter.Take("a", 1, 1)
+	limiter.Take("c", 1, 1) // Evicts b
+	if limiter.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", limiter.Len())
+	}
+	if !limiter.Take("b", 1, 1).Allowed {
+		t.Error("evicted client b kept its empty bucket")
+	}
+
+	*now = now.Add(2 * time.Minute)
+	limiter.cleanup()
+	if limiter.Len() != 0 {
+		t.Errorf("Len after cleanup = %d, want 0", limiter.Len())
+	}
+}
+
+func TestChargeRequest(t *testing.T) {
+	limiter, _ := newTestLimiter(1000, 1000, 10)
+	usage := auth.NewUsageTracker()
+	key := &auth.APIKey{Name: "small", DailyQuota: 2}
+
+	for i := 0; i < 2; i++ {
+		if charge := ChargeRequest(limiter, usage, "key:small", key); !charge.Allowed {
+			t.Fatalf("request %d within quota: %+v", i+1, charge)
+		}
+	}
+	charge := ChargeRequest(limiter, usage, "key:small", key)
+	if charge.Allowed || !charge.QuotaExceeded || charge.RetryAfter <= 0 || charge.RetryAfter > 24*time.Hour {
+		t.Errorf("over quota: charge = %+v", charge)
+	}
+
+	// Anonymous clients have no quota
+	for i := 0; i < 5; i++ {
+		if charge := ChargeRequest(limiter, usage, "ip:1", nil); !charge.Allowed {
+			t.Fatalf("anonymous request %d: %+v", i+1, charge)
+		}
+	}
+}
+
+func TestChargeRequestRecordsRateLimited(t *testing.T) {
+	limiter, _ := newTestLimiter(1, 1, 10)
+	usage := auth.NewUsageTracker()
+	key := &auth.APIKey{Name: "k", DailyQuota: 10}
+
+	ChargeRequest(limiter, usage, "key:k", key)
+	if charge := ChargeRequest(limiter, usage, "key:k", key); charge.Allowed || charge.QuotaExceeded {
+		t.Fatalf("charge = %+v, want a rate limit rejection", charge)
+	}
+
+	snapshot := usage.Snapshot([]auth.APIKey{*key})
+	if snapshot[0].RateLimited != 1 || snapshot[0].RequestsToday != 1 {
+		t.Errorf("usage = %+v, want one request and one rate limited", snapshot[0])
+	}
+}
+
+func TestUntilNextUTCDay(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		want time.Duration
+	}{
+		{time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC), time.Minute},
+		{time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), 24 * time.Hour},
+		{time.Date(2026, 12, 31, 12, 0, 0, 0, time.UTC), 12 * time.Hour},
+		// 01:00 in UTC+2 is 23:00 UTC the previous day
+		{time.Date(2026, 10, 19, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*3600)), time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := UntilNextUTCDay(tt.now); got != tt.want {
+			t.Errorf("UntilNextUTCDay(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
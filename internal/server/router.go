@@ -0,0 +1,162 @@
+// Package server wires the API handlers into the HTTP router, so the
+// binary and tests serve exactly the same routes
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/simoncrean/api-predict/internal/api"
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+)
+
+// Route groups with separate rate limits
+const (
+	RateLimitDefault = "default"
+	RateLimitPredict = "predict"
+	RateLimitRead    = "read"
+	RateLimitAdmin   = "admin"
+)
+
+// RateLimitGroups lists every route group, each needing its own limiter
+var RateLimitGroups = []string{RateLimitDefault, RateLimitPredict, RateLimitRead, RateLimitAdmin}
+
+// Config holds the router settings taken from the server configuration
+type Config struct {
+	// Proxies whose X-Forwarded-For/X-Real-IP headers are trusted
+	TrustedProxies []string
+
+	// Reject /api/v1 requests without a valid API key
+	RequireAPIKey bool
+
+	// Accepted SystemSpec ranges, published in the OpenAPI document
+	SpecLimits models.SpecLimits
+}
+
+// Components groups the initialized pieces the router is wired from
+type Components struct {
+	Handlers        *api.Handlers
+	AdminHandlers   *api.AdminHandlers
+	ProfileHandlers *api.ProfileHandlers
+	Metrics         *metrics.Metrics
+	KeyStore        auth.KeyStore
+	KeyUsage        *auth.UsageTracker
+	RateLimiters    map[string]*ratelimit.Limiter
+	EventBroker     *events.Broker
+	GraphQLSchema   graphql.Schema
+}
+
+// NewRouter configures the HTTP router
+func NewRouter(config Config, deps Components) (*gin.Engine, error) {
+	handlers := deps.Handlers
+
+	// Set gin mode based on environment
+	if os.Getenv("GIN_MODE") == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(api.NotFound)
+	router.NoMethod(api.MethodNotAllowed)
+
+	// Only honor forwarding headers from configured proxies, so ClientIP is
+	// the real client behind nginx and cannot be spoofed otherwise
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	// Add middleware
+	router.Use(api.RequestIDMiddleware())
+	router.Use(api.RequestLoggingMiddleware(slog.Default()))
+	router.Use(api.RecoveryMiddleware(slog.Default()))
+	router.Use(api.CORSMiddleware())
+	router.Use(api.MetricsMiddleware(deps.Metrics))
+
+	// Probes are never authenticated or rate limited
+	router.GET("/livez", handlers.Livez)
+	router.GET("/readyz", handlers.Readyz)
+
+	apiKey := api.APIKeyMiddleware(deps.KeyStore, config.RequireAPIKey)
+
+	rateLimit := func(group string) gin.HandlerFunc {
+		return api.RateLimitMiddleware(deps.RateLimiters[group], deps.Metrics, deps.KeyUsage)
+	}
+
+	// API routes
+	v1 := router.Group("/api/v1", apiKey)
+	{
+		// Core endpoints
+		v1.POST("/predict", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopePredict), handlers.PredictCompatibility)
+		v1.GET("/projects", rateLimit(RateLimitRead), api.RequireScope(auth.ScopeRead), handlers.ListProjects)
+		v1.GET("/datasets", rateLimit(RateLimitRead), api.RequireScope(auth.ScopeRead), handlers.ListDatasetVersions)
+		v1.GET("/events", rateLimit(RateLimitRead), api.RequireScope(auth.ScopeRead), api.EventStream(deps.EventBroker))
+
+		// GraphQL queries may run predictions, so they share the predict limits
+		// and pay for each prediction
+		graphQL := api.GraphQL(deps.GraphQLSchema, deps.RateLimiters[RateLimitPredict], deps.Metrics, deps.KeyUsage)
+		v1.POST("/graphql", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopeRead), graphQL)
+		v1.GET("/graphql", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopeRead), graphQL)
+
+		// Persisted predictions
+		v1.GET("/predictions/:id", rateLimit(RateLimitRead), api.RequireScope(auth.ScopeRead), handlers.GetPrediction)
+		v1.DELETE("/predictions/:id", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopePredict), handlers.DeletePrediction)
+
+		// Saved system profiles
+		v1.POST("/systems", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopePredict), deps.ProfileHandlers.CreateProfile)
+		v1.GET("/systems/:id", rateLimit(RateLimitRead), api.RequireScope(auth.ScopePredict), deps.ProfileHandlers.GetProfile)
+		v1.DELETE("/systems/:id", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopePredict), deps.ProfileHandlers.DeleteProfile)
+		v1.GET("/systems/:id/predict", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopePredict), deps.ProfileHandlers.PredictProfile)
+		v1.PUT("/systems/:id/webhook", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopePredict), deps.ProfileHandlers.SetWebhook)
+		v1.DELETE("/systems/:id/webhook", rateLimit(RateLimitPredict), api.RequireScope(auth.ScopePredict), deps.ProfileHandlers.DeleteWebhook)
+		v1.GET("/systems/:id/webhook/deliveries", rateLimit(RateLimitRead), api.RequireScope(auth.ScopePredict), deps.ProfileHandlers.WebhookDeliveries)
+
+		// Utility endpoints
+		utility := v1.Group("", rateLimit(RateLimitDefault))
+		{
+			utility.GET("/health", handlers.HealthCheck)
+			utility.GET("/docs", handlers.APIDocs)
+			utility.GET("/docs/ui", api.DocsUI)
+			utility.GET("/openapi.json", api.OpenAPIHandler(router, config.SpecLimits))
+			utility.GET("/metrics", handlers.Metrics)
+			utility.GET("/metrics/json", handlers.MetricsJSON)
+		}
+
+		// Admin endpoints, always require an admin API key
+		admin := v1.Group("/admin", rateLimit(RateLimitAdmin), api.RequireScope(auth.ScopeAdmin))
+		{
+			admin.GET("/keys/usage", deps.AdminHandlers.KeyUsage)
+			admin.GET("/reference-systems", deps.AdminHandlers.ReferenceSystems)
+			admin.POST("/impact", deps.AdminHandlers.DatasetImpact)
+			admin.POST("/projects", deps.AdminHandlers.CreateProject)
+			admin.GET("/projects/history", deps.AdminHandlers.ProjectHistory)
+			admin.PUT("/projects/:name", deps.AdminHandlers.UpdateProject)
+			admin.DELETE("/projects/:name", deps.AdminHandlers.DeleteProject)
+		}
+	}
+
+	// Conventional Prometheus scrape path
+	router.GET("/metrics", apiKey, rateLimit(RateLimitDefault), handlers.Metrics)
+
+	// Root endpoint
+	router.GET("/", rateLimit(RateLimitDefault), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"service": "DePIN Resource Predict API",
+			"version": "1.0.0",
+			"docs":    "/api/v1/docs",
+			"openapi": "/api/v1/openapi.json",
+			"health":  "/api/v1/health",
+		})
+	})
+
+	return router, nil
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"github.com/simoncrean/api-predict/internal/api"
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/graphqlapi"
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
+)
+
+// newTestRouter wires every component the way main does, over the repository
+// dataset and a bolt store, with the API key "k-alice" granting predict and
+// read scopes
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store, err := storage.OpenBolt(filepath.Join(t.TempDir(), "api.db"))
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	projects, _, err := storage.NewCSVSource(filepath.Join("..", "..", "data", "depin_specs.csv")).Load()
+	if err != nil {
+		t.Fatalf("load dataset: %v", err)
+	}
+
+	limits := models.DefaultSpecLimits()
+	compatibilityService := service.NewCompatibilityService(projects)
+	appMetrics := metrics.New()
+	keyStore, err := auth.NewMemoryKeyStore([]auth.APIKey{
+		{Name: "alice", Key: "k-alice", Scopes: []string{auth.ScopePredict, auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatalf("NewMemoryKeyStore: %v", err)
+	}
+	keyUsage := auth.NewUsageTracker()
+	rateLimiters := make(map[string]*ratelimit.Limiter, len(RateLimitGroups))
+	for _, group := range RateLimitGroups {
+		rateLimiters[group] = ratelimit.NewLimiter(rate.Inf, 1, 100, time.Hour)
+	}
+	schema, err := graphqlapi.NewSchema(compatibilityService, appMetrics, limits)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	editor := service.NewProjectEditor(compatibilityService, store, nil)
+
+	router, err := NewRouter(Config{RequireAPIKey: true, SpecLimits: limits}, Components{
+		Handlers:        api.NewHandlers(compatibilityService, appMetrics, health.NewReadiness(), limits, service.NewPredictionArchive(store, 0)),
+		AdminHandlers:   api.NewAdminHandlers(keyStore, keyUsage, compatibilityService, nil, editor, store),
+		ProfileHandlers: api.NewProfileHandlers(service.NewProfileService(compatibilityService, store, false), store, appMetrics, limits),
+		Metrics:         appMetrics,
+		KeyStore:        keyStore,
+		KeyUsage:        keyUsage,
+		RateLimiters:    rateLimiters,
+		EventBroker:     events.NewBroker(events.DefaultHistorySize),
+		GraphQLSchema:   schema,
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return router
+}
+
+// serve sends a request authenticated as alice
+func serve(router *gin.Engine, method, target, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-API-Key", "k-alice")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestSavedProfileRoutes(t *testing.T) {
+	router := newTestRouter(t)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/systems",
+		`{"name": "rig", "system": {"cpu_cores": 8, "ram_gb": 16, "storage_gb": 512, "network_mbps": 100, "os": "Linux"}}`)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("POST /api/v1/systems = %d: %s", recorder.Code, recorder.Body)
+	}
+	var profile models.SavedProfile
+	if err := json.Unmarshal(recorder.Body.Bytes(), &profile); err != nil {
+		t.Fatalf("decode profile: %v", err)
+	}
+	path := "/api/v1/systems/" + profile.ID
+
+	steps := []struct {
+		method string
+		target string
+		want   int
+	}{
+		{http.MethodGet, path, http.StatusOK},
+		{http.MethodGet, path + "/predict", http.StatusOK},
+		{http.MethodPost, path + "/predict", http.StatusMethodNotAllowed},
+		{http.MethodGet, path + "/webhook/deliveries", http.StatusOK},
+		{http.MethodDelete, path, http.StatusNoContent},
+		{http.MethodGet, path, http.StatusNotFound},
+	}
+	for _, step := range steps {
+		if recorder := serve(router, step.method, step.target, ""); recorder.Code != step.want {
+			t.Errorf("%s %s = %d, want %d: %s", step.method, step.target, recorder.Code, step.want, recorder.Body)
+		}
+	}
+}
+
+func TestRoutesRequireAPIKey(t *testing.T) {
+	router := newTestRouter(t)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("GET /api/v1/projects without a key = %d, want 401", recorder.Code)
+	}
+	if recorder := serve(router, http.MethodGet, "/api/v1/projects", ""); recorder.Code != http.StatusOK {
+		t.Errorf("GET /api/v1/projects as alice = %d, want 200", recorder.Code)
+	}
+	if recorder := serve(router, http.MethodGet, "/api/v1/admin/keys/usage", ""); recorder.Code != http.StatusForbidden {
+		t.Errorf("GET /api/v1/admin/keys/usage as alice = %d, want 403", recorder.Code)
+	}
+}
@@ -1,37 +1,71 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"math"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/i18n"
+	"github.com/simoncrean/api-predict/internal/models"
 )
 
+// ScoringStrategy identifies the rules compatibility scores are computed
+// with. Change it whenever scoring changes, so stored predictions can be told
+// apart from current ones.
+const ScoringStrategy = "requirement-penalty-v1"
+
 // CompatibilityService handles DePIN compatibility analysis
 type CompatibilityService struct {
-	projects  []models.DePINProject
+	mu        sync.RWMutex
+	versions  []models.DatasetSnapshot // Retained dataset versions, oldest first; the last is live
+	retention int
+	store     VersionStore // Optional persistence of new versions
+	listeners []DatasetListener
 	startTime time.Time
 }
 
-// NewCompatibilityService creates a new compatibility service
+// NewCompatibilityService creates a new compatibility service serving
+// projects as dataset version 1
 func NewCompatibilityService(projects []models.DePINProject) *CompatibilityService {
 	return &CompatibilityService{
-		projects:  projects,
+		versions:  []models.DatasetSnapshot{newDatasetVersion(1, projects, DatasetSourceInitial)},
+		retention: DefaultVersionRetention,
 		startTime: time.Now(),
 	}
 }
 
+// PredictOptions controls how a prediction is rendered
+type PredictOptions struct {
+	Locale    string          // Message locale, defaults to English
+	PlainText bool            // Strip emojis from messages
+	Dataset   DatasetSelector // Dataset version to predict against, defaults to the live one
+}
+
 // PredictCompatibility analyzes system compatibility with all DePIN projects
 func (s *CompatibilityService) PredictCompatibility(system models.SystemSpec) (*models.PredictionResponse, error) {
+	return s.PredictCompatibilityWithOptions(context.Background(), system, PredictOptions{})
+}
+
+// PredictCompatibilityWithOptions analyzes system compatibility with all DePIN
+// projects, rendering messages according to opts. ctx is used for logging.
+func (s *CompatibilityService) PredictCompatibilityWithOptions(ctx context.Context, system models.SystemSpec, opts PredictOptions) (*models.PredictionResponse, error) {
+	loc := i18n.NewLocalizer(opts.Locale, opts.PlainText)
+	projects, version, err := s.ProjectsAt(opts.Dataset)
+	if err != nil {
+		return nil, err
+	}
+
 	var compatible []models.CompatibilityResult
 	var incompatible []models.CompatibilityResult
 	totalScore := 0.0
 
-	for _, project := range s.projects {
-		result := s.analyzeProjectCompatibility(system, project)
+	for _, project := range projects {
+		result := s.analyzeProjectCompatibility(system, project, loc)
 
 		if result.Compatible {
 			compatible = append(compatible, result)
@@ -51,30 +85,44 @@ func (s *CompatibilityService) PredictCompatibility(system models.SystemSpec) (*
 		return incompatible[i].CompatibilityScore > incompatible[j].CompatibilityScore
 	})
 
-	// Calculate summary statistics
+	// Calculate summary statistics; rates stay 0 for an empty dataset
 	summary := models.PredictionSummary{
-		TotalProjects:     len(s.projects),
+		TotalProjects:     len(projects),
 		CompatibleCount:   len(compatible),
 		IncompatibleCount: len(incompatible),
-		CompatibilityRate: float64(len(compatible)) / float64(len(s.projects)) * 100,
-		AverageScore:      totalScore / float64(len(s.projects)),
 		SystemRating:      models.GetSystemRating(system),
 	}
+	if len(projects) > 0 {
+		summary.CompatibilityRate = float64(len(compatible)) / float64(len(projects)) * 100
+		summary.AverageScore = totalScore / float64(len(projects))
+	}
 
 	// Generate recommendations
-	recommendations := s.generateRecommendations(system, compatible, incompatible)
+	recommendations := s.generateRecommendations(system, compatible, incompatible, loc)
+
+	slog.DebugContext(ctx, "Computed compatibility prediction",
+		"projects", summary.TotalProjects,
+		"compatible", summary.CompatibleCount,
+		"average_score", summary.AverageScore,
+		"system_rating", summary.SystemRating,
+		"locale", loc.Locale(),
+	)
 
 	return &models.PredictionResponse{
 		CompatibleProjects:   compatible,
 		IncompatibleProjects: incompatible,
 		Summary:              summary,
 		Recommendations:      recommendations,
+		Locale:               loc.Locale(),
+		DatasetVersion:       version.Version,
+		DatasetCreatedAt:     version.CreatedAt,
+		ScoringStrategy:      ScoringStrategy,
 		GeneratedAt:          time.Now(),
 	}, nil
 }
 
 // analyzeProjectCompatibility performs detailed compatibility analysis for a single project
-func (s *CompatibilityService) analyzeProjectCompatibility(system models.SystemSpec, project models.DePINProject) models.CompatibilityResult {
+func (s *CompatibilityService) analyzeProjectCompatibility(system models.SystemSpec, project models.DePINProject, loc *i18n.Localizer) models.CompatibilityResult {
 	result := models.CompatibilityResult{
 		Name:                project.Name,
 		Compatible:          true,
@@ -86,71 +134,69 @@ func (s *CompatibilityService) analyzeProjectCompatibility(system models.SystemS
 		Warnings:            []string{},
 	}
 
+	// addMissing marks the project incompatible and records a localized requirement
+	addMissing := func(code string, args ...interface{}) {
+		result.Compatible = false
+		result.MissingRequirements = append(result.MissingRequirements, loc.T(code, args...))
+		result.MissingCodes = append(result.MissingCodes, code)
+	}
+
 	score := 1.0
 
 	// Check CPU requirements
 	if system.CPUCores < project.CPUCoresMin {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements,
-			fmt.Sprintf("CPU cores: need %d, have %d", project.CPUCoresMin, system.CPUCores))
+		addMissing(i18n.MissingCPUCores, project.CPUCoresMin, system.CPUCores)
 		score -= 0.3
 	}
 
 	// Check RAM requirements
 	if system.RAMGB < project.RAMGBMin {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements,
-			fmt.Sprintf("RAM: need %dGB, have %dGB", project.RAMGBMin, system.RAMGB))
+		addMissing(i18n.MissingRAM, project.RAMGBMin, system.RAMGB)
 		score -= 0.3
 	} else if system.RAMGB < project.RAMGBRecommended {
 		result.RecommendedUpgrades = append(result.RecommendedUpgrades,
-			fmt.Sprintf("RAM upgrade to %dGB recommended for optimal performance", project.RAMGBRecommended))
+			loc.T(i18n.UpgradeRAMRecommended, project.RAMGBRecommended))
 		score -= 0.1
 	}
 
-	// Check storage requirements
-	if system.StorageGB < project.StorageGBMin {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements,
-			fmt.Sprintf("Storage: need %dGB, have %dGB", project.StorageGBMin, system.StorageGB))
+	// Check storage requirements against the best single drive, using only
+	// solid state drives for SSD projects when the system has any
+	needSSD := project.StorageType == "SSD"
+	hasSSD := system.HasSSDStorage()
+	available := system.AvailableStorageGB(needSSD && hasSSD)
+	if available < project.StorageGBMin {
+		addMissing(i18n.MissingStorage, project.StorageGBMin, available)
 		score -= 0.2
 	}
 
 	// Check SSD requirement
-	if project.StorageType == "SSD" && !system.HasSSD {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements, "SSD storage required")
+	if needSSD && !hasSSD {
+		addMissing(i18n.MissingSSD)
 		score -= 0.25
-	} else if project.StorageType == "SSD" && system.HasSSD {
+	} else if needSSD && hasSSD {
 		// Bonus for having SSD when recommended
 		score += 0.05
 	}
 
-	// Check GPU requirements
-	if project.GPURequired && !system.HasGPU {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements, "Dedicated GPU required")
+	// Check GPU requirements against the best card
+	bestVRAM := system.BestGPUVRAMGB()
+	if project.GPURequired && !system.HasDedicatedGPU() {
+		addMissing(i18n.MissingGPU)
 		score -= 0.4
-	} else if project.GPUVRAMGBMin > 0 && system.GPUVRAMGB < project.GPUVRAMGBMin {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements,
-			fmt.Sprintf("GPU VRAM: need %dGB, have %dGB", project.GPUVRAMGBMin, system.GPUVRAMGB))
+	} else if project.GPUVRAMGBMin > 0 && bestVRAM < project.GPUVRAMGBMin {
+		addMissing(i18n.MissingGPUVRAM, project.GPUVRAMGBMin, bestVRAM)
 		score -= 0.3
 	}
 
 	// Check network speed
 	if system.NetworkMbps < project.NetworkMbpsMin {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements,
-			fmt.Sprintf("Network speed: need %dMbps, have %dMbps", project.NetworkMbpsMin, system.NetworkMbps))
+		addMissing(i18n.MissingNetwork, project.NetworkMbpsMin, system.NetworkMbps)
 		score -= 0.2
 	}
 
 	// Check OS compatibility
 	if !s.isOSCompatible(system.OS, project.SupportedOS) {
-		result.Compatible = false
-		result.MissingRequirements = append(result.MissingRequirements,
-			fmt.Sprintf("OS not supported: need one of [%s], have %s", project.SupportedOS, system.OS))
+		addMissing(i18n.MissingOS, project.SupportedOS, system.OS)
 		score -= 0.3
 	}
 
@@ -159,7 +205,7 @@ func (s *CompatibilityService) analyzeProjectCompatibility(system models.SystemS
 
 	// Home-friendly check
 	if !project.HomeFriendly {
-		result.Warnings = append(result.Warnings, "This project may not be suitable for home use")
+		result.Warnings = append(result.Warnings, loc.T(i18n.WarningNotHomeFriendly))
 	}
 
 	// Ensure score is within bounds
@@ -210,7 +256,7 @@ func (s *CompatibilityService) calculatePerformanceBonus(system models.SystemSpe
 	}
 
 	// High-end GPU bonus
-	if system.HasGPU && system.GPUVRAMGB > 8 {
+	if system.BestGPUVRAMGB() > 8 {
 		bonus += 0.02
 	}
 
@@ -218,25 +264,28 @@ func (s *CompatibilityService) calculatePerformanceBonus(system models.SystemSpe
 }
 
 // generateRecommendations creates personalized recommendations
-func (s *CompatibilityService) generateRecommendations(system models.SystemSpec, compatible, incompatible []models.CompatibilityResult) []string {
+func (s *CompatibilityService) generateRecommendations(system models.SystemSpec, compatible, incompatible []models.CompatibilityResult, loc *i18n.Localizer) []string {
 	var recommendations []string
 
-	compatibilityRate := float64(len(compatible)) / float64(len(s.projects))
+	compatibilityRate := 0.0
+	if total := len(compatible) + len(incompatible); total > 0 {
+		compatibilityRate = float64(len(compatible)) / float64(total)
+	}
 
 	// Overall system assessment
 	switch {
 	case compatibilityRate >= 0.8:
-		recommendations = append(recommendations, "🎉 Excellent! Your system is compatible with most DePIN projects.")
+		recommendations = append(recommendations, loc.T(i18n.RecommendationExcellent))
 	case compatibilityRate >= 0.6:
-		recommendations = append(recommendations, "👍 Good compatibility! Your system works well with many DePIN projects.")
+		recommendations = append(recommendations, loc.T(i18n.RecommendationGood))
 	case compatibilityRate >= 0.4:
-		recommendations = append(recommendations, "⚠️ Fair compatibility. Consider upgrading for better project support.")
+		recommendations = append(recommendations, loc.T(i18n.RecommendationFair))
 	default:
-		recommendations = append(recommendations, "📈 Limited compatibility. Upgrades recommended for better DePIN support.")
+		recommendations = append(recommendations, loc.T(i18n.RecommendationLimited))
 	}
 
 	// Specific upgrade recommendations
-	upgradeRecommendations := s.analyzeUpgradeNeeds(system, incompatible)
+	upgradeRecommendations := s.analyzeUpgradeNeeds(system, incompatible, loc)
 	recommendations = append(recommendations, upgradeRecommendations...)
 
 	// Project-specific recommendations
@@ -247,14 +296,14 @@ func (s *CompatibilityService) generateRecommendations(system models.SystemSpec,
 			projectNames[i] = project.Name
 		}
 		recommendations = append(recommendations,
-			fmt.Sprintf("🚀 Recommended projects for your system: %s", strings.Join(projectNames, ", ")))
+			loc.T(i18n.RecommendationBestProjects, strings.Join(projectNames, ", ")))
 	}
 
 	return recommendations
 }
 
 // analyzeUpgradeNeeds suggests specific hardware upgrades
-func (s *CompatibilityService) analyzeUpgradeNeeds(system models.SystemSpec, incompatible []models.CompatibilityResult) []string {
+func (s *CompatibilityService) analyzeUpgradeNeeds(system models.SystemSpec, incompatible []models.CompatibilityResult, loc *i18n.Localizer) []string {
 	var recommendations []string
 
 	// Count common missing requirements
@@ -265,17 +314,17 @@ func (s *CompatibilityService) analyzeUpgradeNeeds(system models.SystemSpec, inc
 	networkIssues := 0
 
 	for _, result := range incompatible {
-		for _, req := range result.MissingRequirements {
-			switch {
-			case strings.Contains(req, "RAM"):
+		for _, code := range result.MissingCodes {
+			switch code {
+			case i18n.MissingRAM:
 				ramIssues++
-			case strings.Contains(req, "CPU"):
+			case i18n.MissingCPUCores:
 				cpuIssues++
-			case strings.Contains(req, "GPU"):
+			case i18n.MissingGPU, i18n.MissingGPUVRAM:
 				gpuIssues++
-			case strings.Contains(req, "Storage") || strings.Contains(req, "SSD"):
+			case i18n.MissingStorage, i18n.MissingSSD:
 				storageIssues++
-			case strings.Contains(req, "Network"):
+			case i18n.MissingNetwork:
 				networkIssues++
 			}
 		}
@@ -285,23 +334,23 @@ func (s *CompatibilityService) analyzeUpgradeNeeds(system models.SystemSpec, inc
 
 	// Generate specific recommendations
 	if ramIssues > threshold {
-		recommendations = append(recommendations, "💾 Consider upgrading RAM for better project compatibility")
+		recommendations = append(recommendations, loc.T(i18n.SuggestUpgradeRAM))
 	}
 
 	if cpuIssues > threshold {
-		recommendations = append(recommendations, "🖥️ A CPU upgrade would significantly improve project support")
+		recommendations = append(recommendations, loc.T(i18n.SuggestUpgradeCPU))
 	}
 
 	if gpuIssues > threshold {
-		recommendations = append(recommendations, "🎮 Adding a dedicated GPU would unlock AI and compute-intensive projects")
+		recommendations = append(recommendations, loc.T(i18n.SuggestUpgradeGPU))
 	}
 
 	if storageIssues > threshold {
-		recommendations = append(recommendations, "💿 Consider upgrading to SSD storage or increasing capacity")
+		recommendations = append(recommendations, loc.T(i18n.SuggestUpgradeStorage))
 	}
 
 	if networkIssues > threshold {
-		recommendations = append(recommendations, "🌐 Faster internet connection would improve project compatibility")
+		recommendations = append(recommendations, loc.T(i18n.SuggestUpgradeNetwork))
 	}
 
 	return recommendations
@@ -317,11 +366,18 @@ func (s *CompatibilityService) getBestProjects(compatible []models.Compatibility
 
 // GetProjects returns all loaded DePIN projects
 func (s *CompatibilityService) GetProjects() []models.DePINProject {
-	return s.projects
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[len(s.versions)-1].Projects
 }
 
 // GetProjectSummary returns summary statistics about loaded projects
 func (s *CompatibilityService) GetProjectSummary() models.ProjectSummary {
+	return SummarizeProjects(s.GetProjects())
+}
+
+// SummarizeProjects returns summary statistics about a set of projects
+func SummarizeProjects(projects []models.DePINProject) models.ProjectSummary {
 	summary := models.ProjectSummary{
 		ByType:         make(map[string]int),
 		ByCostCategory: make(map[string]int),
@@ -329,7 +385,7 @@ func (s *CompatibilityService) GetProjectSummary() models.ProjectSummary {
 		GPURequired:    0,
 	}
 
-	for _, project := range s.projects {
+	for _, project := range projects {
 		// Count by type
 		summary.ByType[project.Type]++
 
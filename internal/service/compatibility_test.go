@@ -0,0 +1,139 @@
+package service
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/i18n"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// gpuProject needs a card with at least vram GB
+func gpuProject(vram int) models.DePINProject {
+	return models.DePINProject{
+		Name: "Render", CPUCoresMin: 4, RAMGBMin: 16, RAMGBRecommended: 32, StorageGBMin: 100, StorageType: "Any",
+		GPURequired: true, GPUVRAMGBMin: vram, NetworkMbpsMin: 100, SupportedOS: "Linux",
+	}
+}
+
+// baseSystem meets every requirement of gpuProject except the GPU
+func baseSystem() models.SystemSpec {
+	return models.SystemSpec{CPUCores: 8, RAMGB: 32, StorageGB: 1000, HasSSD: true, NetworkMbps: 500, OS: "Linux"}
+}
+
+// analyze returns the result for the only project of a prediction
+func analyze(t *testing.T, project models.DePINProject, system models.SystemSpec) models.CompatibilityResult {
+	t.Helper()
+	response, err := NewCompatibilityService([]models.DePINProject{project}).PredictCompatibility(system)
+	if err != nil {
+		t.Fatalf("PredictCompatibility: %v", err)
+	}
+	if len(response.CompatibleProjects) == 1 {
+		return response.CompatibleProjects[0]
+	}
+	return response.IncompatibleProjects[0]
+}
+
+func TestGPURequirementsUseBestCard(t *testing.T) {
+	tests := []struct {
+		name    string
+		gpus    []models.GPU
+		flat    int // HasGPU with this much VRAM when gpus is empty, -1 for no GPU
+		missing []string
+	}{
+		{"no GPU", nil, -1, []string{i18n.MissingGPU}},
+		{"flat fields", nil, 24, nil},
+		{"small flat card", nil, 8, []string{i18n.MissingGPUVRAM}},
+		{"one big card", []models.GPU{{VRAMGB: 8}, {VRAMGB: 24}}, -1, nil},
+		{"VRAM is not pooled", []models.GPU{{VRAMGB: 12}, {VRAMGB: 12}}, -1, []string{i18n.MissingGPUVRAM}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system := baseSystem()
+			system.GPUs = tt.gpus
+			if tt.flat >= 0 {
+				system.HasGPU = true
+				system.GPUVRAMGB = tt.flat
+			}
+
+			result := analyze(t, gpuProject(16), system)
+			if !slices.Equal(result.MissingCodes, tt.missing) {
+				t.Errorf("missing = %v, want %v", result.MissingCodes, tt.missing)
+			}
+			if result.Compatible != (len(tt.missing) == 0) {
+				t.Errorf("compatible = %v", result.Compatible)
+			}
+		})
+	}
+}
+
+func TestServerClassRating(t *testing.T) {
+	system := models.SystemSpec{CPUCores: 128, RAMGB: 1024, StorageGB: 16000, HasSSD: true, NetworkMbps: 100000, OS: "Linux"}
+	for i := 0; i < 8; i++ {
+		system.GPUs = append(system.GPUs, models.GPU{Model: "H100", VRAMGB: 80})
+	}
+
+	response, err := NewCompatibilityService([]models.DePINProject{gpuProject(16)}).PredictCompatibility(system)
+	if err != nil {
+		t.Fatalf("PredictCompatibility: %v", err)
+	}
+	if response.Summary.SystemRating != models.SystemServer {
+		t.Errorf("rating = %s, want %s", response.Summary.SystemRating, models.SystemServer)
+	}
+	if response.Summary.CompatibleCount != 1 {
+		t.Errorf("server incompatible: %+v", response.IncompatibleProjects)
+	}
+}
+
+func TestStorageRequirementsUseBestDrive(t *testing.T) {
+	ssdProject := models.DePINProject{
+		Name: "Filecoin", CPUCoresMin: 4, RAMGBMin: 16, RAMGBRecommended: 16, StorageGBMin: 2000, StorageType: "SSD",
+		NetworkMbpsMin: 100, SupportedOS: "Linux",
+	}
+	anyProject := ssdProject
+	anyProject.StorageType = "Any"
+
+	tests := []struct {
+		name    string
+		project models.DePINProject
+		drives  []models.StorageDevice
+		missing []string
+	}{
+		{"big NVMe", ssdProject, []models.StorageDevice{{Type: models.StorageNVMe, CapacityGB: 4000}}, nil},
+		{"space is not pooled", anyProject, []models.StorageDevice{{Type: models.StorageSSD, CapacityGB: 1000}, {Type: models.StorageHDD, CapacityGB: 1500}}, []string{i18n.MissingStorage}},
+		{"HDD space does not count for SSD projects", ssdProject, []models.StorageDevice{{Type: models.StorageSSD, CapacityGB: 500}, {Type: models.StorageHDD, CapacityGB: 8000}}, []string{i18n.MissingStorage}},
+		{"HDD only", ssdProject, []models.StorageDevice{{Type: models.StorageHDD, CapacityGB: 8000}}, []string{i18n.MissingSSD}},
+		{"HDD serves Any", anyProject, []models.StorageDevice{{Type: models.StorageSSD, CapacityGB: 500}, {Type: models.StorageHDD, CapacityGB: 8000}}, nil},
+		{"free space counts", anyProject, []models.StorageDevice{{Type: models.StorageHDD, CapacityGB: 8000, FreeGB: 1000}}, []string{i18n.MissingStorage}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system := baseSystem()
+			system.StorageGB = 0
+			system.HasSSD = false
+			system.StorageDevices = tt.drives
+
+			result := analyze(t, tt.project, system)
+			if !slices.Equal(result.MissingCodes, tt.missing) {
+				t.Errorf("missing = %v, want %v", result.MissingCodes, tt.missing)
+			}
+		})
+	}
+}
+
+func TestPredictEmptyDataset(t *testing.T) {
+	result, err := NewCompatibilityService(nil).PredictCompatibility(baseSystem())
+	if err != nil {
+		t.Fatalf("predict: %v", err)
+	}
+	if result.Summary.TotalProjects != 0 || result.Summary.CompatibilityRate != 0 || result.Summary.AverageScore != 0 {
+		t.Errorf("summary = %+v, want zero rates", result.Summary)
+	}
+	if len(result.Recommendations) == 0 {
+		t.Error("no recommendations")
+	}
+	if _, err := json.Marshal(result); err != nil {
+		t.Errorf("encode: %v", err)
+	}
+}
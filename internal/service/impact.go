@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// CompareDatasets predicts compatibility for each reference system against
+// two datasets and reports which projects each system gains or loses and how
+// the scores of projects in both datasets move
+func CompareDatasets(profiles []models.ReferenceSystem, old, updated []models.DePINProject) (*models.ImpactReport, error) {
+	oldService := NewCompatibilityService(old)
+	newService := NewCompatibilityService(updated)
+
+	report := &models.ImpactReport{
+		Profiles:    make([]models.ProfileImpact, 0, len(profiles)),
+		GeneratedAt: time.Now(),
+	}
+
+	for _, profile := range profiles {
+		before, err := oldService.PredictCompatibility(profile.System)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+		after, err := newService.PredictCompatibility(profile.System)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+
+		impact := compareResults(profile.Name, before, after)
+		if len(impact.Gained) > 0 || len(impact.Lost) > 0 {
+			report.ProfilesAffected++
+		}
+		report.ProjectsGained += len(impact.Gained)
+		report.ProjectsLost += len(impact.Lost)
+		report.Profiles = append(report.Profiles, impact)
+	}
+
+	return report, nil
+}
+
+// compareResults diffs two predictions for the same system
+func compareResults(profile string, before, after *models.PredictionResponse) models.ProfileImpact {
+	return compareEvaluations(profile, NewProfileEvaluation(before), NewProfileEvaluation(after))
+}
+
+// NewProfileEvaluation keeps what is needed to diff a prediction against a
+// later one
+func NewProfileEvaluation(result *models.PredictionResponse) models.ProfileEvaluation {
+	evaluation := models.ProfileEvaluation{
+		DatasetVersion: result.DatasetVersion,
+		EvaluatedAt:    result.GeneratedAt,
+		Compatible:     make([]string, 0, len(result.CompatibleProjects)),
+		Scores:         make(map[string]float64, len(result.CompatibleProjects)+len(result.IncompatibleProjects)),
+		AverageScore:   result.Summary.AverageScore,
+	}
+
+	for _, project := range result.CompatibleProjects {
+		evaluation.Compatible = append(evaluation.Compatible, project.Name)
+		evaluation.Scores[project.Name] = project.CompatibilityScore
+	}
+	for _, project := range result.IncompatibleProjects {
+		evaluation.Scores[project.Name] = project.CompatibilityScore
+	}
+	sort.Strings(evaluation.Compatible)
+	return evaluation
+}
+
+// compareEvaluations diffs two evaluations of the same system
+func compareEvaluations(profile string, before, after models.ProfileEvaluation) models.ProfileImpact {
+	impact := models.ProfileImpact{
+		Profile:            profile,
+		CompatibleBefore:   len(before.Compatible),
+		CompatibleAfter:    len(after.Compatible),
+		AverageScoreBefore: before.AverageScore,
+		AverageScoreAfter:  after.AverageScore,
+		Gained:             []string{},
+		Lost:               []string{},
+		ScoreChanges:       []models.ScoreChange{},
+	}
+
+	wasCompatible := make(map[string]bool, len(before.Compatible))
+	for _, name := range before.Compatible {
+		wasCompatible[name] = true
+	}
+	isCompatible := make(map[string]bool, len(after.Compatible))
+	for _, name := range after.Compatible {
+		isCompatible[name] = true
+	}
+
+	for name := range isCompatible {
+		if !wasCompatible[name] {
+			impact.Gained = append(impact.Gained, name)
+		}
+	}
+	for name := range wasCompatible {
+		if !isCompatible[name] {
+			impact.Lost = append(impact.Lost, name)
+		}
+	}
+
+	for name, oldScore := range before.Scores {
+		newScore, ok := after.Scores[name]
+		if !ok {
+			continue
+		}
+		// Ignore floating point noise below the precision scores are shown with
+		if delta := math.Round((newScore-oldScore)*100) / 100; delta != 0 {
+			impact.ScoreChanges = append(impact.ScoreChanges, models.ScoreChange{
+				Project: name,
+				Before:  oldScore,
+				After:   newScore,
+				Delta:   delta,
+			})
+		}
+	}
+
+	sort.Strings(impact.Gained)
+	sort.Strings(impact.Lost)
+	sort.Slice(impact.ScoreChanges, func(i, j int) bool {
+		return impact.ScoreChanges[i].Project < impact.ScoreChanges[j].Project
+	})
+
+	return impact
+}
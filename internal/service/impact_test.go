@@ -0,0 +1,73 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+var desktop = models.ReferenceSystem{
+	Name:   "desktop",
+	System: models.SystemSpec{CPUCores: 8, RAMGB: 16, StorageGB: 512, HasSSD: true, NetworkMbps: 100, OS: "Linux"},
+}
+
+func TestCompareDatasets(t *testing.T) {
+	old := []models.DePINProject{
+		{Name: "Alpha", CPUCoresMin: 16, SupportedOS: "Linux"},
+		{Name: "Beta", CPUCoresMin: 2, SupportedOS: "Linux"},
+		{Name: "Gamma", RAMGBMin: 4, RAMGBRecommended: 32, SupportedOS: "Linux"},
+		{Name: "Delta", SupportedOS: "Linux"},
+	}
+	updated := []models.DePINProject{
+		{Name: "Alpha", CPUCoresMin: 4, SupportedOS: "Linux"}, // Now fits
+		{Name: "Beta", CPUCoresMin: 32, SupportedOS: "Linux"}, // No longer fits
+		{Name: "Gamma", RAMGBMin: 4, RAMGBRecommended: 8, SupportedOS: "Linux"},
+		{Name: "Delta", SupportedOS: "Linux"},
+	}
+
+	report, err := CompareDatasets([]models.ReferenceSystem{desktop}, old, updated)
+	if err != nil {
+		t.Fatalf("CompareDatasets: %v", err)
+	}
+
+	if report.ProfilesAffected != 1 || report.ProjectsGained != 1 || report.ProjectsLost != 1 {
+		t.Errorf("report totals = %+v", report)
+	}
+	if len(report.Profiles) != 1 {
+		t.Fatalf("got %d profiles, want 1", len(report.Profiles))
+	}
+
+	impact := report.Profiles[0]
+	if !reflect.DeepEqual(impact.Gained, []string{"Alpha"}) || !reflect.DeepEqual(impact.Lost, []string{"Beta"}) {
+		t.Errorf("Gained = %v, Lost = %v", impact.Gained, impact.Lost)
+	}
+	if impact.CompatibleBefore != 3 || impact.CompatibleAfter != 3 {
+		t.Errorf("compatible before/after = %d/%d, want 3/3", impact.CompatibleBefore, impact.CompatibleAfter)
+	}
+
+	// Delta is unchanged and must not be listed
+	moved := make(map[string]models.ScoreChange)
+	for _, change := range impact.ScoreChanges {
+		moved[change.Project] = change
+	}
+	if _, ok := moved["Delta"]; ok || len(moved) != 3 {
+		t.Errorf("ScoreChanges = %+v, want Alpha, Beta and Gamma", impact.ScoreChanges)
+	}
+	if gamma := moved["Gamma"]; gamma.Delta <= 0 || gamma.After <= gamma.Before {
+		t.Errorf("Gamma change = %+v, want a higher score once the recommended RAM is met", gamma)
+	}
+}
+
+func TestCompareDatasetsUnchanged(t *testing.T) {
+	projects := []models.DePINProject{{Name: "Alpha", CPUCoresMin: 4, SupportedOS: "Linux"}}
+
+	report, err := CompareDatasets([]models.ReferenceSystem{desktop}, projects, projects)
+	if err != nil {
+		t.Fatalf("CompareDatasets: %v", err)
+	}
+	impact := report.Profiles[0]
+	if report.ProfilesAffected != 0 || len(impact.Gained) != 0 || len(impact.Lost) != 0 || len(impact.ScoreChanges) != 0 {
+		t.Errorf("report for identical datasets = %+v", report)
+	}
+}
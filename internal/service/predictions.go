@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// DefaultPredictionRetention is how long persisted predictions are kept
+const DefaultPredictionRetention = 30 * 24 * time.Hour
+
+// ErrPredictionExpired is returned for persisted predictions past their
+// retention that have not been pruned yet
+var ErrPredictionExpired = errors.New("prediction expired")
+
+// PredictionStore persists prediction results
+type PredictionStore interface {
+	PutPrediction(prediction models.StoredPrediction) error
+	Prediction(id string) (models.StoredPrediction, error)
+	DeletePrediction(id string) error
+	PrunePredictions(before time.Time) (int, error)
+}
+
+// PredictionArchive persists prediction results under opaque IDs so they
+// can be shared, and deletes them after the retention period
+type PredictionArchive struct {
+	store     PredictionStore
+	retention time.Duration // Zero keeps predictions forever
+}
+
+// NewPredictionArchive creates an archive keeping predictions in store for
+// retention, or forever when retention is zero
+func NewPredictionArchive(store PredictionStore, retention time.Duration) *PredictionArchive {
+	return &PredictionArchive{
+		store:     store,
+		retention: retention,
+	}
+}
+
+// Save stores result under a new ID on behalf of owner, the API key name or
+// "" for anonymous requests. It sets result.PredictionID and
+// result.DeletionToken; only a hash of the token is stored.
+func (a *PredictionArchive) Save(system models.SystemSpec, result *models.PredictionResponse, owner string) (models.StoredPrediction, error) {
+	id, err := newPredictionID()
+	if err != nil {
+		return models.StoredPrediction{}, err
+	}
+	token, err := newSecretToken()
+	if err != nil {
+		return models.StoredPrediction{}, err
+	}
+	result.PredictionID = id
+	result.DeletionToken = ""
+
+	prediction := models.StoredPrediction{
+		ID:        id,
+		System:    system,
+		Result:    *result,
+		Owner:     owner,
+		CreatedAt: time.Now().UTC(),
+
+		DeletionTokenHash: hashSecretToken(token),
+	}
+	if a.retention > 0 {
+		expiresAt := prediction.CreatedAt.Add(a.retention)
+		prediction.ExpiresAt = &expiresAt
+	}
+
+	if err := a.store.PutPrediction(prediction); err != nil {
+		result.PredictionID = ""
+		return models.StoredPrediction{}, fmt.Errorf("failed to store prediction: %w", err)
+	}
+	result.DeletionToken = token
+	return prediction, nil
+}
+
+// ValidDeletionToken reports whether token is the deletion token returned
+// when prediction was stored
+func ValidDeletionToken(prediction models.StoredPrediction, token string) bool {
+	return secretTokenMatches(prediction.DeletionTokenHash, token)
+}
+
+// Get returns the persisted prediction with the given ID
+func (a *PredictionArchive) Get(id string) (models.StoredPrediction, error) {
+	prediction, err := a.store.Prediction(id)
+	if err != nil {
+		return models.StoredPrediction{}, err
+	}
+	if prediction.ExpiresAt != nil && time.Now().After(*prediction.ExpiresAt) {
+		return models.StoredPrediction{}, fmt.Errorf("%w: %s", ErrPredictionExpired, id)
+	}
+	return prediction, nil
+}
+
+// Delete deletes the persisted prediction with the given ID
+func (a *PredictionArchive) Delete(id string) error {
+	return a.store.DeletePrediction(id)
+}
+
+// Prune deletes predictions past the retention period
+func (a *PredictionArchive) Prune() (int, error) {
+	if a.retention == 0 {
+		return 0, nil
+	}
+	return a.store.PrunePredictions(time.Now().Add(-a.retention))
+}
+
+// StartPruning periodically prunes expired predictions until ctx is cancelled
+func (a *PredictionArchive) StartPruning(ctx context.Context, interval time.Duration) {
+	if a.retention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := a.Prune()
+				if err != nil {
+					slog.Warn("Failed to prune stored predictions", "error", err)
+				} else if deleted > 0 {
+					slog.Info("Pruned stored predictions", "deleted", deleted)
+				}
+			}
+		}
+	}()
+}
+
+// newPredictionID returns a random 128-bit hex ID
+func newPredictionID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate prediction ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// memoryPredictions keeps stored predictions in memory
+type memoryPredictions map[string]models.StoredPrediction
+
+func (m memoryPredictions) PutPrediction(prediction models.StoredPrediction) error {
+	m[prediction.ID] = prediction
+	return nil
+}
+
+func (m memoryPredictions) Prediction(id string) (models.StoredPrediction, error) {
+	prediction, ok := m[id]
+	if !ok {
+		return models.StoredPrediction{}, errors.New("not found")
+	}
+	return prediction, nil
+}
+
+func (m memoryPredictions) DeletePrediction(id string) error {
+	delete(m, id)
+	return nil
+}
+
+func (m memoryPredictions) PrunePredictions(before time.Time) (int, error) {
+	deleted := 0
+	for id, prediction := range m {
+		if prediction.CreatedAt.Before(before) {
+			delete(m, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestPredictionArchiveSave(t *testing.T) {
+	store := memoryPredictions{}
+	archive := NewPredictionArchive(store, time.Hour)
+
+	result := &models.PredictionResponse{Locale: "en"}
+	stored, err := archive.Save(models.SystemSpec{CPUCores: 4}, result, "")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if result.PredictionID == "" || result.PredictionID != stored.ID {
+		t.Errorf("PredictionID = %q, stored ID %q", result.PredictionID, stored.ID)
+	}
+	if result.DeletionToken == "" || stored.DeletionTokenHash == result.DeletionToken {
+		t.Errorf("deletion token %q, stored hash %q; want a token stored only as a hash", result.DeletionToken, stored.DeletionTokenHash)
+	}
+	if store[stored.ID].Result.DeletionToken != "" {
+		t.Error("the deletion token was stored with the result")
+	}
+	if stored.ExpiresAt == nil || !stored.ExpiresAt.Equal(stored.CreatedAt.Add(time.Hour)) {
+		t.Errorf("ExpiresAt = %v, want an hour after %v", stored.ExpiresAt, stored.CreatedAt)
+	}
+
+	if !ValidDeletionToken(stored, result.DeletionToken) {
+		t.Error("the returned deletion token is not accepted")
+	}
+	for _, token := range []string{"", "wrong", stored.DeletionTokenHash} {
+		if ValidDeletionToken(stored, token) {
+			t.Errorf("deletion token %q accepted", token)
+		}
+	}
+}
+
+func TestPredictionArchiveExpiry(t *testing.T) {
+	store := memoryPredictions{}
+	archive := NewPredictionArchive(store, time.Hour)
+
+	past := time.Now().Add(-time.Minute)
+	store["old"] = models.StoredPrediction{ID: "old", CreatedAt: past.Add(-time.Hour), ExpiresAt: &past}
+	if _, err := archive.Get("old"); !errors.Is(err, ErrPredictionExpired) {
+		t.Errorf("Get(expired) error = %v, want ErrPredictionExpired", err)
+	}
+
+	deleted, err := archive.Prune()
+	if err != nil || deleted != 1 || len(store) != 0 {
+		t.Errorf("Prune = %d, %v; %d left", deleted, err, len(store))
+	}
+}
+
+func TestPredictionArchiveKeepsForever(t *testing.T) {
+	store := memoryPredictions{"old": {ID: "old", CreatedAt: time.Now().AddDate(-1, 0, 0)}}
+	archive := NewPredictionArchive(store, 0)
+
+	stored, err := archive.Save(models.SystemSpec{}, &models.PredictionResponse{}, "alice")
+	if err != nil || stored.ExpiresAt != nil || stored.Owner != "alice" {
+		t.Errorf("Save = %+v, %v; want no expiry", stored, err)
+	}
+	if deleted, _ := archive.Prune(); deleted != 0 || len(store) != 2 {
+		t.Errorf("Prune deleted %d with retention 0", deleted)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// ErrInvalidWebhook is returned for webhook URLs that are not absolute
+// http or https URLs, or that point at internal addresses
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// ProfileStore persists saved system profiles
+type ProfileStore interface {
+	PutProfile(profile models.SavedProfile) error
+	Profile(id string) (models.SavedProfile, error)
+	Profiles() ([]models.SavedProfile, error)
+	DeleteProfile(id string) error
+}
+
+// ProfileService manages saved system profiles and predicts for them against
+// the live dataset, reporting what changed since each profile's previous
+// evaluation
+type ProfileService struct {
+	mu      sync.Mutex // Serializes profile updates so no evaluation or webhook change is lost
+	service *CompatibilityService
+	store   ProfileStore
+
+	allowPrivateWebhooks bool // Accept webhooks on loopback, private and link-local addresses
+}
+
+// NewProfileService creates a profile service predicting with service.
+// Webhooks pointing at internal addresses are rejected unless
+// allowPrivateWebhooks is set.
+func NewProfileService(service *CompatibilityService, store ProfileStore, allowPrivateWebhooks bool) *ProfileService {
+	return &ProfileService{
+		service:              service,
+		store:                store,
+		allowPrivateWebhooks: allowPrivateWebhooks,
+	}
+}
+
+// Create saves a named system profile under a new random ID on behalf of
+// owner, the API key name or "" for anonymous requests. The returned
+// profile carries its access token; only a hash of the token is stored.
+func (p *ProfileService) Create(name string, system models.SystemSpec, owner string) (models.SavedProfile, error) {
+	id, err := newProfileID()
+	if err != nil {
+		return models.SavedProfile{}, err
+	}
+	token, err := newSecretToken()
+	if err != nil {
+		return models.SavedProfile{}, err
+	}
+
+	now := time.Now().UTC()
+	profile := models.SavedProfile{
+		ID:              id,
+		Name:            name,
+		System:          system,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Owner:           owner,
+		AccessTokenHash: hashSecretToken(token),
+	}
+	if err := p.store.PutProfile(profile); err != nil {
+		return models.SavedProfile{}, fmt.Errorf("failed to save profile: %w", err)
+	}
+	profile.AccessToken = token
+	return profile, nil
+}
+
+// ValidProfileToken reports whether token is the access token returned when
+// profile was created
+func ValidProfileToken(profile models.SavedProfile, token string) bool {
+	return secretTokenMatches(profile.AccessTokenHash, token)
+}
+
+// Get returns the saved profile with the given ID
+func (p *ProfileService) Get(id string) (models.SavedProfile, error) {
+	return p.store.Profile(id)
+}
+
+// Delete deletes the saved profile with the given ID
+func (p *ProfileService) Delete(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.store.DeleteProfile(id)
+}
+
+// SetWebhook registers or replaces the webhook of a saved profile. An empty
+// secret is replaced with a generated one. The returned webhook includes
+// the secret.
+func (p *ProfileService) SetWebhook(ctx context.Context, id, webhookURL, secret string) (models.ProfileWebhook, error) {
+	if err := CheckWebhookURL(ctx, webhookURL, p.allowPrivateWebhooks); err != nil {
+		return models.ProfileWebhook{}, err
+	}
+
+	if secret == "" {
+		var err error
+		if secret, err = newSecretToken(); err != nil {
+			return models.ProfileWebhook{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profile, err := p.store.Profile(id)
+	if err != nil {
+		return models.ProfileWebhook{}, err
+	}
+
+	webhook := models.ProfileWebhook{
+		URL:       webhookURL,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	profile.Webhook = &webhook
+	profile.UpdatedAt = webhook.CreatedAt
+	if err := p.store.PutProfile(profile); err != nil {
+		return models.ProfileWebhook{}, fmt.Errorf("failed to save webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// RemoveWebhook removes the webhook of a saved profile, if it has one
+func (p *ProfileService) RemoveWebhook(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profile, err := p.store.Profile(id)
+	if err != nil {
+		return err
+	}
+	if profile.Webhook == nil {
+		return nil
+	}
+
+	profile.Webhook = nil
+	profile.UpdatedAt = time.Now().UTC()
+	if err := p.store.PutProfile(profile); err != nil {
+		return fmt.Errorf("failed to remove webhook: %w", err)
+	}
+	return nil
+}
+
+// Predict predicts compatibility for a saved profile against the live
+// dataset and stores the result as its latest evaluation. The response
+// includes the changes since the previous evaluation, if any.
+func (p *ProfileService) Predict(ctx context.Context, id string, opts PredictOptions) (*models.ProfilePredictionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profile, err := p.store.Profile(id)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Dataset = DatasetSelector{}
+	result, err := p.service.PredictCompatibilityWithOptions(ctx, profile.System, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := NewProfileEvaluation(result)
+	response := &models.ProfilePredictionResponse{Prediction: result}
+	if previous := profile.LastEvaluation; previous != nil {
+		response.Changes = &models.ProfileChanges{
+			SinceDatasetVersion: previous.DatasetVersion,
+			SinceEvaluatedAt:    previous.EvaluatedAt,
+			ProfileImpact:       compareEvaluations(profile.Name, *previous, evaluation),
+		}
+	}
+
+	profile.LastEvaluation = &evaluation
+	profile.UpdatedAt = time.Now().UTC()
+	if err := p.store.PutProfile(profile); err != nil {
+		return nil, fmt.Errorf("failed to save profile evaluation: %w", err)
+	}
+
+	response.Profile = profile
+	return response, nil
+}
+
+// newProfileID returns a random 96-bit hex ID
+func newProfileID() (string, error) {
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate profile ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
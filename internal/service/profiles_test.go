@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+func TestProfileServiceCreate(t *testing.T) {
+	store := newMemoryProfiles()
+	profiles := NewProfileService(NewCompatibilityService(nil), store, false)
+
+	profile, err := profiles.Create("rig", models.SystemSpec{CPUCores: 4}, "alice")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if profile.ID == "" || profile.Owner != "alice" || profile.AccessToken == "" {
+		t.Errorf("profile = %+v, want an ID, owner and access token", profile)
+	}
+
+	stored := store.profiles[profile.ID]
+	if stored.AccessToken != "" || stored.AccessTokenHash == "" {
+		t.Errorf("stored profile = %+v, want only the token hash", stored)
+	}
+	if !ValidProfileToken(stored, profile.AccessToken) {
+		t.Error("the returned access token is not accepted")
+	}
+	for _, token := range []string{"", "wrong", stored.AccessTokenHash} {
+		if ValidProfileToken(stored, token) {
+			t.Errorf("access token %q accepted", token)
+		}
+	}
+}
+
+func TestProfileServiceSetWebhook(t *testing.T) {
+	store := newMemoryProfiles(models.SavedProfile{ID: "p1", Name: "rig"})
+	profiles := NewProfileService(NewCompatibilityService(nil), store, false)
+
+	if _, err := profiles.SetWebhook(context.Background(), "p1", "http://169.254.169.254/latest", ""); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("metadata webhook error = %v, want ErrInvalidWebhook", err)
+	}
+	if store.profiles["p1"].Webhook != nil {
+		t.Error("rejected webhook was stored")
+	}
+
+	webhook, err := profiles.SetWebhook(context.Background(), "p1", "https://93.184.216.34/hook", "")
+	if err != nil {
+		t.Fatalf("SetWebhook: %v", err)
+	}
+	if len(webhook.Secret) != 64 || store.profiles["p1"].Webhook.URL != webhook.URL {
+		t.Errorf("webhook = %+v, want a generated secret", webhook)
+	}
+
+	if err := profiles.RemoveWebhook("p1"); err != nil || store.profiles["p1"].Webhook != nil {
+		t.Errorf("RemoveWebhook = %v, webhook %+v", err, store.profiles["p1"].Webhook)
+	}
+}
+
+func TestProfileServiceAllowsPrivateWebhooks(t *testing.T) {
+	store := newMemoryProfiles(models.SavedProfile{ID: "p1", Name: "rig"})
+	profiles := NewProfileService(NewCompatibilityService(nil), store, true)
+
+	if _, err := profiles.SetWebhook(context.Background(), "p1", "http://127.0.0.1:9000/hook", "0123456789abcdef"); err != nil {
+		t.Errorf("SetWebhook with private targets allowed: %v", err)
+	}
+}
+
+func TestProfileServicePredictReportsChanges(t *testing.T) {
+	big := models.DePINProject{Name: "big", CPUCoresMin: 64, SupportedOS: "Linux"}
+	compatibilityService := NewCompatibilityService(append(namedProjects("a"), big))
+	store := newMemoryProfiles()
+	profiles := NewProfileService(compatibilityService, store, false)
+
+	profile, err := profiles.Create("rig", models.SystemSpec{CPUCores: 8, RAMGB: 16, StorageGB: 500, NetworkMbps: 100, OS: "Linux"}, "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	first, err := profiles.Predict(context.Background(), profile.ID, PredictOptions{})
+	if err != nil {
+		t.Fatalf("first Predict: %v", err)
+	}
+	if first.Changes != nil {
+		t.Errorf("first prediction reports changes: %+v", first.Changes)
+	}
+	stored := store.profiles[profile.ID]
+	if stored.LastEvaluation == nil || stored.LastEvaluation.DatasetVersion != 1 || !slices.Equal(stored.LastEvaluation.Compatible, []string{"a"}) {
+		t.Fatalf("stored evaluation = %+v", stored.LastEvaluation)
+	}
+
+	big.CPUCoresMin = 4
+	if _, _, err := compatibilityService.SetProjects(append(namedProjects("c"), big), DatasetSourceAdmin); err != nil {
+		t.Fatal(err)
+	}
+
+	// A dataset selector is ignored: profiles are evaluated against the live dataset
+	second, err := profiles.Predict(context.Background(), profile.ID, PredictOptions{Dataset: DatasetSelector{Version: 1}})
+	if err != nil {
+		t.Fatalf("second Predict: %v", err)
+	}
+	if second.Prediction.DatasetVersion != 2 {
+		t.Errorf("predicted against version %d, want the live 2", second.Prediction.DatasetVersion)
+	}
+	changes := second.Changes
+	if changes == nil || changes.SinceDatasetVersion != 1 {
+		t.Fatalf("changes = %+v, want changes since version 1", changes)
+	}
+	if !slices.Equal(changes.Gained, []string{"big", "c"}) || !slices.Equal(changes.Lost, []string{"a"}) {
+		t.Errorf("gained %v and lost %v, want [big c] and [a]", changes.Gained, changes.Lost)
+	}
+	if changes.CompatibleBefore != 1 || changes.CompatibleAfter != 2 {
+		t.Errorf("compatible %d -> %d, want 1 -> 2", changes.CompatibleBefore, changes.CompatibleAfter)
+	}
+	if store.profiles[profile.ID].LastEvaluation.DatasetVersion != 2 {
+		t.Error("latest evaluation not stored")
+	}
+
+	if _, err := profiles.Predict(context.Background(), "missing", PredictOptions{}); err == nil {
+		t.Error("unknown profile predicted")
+	}
+}
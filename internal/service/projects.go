@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/data"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// Errors returned by ProjectEditor
+var (
+	ErrProjectExists   = errors.New("project already exists")
+	ErrProjectNotFound = errors.New("project not found")
+	ErrInvalidProject  = errors.New("invalid project")
+	ErrLastProject     = errors.New("cannot delete the last project")
+)
+
+// ProjectHistory records project edits
+type ProjectHistory interface {
+	AppendChange(record *models.ProjectChangeRecord) error
+}
+
+// ProjectEditor applies admin edits to the project dataset. Each edit is
+// validated like a loaded CSV line and becomes a new dataset version of the
+// CompatibilityService, persisted by its VersionStore, then is recorded in
+// the history.
+type ProjectEditor struct {
+	mu       sync.Mutex
+	service  *CompatibilityService
+	history  ProjectHistory
+	onChange func(record models.ProjectChangeRecord, projects []models.DePINProject)
+}
+
+// NewProjectEditor creates an editor for the projects served by service.
+// onChange, if set, is called with the change record and the new dataset
+// after every edit.
+func NewProjectEditor(service *CompatibilityService, history ProjectHistory, onChange func(record models.ProjectChangeRecord, projects []models.DePINProject)) *ProjectEditor {
+	return &ProjectEditor{
+		service:  service,
+		history:  history,
+		onChange: onChange,
+	}
+}
+
+// Create adds a new project
+func (e *ProjectEditor) Create(project models.DePINProject, actor string) (*models.ProjectChangeRecord, error) {
+	if err := prepareProject(&project); err != nil {
+		return nil, err
+	}
+
+	record := models.ProjectChangeRecord{
+		Action:  models.ChangeCreate,
+		Project: project.Name,
+		Actor:   actor,
+		After:   &project,
+	}
+	return e.commit(&record, func(projects []models.DePINProject) ([]models.DePINProject, error) {
+		if projectIndex(projects, project.Name) >= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrProjectExists, project.Name)
+		}
+		return append(projects, project), nil
+	})
+}
+
+// Update replaces the named project. The project may be renamed as long as
+// the new name is not taken.
+func (e *ProjectEditor) Update(name string, project models.DePINProject, actor string) (*models.ProjectChangeRecord, error) {
+	if err := prepareProject(&project); err != nil {
+		return nil, err
+	}
+
+	record := models.ProjectChangeRecord{
+		Action:  models.ChangeUpdate,
+		Project: name,
+		Actor:   actor,
+		After:   &project,
+	}
+	return e.commit(&record, func(projects []models.DePINProject) ([]models.DePINProject, error) {
+		idx := projectIndex(projects, name)
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrProjectNotFound, name)
+		}
+		if project.Name != name && projectIndex(projects, project.Name) >= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrProjectExists, project.Name)
+		}
+
+		before := projects[idx]
+		record.Before = &before
+		projects[idx] = project
+		return projects, nil
+	})
+}
+
+// Delete removes the named project. The last project cannot be deleted,
+// since an empty dataset is rejected when loaded.
+func (e *ProjectEditor) Delete(name string, actor string) (*models.ProjectChangeRecord, error) {
+	record := models.ProjectChangeRecord{
+		Action:  models.ChangeDelete,
+		Project: name,
+		Actor:   actor,
+	}
+	return e.commit(&record, func(projects []models.DePINProject) ([]models.DePINProject, error) {
+		idx := projectIndex(projects, name)
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrProjectNotFound, name)
+		}
+		if len(projects) == 1 {
+			return nil, fmt.Errorf("%w: %s", ErrLastProject, name)
+		}
+
+		before := projects[idx]
+		record.Before = &before
+		return slices.Delete(projects, idx, idx+1), nil
+	})
+}
+
+// commit applies edit to the live projects and records the change. The edit
+// runs under the CompatibilityService lock, so a concurrent dataset reload
+// is never overwritten with a stale copy. An edit that leaves the dataset
+// unchanged, such as a repeated update, is neither recorded nor reported to
+// onChange. A history failure is logged rather than returned, since the
+// dataset already changed.
+func (e *ProjectEditor) commit(record *models.ProjectChangeRecord, edit func([]models.DePINProject) ([]models.DePINProject, error)) (*models.ProjectChangeRecord, error) {
+	// Serialize edits so history entries follow dataset versions
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var projects []models.DePINProject
+	version, changed, err := e.service.UpdateProjects(DatasetSourceAdmin, func(live []models.DePINProject) ([]models.DePINProject, error) {
+		edited, err := edit(live)
+		projects = edited
+		return edited, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record.Timestamp = time.Now().UTC()
+	record.DatasetVersion = version.Version
+	if !changed {
+		return record, nil
+	}
+
+	if err := e.history.AppendChange(record); err != nil {
+		slog.Error("Failed to record project change", "project", record.Project, "action", record.Action, "error", err)
+	}
+
+	if e.onChange != nil {
+		e.onChange(*record, projects)
+	}
+
+	slog.Info("Project changed", "project", record.Project, "action", record.Action, "actor", record.Actor)
+	return record, nil
+}
+
+// prepareProject applies the dataset loader's validation and defaults, so an
+// edited project is stored exactly as the same CSV line would be loaded
+func prepareProject(project *models.DePINProject) error {
+	if err := data.ValidateProject(*project); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProject, err)
+	}
+	data.ApplyProjectDefaults(project)
+	return nil
+}
+
+func projectIndex(projects []models.DePINProject, name string) int {
+	return slices.IndexFunc(projects, func(p models.DePINProject) bool { return p.Name == name })
+}
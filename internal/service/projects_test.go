@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// memoryHistory records project changes in memory
+type memoryHistory struct {
+	changes []models.ProjectChangeRecord
+}
+
+func (h *memoryHistory) AppendChange(record *models.ProjectChangeRecord) error {
+	record.ID = int64(len(h.changes) + 1)
+	h.changes = append(h.changes, *record)
+	return nil
+}
+
+// newTestEditor returns an editor over one project, counting onChange calls
+func newTestEditor() (*ProjectEditor, *CompatibilityService, *memoryHistory, *int) {
+	service := NewCompatibilityService([]models.DePINProject{
+		{Name: "Alpha", Type: "Storage", NodeType: "Full", CPUCoresMin: 2, StorageType: "SSD", CostCategory: "Low", SupportedOS: "Linux"},
+	})
+	history := &memoryHistory{}
+	changes := 0
+	editor := NewProjectEditor(service, history, func(models.ProjectChangeRecord, []models.DePINProject) { changes++ })
+	return editor, service, history, &changes
+}
+
+func TestProjectEditorCreateAppliesDefaults(t *testing.T) {
+	editor, service, history, changes := newTestEditor()
+
+	record, err := editor.Create(models.DePINProject{Name: "Beta", CPUCoresMin: 4, EstimatedCostMax: 50}, "ops")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if record.ID != 1 || record.Action != models.ChangeCreate || record.Actor != "ops" || record.DatasetVersion != 2 {
+		t.Errorf("record = %+v", record)
+	}
+
+	projects := service.GetProjects()
+	beta := projects[len(projects)-1]
+	want := models.DePINProject{
+		Name: "Beta", Type: "Unknown", NodeType: "Standard", CPUCoresMin: 4, StorageType: "Any",
+		EstimatedCostMax: 50, CostCategory: "Medium", SupportedOS: "Linux,Windows,macOS",
+	}
+	if beta != want {
+		t.Errorf("stored project = %+v, want loader defaults %+v", beta, want)
+	}
+	if *record.After != want {
+		t.Errorf("record.After = %+v, want the stored project", *record.After)
+	}
+	if len(history.changes) != 1 || *changes != 1 {
+		t.Errorf("history = %d entries, onChange = %d calls, want 1 each", len(history.changes), *changes)
+	}
+}
+
+func TestProjectEditorUnchangedUpdate(t *testing.T) {
+	editor, service, history, changes := newTestEditor()
+	alpha := service.GetProjects()[0]
+
+	record, err := editor.Update("Alpha", alpha, "ops")
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if record.ID != 0 || record.DatasetVersion != 1 {
+		t.Errorf("record = %+v, want no ID and the current version", record)
+	}
+	if len(history.changes) != 0 || *changes != 0 {
+		t.Errorf("history = %d entries, onChange = %d calls, want none", len(history.changes), *changes)
+	}
+	if version := service.CurrentDatasetVersion().Version; version != 1 {
+		t.Errorf("dataset version = %d, want 1", version)
+	}
+}
+
+func TestProjectEditorUpdateAndDelete(t *testing.T) {
+	editor, service, history, changes := newTestEditor()
+
+	updated := service.GetProjects()[0]
+	updated.CPUCoresMin = 8
+	record, err := editor.Update("Alpha", updated, "ops")
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if record.Before.CPUCoresMin != 2 || record.After.CPUCoresMin != 8 || record.DatasetVersion != 2 {
+		t.Errorf("update record = %+v", record)
+	}
+
+	if _, err := editor.Create(models.DePINProject{Name: "Beta"}, "ops"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := editor.Delete("Alpha", "ops"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if projects := service.GetProjects(); len(projects) != 1 || projects[0].Name != "Beta" {
+		t.Errorf("projects after delete = %+v", projects)
+	}
+	if len(history.changes) != 3 || *changes != 3 {
+		t.Errorf("history = %d entries, onChange = %d calls, want 3 each", len(history.changes), *changes)
+	}
+}
+
+func TestProjectEditorErrors(t *testing.T) {
+	editor, _, history, _ := newTestEditor()
+
+	tests := []struct {
+		name string
+		edit func() error
+		want error
+	}{
+		{"create existing", func() error {
+			_, err := editor.Create(models.DePINProject{Name: "Alpha"}, "ops")
+			return err
+		}, ErrProjectExists},
+		{"create invalid", func() error {
+			_, err := editor.Create(models.DePINProject{Name: "Big", CPUCoresMin: 1000}, "ops")
+			return err
+		}, ErrInvalidProject},
+		{"update missing", func() error {
+			_, err := editor.Update("Missing", models.DePINProject{Name: "Missing"}, "ops")
+			return err
+		}, ErrProjectNotFound},
+		{"delete last", func() error {
+			_, err := editor.Delete("Alpha", "ops")
+			return err
+		}, ErrLastProject},
+		{"rename onto existing", func() error {
+			if _, err := editor.Create(models.DePINProject{Name: "Beta"}, "ops"); err != nil {
+				return err
+			}
+			_, err := editor.Update("Beta", models.DePINProject{Name: "Alpha"}, "ops")
+			return err
+		}, ErrProjectExists},
+		{"delete missing", func() error {
+			_, err := editor.Delete("Missing", "ops")
+			return err
+		}, ErrProjectNotFound},
+	}
+
+	for _, tt := range tests {
+		if err := tt.edit(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	// Only the Beta creation succeeded
+	if len(history.changes) != 1 {
+		t.Errorf("history = %+v, want only the Beta creation", history.changes)
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+// newSecretToken returns a random 256-bit hex token. Tokens are returned to
+// the caller once and only their hash is stored.
+func newSecretToken() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// hashSecretToken returns the hex SHA-256 of a token
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// secretTokenMatches reports whether token hashes to hash, in constant time.
+// An empty token or hash never matches.
+func secretTokenMatches(hash, token string) bool {
+	if hash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashSecretToken(token)), []byte(hash)) == 1
+}
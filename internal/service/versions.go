@@ -0,0 +1,300 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// DefaultVersionRetention is how many dataset versions are kept for
+// time-travel queries
+const DefaultVersionRetention = 50
+
+// Sources of dataset versions
+const (
+	DatasetSourceInitial = "initial"
+	DatasetSourceImport  = "import" // The CSV changed between restarts
+	DatasetSourceReload  = "reload"
+	DatasetSourceAdmin   = "admin"
+)
+
+// ErrDatasetVersionNotFound is returned for versions that never existed or
+// are no longer retained
+var ErrDatasetVersionNotFound = errors.New("dataset version not found")
+
+// DatasetSelector picks a dataset version by number or by the time it was
+// live. The zero value selects the live version.
+type DatasetSelector struct {
+	Version int
+	AsOf    time.Time
+}
+
+// DatasetSelectorError reports an invalid dataset selection. Field is
+// "dataset_version" or "as_of", or empty when both were given.
+type DatasetSelectorError struct {
+	Field   string
+	Message string
+}
+
+func (e *DatasetSelectorError) Error() string {
+	return e.Message
+}
+
+// errSelectorConflict is returned for selections setting both a dataset
+// version and an as_of time
+var errSelectorConflict = &DatasetSelectorError{Message: "Use either dataset_version or as_of, not both"}
+
+// NewDatasetSelector builds a selector from a dataset version and an as_of
+// time, either zero when unset. It rejects negative versions and selections
+// setting both.
+func NewDatasetSelector(version int, asOf time.Time) (DatasetSelector, error) {
+	if version != 0 && !asOf.IsZero() {
+		return DatasetSelector{}, errSelectorConflict
+	}
+	if version < 0 {
+		return DatasetSelector{}, &DatasetSelectorError{Field: "dataset_version", Message: "dataset_version must be a positive integer"}
+	}
+	return DatasetSelector{Version: version, AsOf: asOf}, nil
+}
+
+// ParseDatasetSelector is NewDatasetSelector for an as_of given as text and
+// parsed with ParseAsOf. An empty asOf is unset.
+func ParseDatasetSelector(version int, asOf string) (DatasetSelector, error) {
+	if version != 0 && asOf != "" {
+		return DatasetSelector{}, errSelectorConflict
+	}
+
+	var asOfTime time.Time
+	if asOf != "" {
+		var err error
+		if asOfTime, err = ParseAsOf(asOf); err != nil {
+			return DatasetSelector{}, &DatasetSelectorError{Field: "as_of", Message: "as_of must be an RFC 3339 time or a YYYY-MM-DD date"}
+		}
+	}
+	return NewDatasetSelector(version, asOfTime)
+}
+
+// ParseAsOf parses an as_of selector: an RFC 3339 time, or a date meaning
+// the end of that day in UTC
+func ParseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+// VersionStore persists dataset versions
+type VersionStore interface {
+	SaveDatasetVersion(snapshot models.DatasetSnapshot) error
+	PruneDatasetVersions(keep int) error
+}
+
+// DatasetListener is called after a new dataset version goes live, with the
+// version it replaced
+type DatasetListener func(previous, current models.DatasetSnapshot)
+
+func newDatasetVersion(version int, projects []models.DePINProject, source string) models.DatasetSnapshot {
+	return models.DatasetSnapshot{
+		Version: models.DatasetVersion{
+			Version:   version,
+			CreatedAt: time.Now().UTC(),
+			Source:    source,
+			Projects:  len(projects),
+			Checksum:  datasetChecksum(projects),
+		},
+		Projects: projects,
+	}
+}
+
+// RestoreCompatibilityService creates a service from persisted dataset
+// versions, oldest first; the last one is live. There must be at least one.
+func RestoreCompatibilityService(snapshots []models.DatasetSnapshot) *CompatibilityService {
+	return &CompatibilityService{
+		versions:  slices.Clone(snapshots),
+		retention: max(DefaultVersionRetention, len(snapshots)),
+		startTime: time.Now(),
+	}
+}
+
+// SetVersionStore persists every new dataset version to store
+func (s *CompatibilityService) SetVersionStore(store VersionStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+}
+
+// AddDatasetListener registers listener for every new dataset version.
+// Listeners run on the goroutine that changed the dataset, so they should
+// hand slow work off.
+func (s *CompatibilityService) AddDatasetListener(listener DatasetListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+// CurrentSnapshot returns the live dataset version with its projects
+func (s *CompatibilityService) CurrentSnapshot() models.DatasetSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[len(s.versions)-1]
+}
+
+// datasetChecksum identifies dataset content, so reloading an unchanged file
+// does not create a version
+func datasetChecksum(projects []models.DePINProject) string {
+	content, err := json.Marshal(projects)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SetVersionRetention sets how many dataset versions are kept, at least one
+func (s *CompatibilityService) SetVersionRetention(n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retention = max(n, 1)
+	return s.pruneVersions()
+}
+
+// SetProjects makes projects the live dataset as a new version, e.g. after a
+// dataset reload, and returns that version and whether it is new. Content
+// identical to the live dataset keeps the current version. With a
+// VersionStore, the version is persisted before it goes live. Predictions
+// already in flight finish against the previous set. Dataset listeners are
+// called once the new version is live.
+func (s *CompatibilityService) SetProjects(projects []models.DePINProject, source string) (models.DatasetVersion, bool, error) {
+	return s.UpdateProjects(source, func([]models.DePINProject) ([]models.DePINProject, error) {
+		return projects, nil
+	})
+}
+
+// UpdateProjects is SetProjects with the new projects computed by update from
+// a copy of the live ones. update runs under the service lock, so no reload
+// or other edit can land between reading the dataset and replacing it. An
+// error from update leaves the dataset unchanged and is returned.
+func (s *CompatibilityService) UpdateProjects(source string, update func(projects []models.DePINProject) ([]models.DePINProject, error)) (models.DatasetVersion, bool, error) {
+	current, next, changed, err := s.replaceProjects(source, update)
+	if err != nil || !changed {
+		return next.Version, false, err
+	}
+
+	s.mu.RLock()
+	listeners := slices.Clone(s.listeners)
+	s.mu.RUnlock()
+	for _, listener := range listeners {
+		listener(current, next)
+	}
+	return next.Version, true, nil
+}
+
+// replaceProjects makes the projects returned by update the live version
+// unless they match it, and returns the previous and live versions
+func (s *CompatibilityService) replaceProjects(source string, update func([]models.DePINProject) ([]models.DePINProject, error)) (models.DatasetSnapshot, models.DatasetSnapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.versions[len(s.versions)-1]
+	projects, err := update(slices.Clone(current.Projects))
+	if err != nil {
+		return current, current, false, err
+	}
+
+	next := newDatasetVersion(current.Version.Version+1, projects, source)
+	if next.Version.Checksum == current.Version.Checksum {
+		slog.Info("Dataset unchanged", "version", current.Version.Version, "source", source)
+		return current, current, false, nil
+	}
+
+	if s.store != nil {
+		if err := s.store.SaveDatasetVersion(next); err != nil {
+			return current, models.DatasetSnapshot{}, false, fmt.Errorf("failed to persist dataset version %d: %w", next.Version.Version, err)
+		}
+	}
+
+	s.versions = append(s.versions, next)
+	if err := s.pruneVersions(); err != nil {
+		slog.Warn("Failed to prune stored dataset versions", "error", err)
+	}
+
+	slog.Info("Replaced project dataset",
+		"previous", len(current.Projects),
+		"current", len(projects),
+		"version", next.Version.Version,
+		"source", source,
+	)
+	return current, next, true, nil
+}
+
+// pruneVersions drops the oldest versions beyond the retention limit
+func (s *CompatibilityService) pruneVersions() error {
+	if excess := len(s.versions) - s.retention; excess > 0 {
+		s.versions = slices.Clone(s.versions[excess:])
+	}
+	if s.store != nil {
+		return s.store.PruneDatasetVersions(s.retention)
+	}
+	return nil
+}
+
+// CurrentDatasetVersion describes the live dataset
+func (s *CompatibilityService) CurrentDatasetVersion() models.DatasetVersion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[len(s.versions)-1].Version
+}
+
+// DatasetVersions lists the retained dataset versions, newest first
+func (s *CompatibilityService) DatasetVersions() []models.DatasetVersion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := make([]models.DatasetVersion, len(s.versions))
+	for i, version := range s.versions {
+		versions[len(s.versions)-1-i] = version.Version
+	}
+	return versions
+}
+
+// ProjectsAt returns the projects of the selected dataset version. AsOf
+// selects the version that was live at that time.
+func (s *CompatibilityService) ProjectsAt(selector DatasetSelector) ([]models.DePINProject, models.DatasetVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	switch {
+	case selector.Version > 0:
+		for _, version := range s.versions {
+			if version.Version.Version == selector.Version {
+				return version.Projects, version.Version, nil
+			}
+		}
+		return nil, models.DatasetVersion{}, fmt.Errorf("%w: version %d is not retained", ErrDatasetVersionNotFound, selector.Version)
+
+	case !selector.AsOf.IsZero():
+		for i := len(s.versions) - 1; i >= 0; i-- {
+			if !s.versions[i].Version.CreatedAt.After(selector.AsOf) {
+				return s.versions[i].Projects, s.versions[i].Version, nil
+			}
+		}
+		return nil, models.DatasetVersion{}, fmt.Errorf("%w: no retained version was live at %s, the oldest is from %s",
+			ErrDatasetVersionNotFound, selector.AsOf.Format(time.RFC3339), s.versions[0].Version.CreatedAt.Format(time.RFC3339))
+
+	default:
+		current := s.versions[len(s.versions)-1]
+		return current.Projects, current.Version, nil
+	}
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// memoryVersions is an in-memory VersionStore
+type memoryVersions struct {
+	saved   []int
+	keep    int
+	failing bool
+}
+
+func (m *memoryVersions) SaveDatasetVersion(snapshot models.DatasetSnapshot) error {
+	if m.failing {
+		return errors.New("disk full")
+	}
+	m.saved = append(m.saved, snapshot.Version.Version)
+	return nil
+}
+
+func (m *memoryVersions) PruneDatasetVersions(keep int) error {
+	m.keep = keep
+	return nil
+}
+
+// namedProjects returns one minimal project per name
+func namedProjects(names ...string) []models.DePINProject {
+	projects := make([]models.DePINProject, len(names))
+	for i, name := range names {
+		projects[i] = models.DePINProject{Name: name, CPUCoresMin: 1, SupportedOS: "Linux"}
+	}
+	return projects
+}
+
+// versionNumbers lists the numbers of versions
+func versionNumbers(versions []models.DatasetVersion) []int {
+	numbers := make([]int, len(versions))
+	for i, version := range versions {
+		numbers[i] = version.Version
+	}
+	return numbers
+}
+
+func TestParseDatasetSelector(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		asOf    string
+		want    DatasetSelector
+		field   string // Field of the expected DatasetSelectorError, "-" for none
+	}{
+		{"live", 0, "", DatasetSelector{}, "-"},
+		{"version", 3, "", DatasetSelector{Version: 3}, "-"},
+		{"time", 0, "2024-05-01T12:00:00Z", DatasetSelector{AsOf: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}, "-"},
+		{"date is end of day", 0, "2024-05-01", DatasetSelector{AsOf: time.Date(2024, 5, 1, 23, 59, 59, 999999999, time.UTC)}, "-"},
+		{"both", 3, "2024-05-01", DatasetSelector{}, ""},
+		{"negative version", -1, "", DatasetSelector{}, "dataset_version"},
+		{"malformed as_of", 0, "yesterday", DatasetSelector{}, "as_of"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDatasetSelector(tt.version, tt.asOf)
+			if tt.field == "-" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got.Version != tt.want.Version || !got.AsOf.Equal(tt.want.AsOf) {
+					t.Errorf("selector = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+			var selectorErr *DatasetSelectorError
+			if !errors.As(err, &selectorErr) {
+				t.Fatalf("error = %v, want a DatasetSelectorError", err)
+			}
+			if selectorErr.Field != tt.field {
+				t.Errorf("field = %q, want %q", selectorErr.Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestNewDatasetSelector(t *testing.T) {
+	asOf := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	if selector, err := NewDatasetSelector(0, asOf); err != nil || !selector.AsOf.Equal(asOf) {
+		t.Errorf("as_of selector = %+v, %v", selector, err)
+	}
+	if _, err := NewDatasetSelector(2, asOf); err != errSelectorConflict {
+		t.Errorf("both set: error = %v, want the conflict error", err)
+	}
+	var selectorErr *DatasetSelectorError
+	if _, err := NewDatasetSelector(-2, time.Time{}); !errors.As(err, &selectorErr) || selectorErr.Field != "dataset_version" {
+		t.Errorf("negative version: error = %v", err)
+	}
+}
+
+func TestSetProjectsCreatesVersions(t *testing.T) {
+	compatibilityService := NewCompatibilityService(namedProjects("a"))
+	store := &memoryVersions{}
+	compatibilityService.SetVersionStore(store)
+
+	var notified [][2]int
+	compatibilityService.AddDatasetListener(func(previous, current models.DatasetSnapshot) {
+		notified = append(notified, [2]int{previous.Version.Version, current.Version.Version})
+	})
+
+	version, changed, err := compatibilityService.SetProjects(namedProjects("a"), DatasetSourceReload)
+	if err != nil || changed || version.Version != 1 {
+		t.Errorf("unchanged reload = %+v, %v, %v, want version 1 unchanged", version, changed, err)
+	}
+
+	version, changed, err = compatibilityService.SetProjects(namedProjects("a", "b"), DatasetSourceAdmin)
+	if err != nil || !changed || version.Version != 2 || version.Source != DatasetSourceAdmin || version.Projects != 2 {
+		t.Errorf("changed dataset = %+v, %v, %v, want version 2", version, changed, err)
+	}
+
+	if got := versionNumbers(compatibilityService.DatasetVersions()); !slices.Equal(got, []int{2, 1}) {
+		t.Errorf("versions = %v, want [2 1]", got)
+	}
+	if !slices.Equal(store.saved, []int{2}) {
+		t.Errorf("persisted versions = %v, want [2]", store.saved)
+	}
+	if !slices.Equal(notified, [][2]int{{1, 2}}) {
+		t.Errorf("listener calls = %v, want one for 1 -> 2", notified)
+	}
+}
+
+func TestSetProjectsKeepsLiveVersionWhenStoreFails(t *testing.T) {
+	compatibilityService := NewCompatibilityService(namedProjects("a"))
+	compatibilityService.SetVersionStore(&memoryVersions{failing: true})
+
+	if _, _, err := compatibilityService.SetProjects(namedProjects("b"), DatasetSourceReload); err == nil {
+		t.Fatal("SetProjects succeeded without persisting")
+	}
+	if current := compatibilityService.CurrentDatasetVersion(); current.Version != 1 {
+		t.Errorf("live version = %d, want 1", current.Version)
+	}
+}
+
+func TestVersionRetention(t *testing.T) {
+	compatibilityService := NewCompatibilityService(namedProjects("v1"))
+	store := &memoryVersions{}
+	compatibilityService.SetVersionStore(store)
+	for _, name := range []string{"v2", "v3", "v4"} {
+		if _, _, err := compatibilityService.SetProjects(namedProjects(name), DatasetSourceReload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := compatibilityService.SetVersionRetention(2); err != nil {
+		t.Fatalf("SetVersionRetention: %v", err)
+	}
+	if got := versionNumbers(compatibilityService.DatasetVersions()); !slices.Equal(got, []int{4, 3}) {
+		t.Errorf("versions = %v, want [4 3]", got)
+	}
+	if store.keep != 2 {
+		t.Errorf("store pruned to %d, want 2", store.keep)
+	}
+
+	if _, _, err := compatibilityService.ProjectsAt(DatasetSelector{Version: 1}); !errors.Is(err, ErrDatasetVersionNotFound) {
+		t.Errorf("pruned version: error = %v, want ErrDatasetVersionNotFound", err)
+	}
+}
+
+func TestProjectsAt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC) }
+	compatibilityService := RestoreCompatibilityService([]models.DatasetSnapshot{
+		{Version: models.DatasetVersion{Version: 3, CreatedAt: day(1)}, Projects: namedProjects("a")},
+		{Version: models.DatasetVersion{Version: 4, CreatedAt: day(5)}, Projects: namedProjects("a", "b")},
+		{Version: models.DatasetVersion{Version: 5, CreatedAt: day(9)}, Projects: namedProjects("a", "b", "c")},
+	})
+
+	tests := []struct {
+		name     string
+		selector DatasetSelector
+		want     int // Version, 0 for not found
+	}{
+		{"live", DatasetSelector{}, 5},
+		{"by number", DatasetSelector{Version: 4}, 4},
+		{"unknown number", DatasetSelector{Version: 2}, 0},
+		{"as of creation", DatasetSelector{AsOf: day(5)}, 4},
+		{"as of between", DatasetSelector{AsOf: day(7)}, 4},
+		{"as of future", DatasetSelector{AsOf: day(30)}, 5},
+		{"as of before history", DatasetSelector{AsOf: day(1).Add(-time.Second)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects, version, err := compatibilityService.ProjectsAt(tt.selector)
+			if tt.want == 0 {
+				if !errors.Is(err, ErrDatasetVersionNotFound) {
+					t.Errorf("error = %v, want ErrDatasetVersionNotFound", err)
+				}
+				return
+			}
+			if err != nil || version.Version != tt.want || len(projects) != tt.want-2 {
+				t.Errorf("version %d with %d projects, %v, want version %d", version.Version, len(projects), err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredictAgainstPastVersion(t *testing.T) {
+	compatibilityService := NewCompatibilityService(namedProjects("a"))
+	if _, _, err := compatibilityService.SetProjects(namedProjects("a", "b", "c"), DatasetSourceReload); err != nil {
+		t.Fatal(err)
+	}
+
+	system := models.SystemSpec{CPUCores: 4, RAMGB: 8, StorageGB: 100, NetworkMbps: 100, OS: "Linux"}
+	for _, tt := range []struct {
+		selector DatasetSelector
+		version  int
+		projects int
+	}{
+		{DatasetSelector{}, 2, 3},
+		{DatasetSelector{Version: 1}, 1, 1},
+	} {
+		result, err := compatibilityService.PredictCompatibilityWithOptions(context.Background(), system, PredictOptions{Dataset: tt.selector})
+		if err != nil {
+			t.Fatalf("predict: %v", err)
+		}
+		if result.DatasetVersion != tt.version || result.Summary.TotalProjects != tt.projects {
+			t.Errorf("selector %+v: version %d with %d projects, want %d with %d",
+				tt.selector, result.DatasetVersion, result.Summary.TotalProjects, tt.version, tt.projects)
+		}
+	}
+
+	if _, err := compatibilityService.PredictCompatibilityWithOptions(context.Background(), system, PredictOptions{Dataset: DatasetSelector{Version: 9}}); !errors.Is(err, ErrDatasetVersionNotFound) {
+		t.Errorf("unknown version: error = %v", err)
+	}
+}
+
+func TestUpdateProjects(t *testing.T) {
+	compatibilityService := NewCompatibilityService(namedProjects("a"))
+
+	failed := errors.New("rejected")
+	if _, _, err := compatibilityService.UpdateProjects(DatasetSourceAdmin, func([]models.DePINProject) ([]models.DePINProject, error) {
+		return namedProjects("b"), failed
+	}); err != failed {
+		t.Errorf("error = %v, want the update's error", err)
+	}
+	if current := compatibilityService.CurrentDatasetVersion(); current.Version != 1 {
+		t.Errorf("failed update changed the dataset to version %d", current.Version)
+	}
+
+	// A reload started during an update waits for it, so the update's read
+	// and write see the same dataset and the reload is not overwritten
+	reloaded := make(chan struct{})
+	version, changed, err := compatibilityService.UpdateProjects(DatasetSourceAdmin, func(projects []models.DePINProject) ([]models.DePINProject, error) {
+		go func() {
+			compatibilityService.SetProjects(namedProjects("reloaded"), DatasetSourceReload)
+			close(reloaded)
+		}()
+		select {
+		case <-reloaded:
+			t.Error("reload replaced the dataset during an update")
+		case <-time.After(20 * time.Millisecond):
+		}
+		return append(projects, namedProjects("b")...), nil
+	})
+	if err != nil || !changed || version.Version != 2 {
+		t.Errorf("update = %+v, %v, %v, want version 2", version, changed, err)
+	}
+
+	<-reloaded
+	projects := compatibilityService.GetProjects()
+	if current := compatibilityService.CurrentDatasetVersion(); current.Version != 3 || len(projects) != 1 || projects[0].Name != "reloaded" {
+		t.Errorf("after reload: version %d with %+v, want version 3 with the reloaded dataset", current.Version, projects)
+	}
+}
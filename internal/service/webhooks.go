@@ -0,0 +1,328 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// Webhook delivery defaults
+const (
+	DefaultWebhookMaxAttempts = 5
+	DefaultWebhookTimeout     = 10 * time.Second
+	DefaultWebhookBackoff     = 2 * time.Second
+)
+
+// maxWebhookDeliveries bounds the delivery log
+const maxWebhookDeliveries = 10000
+
+// Headers sent with webhook events
+const (
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookLog records webhook delivery attempts
+type WebhookLog interface {
+	AppendWebhookDelivery(record *models.WebhookDelivery) error
+	PruneWebhookDeliveries(keep int) error
+}
+
+// WebhookOptions controls webhook delivery
+type WebhookOptions struct {
+	MaxAttempts int           // Attempts per event, including the first
+	Timeout     time.Duration // Per attempt
+	Backoff     time.Duration // Wait before the first retry, doubled for each further retry
+
+	// AllowPrivateTargets permits deliveries to loopback, private and
+	// link-local addresses, for receivers on the same host or network
+	AllowPrivateTargets bool
+}
+
+// errBlockedWebhookTarget is returned when a delivery would connect to an
+// internal address
+var errBlockedWebhookTarget = errors.New("webhook target is not a public address")
+
+// sharedAddressSpace is the RFC 6598 carrier-grade NAT range, which some
+// clouds use for their metadata service
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// WebhookNotifier re-runs predictions for saved profiles with a webhook after
+// every dataset change, and POSTs a signed event to each profile that gained
+// or lost compatible projects. Failed deliveries are retried with
+// exponential backoff; every attempt is recorded in the delivery log.
+type WebhookNotifier struct {
+	ctx      context.Context
+	profiles ProfileStore
+	log      WebhookLog
+	client   *http.Client
+	opts     WebhookOptions
+}
+
+// NewWebhookNotifier creates a notifier whose deliveries stop when ctx is
+// cancelled. Register its DatasetChanged method as a DatasetListener.
+func NewWebhookNotifier(ctx context.Context, profiles ProfileStore, log WebhookLog, opts WebhookOptions) *WebhookNotifier {
+	return &WebhookNotifier{
+		ctx:      ctx,
+		profiles: profiles,
+		log:      log,
+		client:   newWebhookClient(opts.Timeout, opts.AllowPrivateTargets),
+		opts:     opts,
+	}
+}
+
+// DatasetChanged evaluates the saved profiles against the new dataset in the
+// background
+func (n *WebhookNotifier) DatasetChanged(previous, current models.DatasetSnapshot) {
+	go n.notify(previous, current)
+}
+
+// notify sends an event to every profile webhook whose compatible projects
+// differ between the two datasets
+func (n *WebhookNotifier) notify(previous, current models.DatasetSnapshot) {
+	profiles, err := n.profiles.Profiles()
+	if err != nil {
+		slog.Error("Failed to load profiles for webhooks", "error", err)
+		return
+	}
+
+	before := NewCompatibilityService(previous.Projects)
+	after := NewCompatibilityService(current.Projects)
+
+	for _, profile := range profiles {
+		if profile.Webhook == nil {
+			continue
+		}
+
+		oldResult, err := before.PredictCompatibility(profile.System)
+		if err != nil {
+			slog.Error("Webhook prediction failed", "profile", profile.ID, "error", err)
+			continue
+		}
+		newResult, err := after.PredictCompatibility(profile.System)
+		if err != nil {
+			slog.Error("Webhook prediction failed", "profile", profile.ID, "error", err)
+			continue
+		}
+
+		impact := compareResults(profile.Name, oldResult, newResult)
+		if len(impact.Gained) == 0 && len(impact.Lost) == 0 {
+			continue
+		}
+
+		eventID, err := newEventID()
+		if err != nil {
+			slog.Error("Failed to create webhook event", "profile", profile.ID, "error", err)
+			continue
+		}
+		event := models.WebhookEvent{
+			ID:                     eventID,
+			Type:                   models.WebhookEventCompatibilityChanged,
+			CreatedAt:              time.Now().UTC(),
+			Profile:                models.WebhookProfile{ID: profile.ID, Name: profile.Name},
+			DatasetVersion:         current.Version.Version,
+			PreviousDatasetVersion: previous.Version.Version,
+			DatasetSource:          current.Version.Source,
+			NewlyCompatible:        impact.Gained,
+			NewlyIncompatible:      impact.Lost,
+			CompatibleCount:        impact.CompatibleAfter,
+		}
+		go n.deliver(profile.ID, *profile.Webhook, event)
+	}
+}
+
+// deliver POSTs event until it is accepted, the attempts run out or the
+// receiver rejects it with a status that retrying cannot fix
+func (n *WebhookNotifier) deliver(profileID string, webhook models.ProfileWebhook, event models.WebhookEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to encode webhook event", "event", event.ID, "error", err)
+		return
+	}
+
+	backoff := n.opts.Backoff
+	for attempt := 1; attempt <= n.opts.MaxAttempts; attempt++ {
+		record := n.send(webhook, event, body)
+		record.ProfileID = profileID
+		record.EventID = event.ID
+		record.Attempt = attempt
+		if err := n.log.AppendWebhookDelivery(&record); err != nil {
+			slog.Warn("Failed to record webhook delivery", "event", event.ID, "error", err)
+		}
+
+		if record.Delivered {
+			slog.Info("Delivered webhook event", "profile", profileID, "event", event.ID, "attempt", attempt)
+			break
+		}
+		if !retryableDelivery(record) || attempt == n.opts.MaxAttempts {
+			slog.Warn("Webhook delivery failed", "profile", profileID, "event", event.ID,
+				"attempt", attempt, "status", record.StatusCode, "error", record.Error)
+			break
+		}
+
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	if err := n.log.PruneWebhookDeliveries(maxWebhookDeliveries); err != nil {
+		slog.Warn("Failed to prune webhook deliveries", "error", err)
+	}
+}
+
+// send makes one delivery attempt
+func (n *WebhookNotifier) send(webhook models.ProfileWebhook, event models.WebhookEvent, body []byte) models.WebhookDelivery {
+	record := models.WebhookDelivery{
+		URL:       webhook.URL,
+		Timestamp: time.Now().UTC(),
+	}
+
+	request, err := http.NewRequestWithContext(n.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+
+	timestamp := strconv.FormatInt(record.Timestamp.Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "api-predict-webhooks/1.0")
+	request.Header.Set(WebhookIDHeader, event.ID)
+	request.Header.Set(WebhookEventHeader, event.Type)
+	request.Header.Set(WebhookTimestampHeader, timestamp)
+	request.Header.Set(WebhookSignatureHeader, WebhookSignature(webhook.Secret, timestamp, body))
+
+	response, err := n.client.Do(request)
+	record.DurationMS = time.Since(record.Timestamp).Milliseconds()
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	record.StatusCode = response.StatusCode
+	record.Delivered = response.StatusCode >= 200 && response.StatusCode < 300
+	if !record.Delivered {
+		record.Error = fmt.Sprintf("receiver answered %s", response.Status)
+	}
+	return record
+}
+
+// newWebhookClient returns the client used for deliveries. Unless
+// allowPrivate is set, it refuses to connect to internal addresses. The check
+// runs on the address actually dialled, after DNS resolution and on every
+// redirect, so a host cannot be re-pointed after its webhook was registered.
+// Guarded deliveries connect directly rather than through a proxy.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				addr, err := netip.ParseAddr(host)
+				if err != nil || blockedWebhookAddress(addr) {
+					return fmt.Errorf("%w: %s", errBlockedWebhookTarget, host)
+				}
+				return nil
+			},
+		}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// CheckWebhookURL checks that webhookURL is an absolute http or https URL.
+// Unless allowPrivate is set, its host must also resolve only to public
+// addresses: loopback, private, link-local (including the 169.254.169.254
+// metadata service), shared and multicast addresses are rejected.
+func CheckWebhookURL(ctx context.Context, webhookURL string, allowPrivate bool) error {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	host := parsed.Hostname()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: host %q does not resolve", ErrInvalidWebhook, host)
+	}
+	for _, addr := range addrs {
+		if blockedWebhookAddress(addr) {
+			return fmt.Errorf("%w: host %q resolves to %s, which is not a public address", ErrInvalidWebhook, host, addr.Unmap())
+		}
+	}
+	return nil
+}
+
+// blockedWebhookAddress reports whether addr is an internal address that
+// webhooks may not reach
+func blockedWebhookAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// retryableDelivery reports whether a failed attempt may succeed later:
+// network errors, timeouts, rate limiting and server errors
+func retryableDelivery(record models.WebhookDelivery) bool {
+	switch {
+	case record.StatusCode == 0:
+		return true
+	case record.StatusCode == http.StatusRequestTimeout, record.StatusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return record.StatusCode >= 500
+	}
+}
+
+// WebhookSignature signs a webhook body sent at timestamp (Unix seconds) as
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+func WebhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newEventID returns a random webhook event ID
+func newEventID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	return "evt_" + hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// memoryProfiles keeps saved profiles in memory
+type memoryProfiles struct {
+	mu       sync.Mutex
+	profiles map[string]models.SavedProfile
+}
+
+func newMemoryProfiles(profiles ...models.SavedProfile) *memoryProfiles {
+	m := &memoryProfiles{profiles: make(map[string]models.SavedProfile)}
+	for _, profile := range profiles {
+		m.profiles[profile.ID] = profile
+	}
+	return m
+}
+
+func (m *memoryProfiles) PutProfile(profile models.SavedProfile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.profiles[profile.ID] = profile
+	return nil
+}
+
+func (m *memoryProfiles) Profile(id string) (models.SavedProfile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	profile, ok := m.profiles[id]
+	if !ok {
+		return models.SavedProfile{}, errors.New("not found")
+	}
+	return profile, nil
+}
+
+func (m *memoryProfiles) Profiles() ([]models.SavedProfile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var profiles []models.SavedProfile
+	for _, profile := range m.profiles {
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (m *memoryProfiles) DeleteProfile(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.profiles, id)
+	return nil
+}
+
+// memoryDeliveries records webhook delivery attempts and signals each one
+type memoryDeliveries struct {
+	mu       sync.Mutex
+	records  []models.WebhookDelivery
+	recorded chan struct{}
+}
+
+func newMemoryDeliveries() *memoryDeliveries {
+	return &memoryDeliveries{recorded: make(chan struct{}, 16)}
+}
+
+func (m *memoryDeliveries) AppendWebhookDelivery(record *models.WebhookDelivery) error {
+	m.mu.Lock()
+	m.records = append(m.records, *record)
+	m.mu.Unlock()
+	m.recorded <- struct{}{}
+	return nil
+}
+
+func (m *memoryDeliveries) PruneWebhookDeliveries(int) error { return nil }
+
+// receivedWebhook is a request seen by the test receiver
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver starts a local receiver answering status
+func webhookReceiver(t *testing.T, status int) (*httptest.Server, chan receivedWebhook) {
+	t.Helper()
+	received := make(chan receivedWebhook, 16)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedWebhook{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver, received
+}
+
+// webhookSnapshots returns two dataset versions; the profile below loses
+// Beta in the second
+func webhookSnapshots() (models.DatasetSnapshot, models.DatasetSnapshot) {
+	alpha := models.DePINProject{Name: "Alpha", Type: "Storage", NodeType: "Full", CPUCoresMin: 2, RAMGBMin: 4, StorageType: "SSD", CostCategory: "Low", SupportedOS: "Linux"}
+	beta := alpha
+	beta.Name = "Beta"
+
+	previous := models.DatasetSnapshot{
+		Version:  models.DatasetVersion{Version: 1, Source: "csv"},
+		Projects: []models.DePINProject{alpha, beta},
+	}
+	current := models.DatasetSnapshot{
+		Version:  models.DatasetVersion{Version: 2, Source: "admin"},
+		Projects: []models.DePINProject{alpha},
+	}
+	return previous, current
+}
+
+func webhookProfile(url string) models.SavedProfile {
+	return models.SavedProfile{
+		ID:     "p1",
+		Name:   "rig",
+		System: models.SystemSpec{CPUCores: 8, RAMGB: 16, StorageGB: 1000, HasSSD: true, NetworkMbps: 100, OS: "Linux"},
+		Webhook: &models.ProfileWebhook{
+			URL:    url,
+			Secret: "0123456789abcdef",
+		},
+	}
+}
+
+func TestWebhookNotifierSendsSignedEvent(t *testing.T) {
+	receiver, received := webhookReceiver(t, http.StatusNoContent)
+	deliveries := newMemoryDeliveries()
+	notifier := NewWebhookNotifier(context.Background(), newMemoryProfiles(webhookProfile(receiver.URL+"/hook")), deliveries, WebhookOptions{
+		MaxAttempts:         1,
+		Timeout:             5 * time.Second,
+		AllowPrivateTargets: true,
+	})
+
+	previous, current := webhookSnapshots()
+	notifier.notify(previous, current)
+
+	var request receivedWebhook
+	select {
+	case request = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook received")
+	}
+
+	timestamp := request.header.Get(WebhookTimestampHeader)
+	want := WebhookSignature("0123456789abcdef", timestamp, request.body)
+	if got := request.header.Get(WebhookSignatureHeader); timestamp == "" || got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if WebhookSignature("wrong-secret-value", timestamp, request.body) == want {
+		t.Error("signature does not depend on the secret")
+	}
+
+	var event models.WebhookEvent
+	if err := json.Unmarshal(request.body, &event); err != nil {
+		t.Fatalf("invalid event %q: %v", request.body, err)
+	}
+	if event.Type != models.WebhookEventCompatibilityChanged || event.Profile.ID != "p1" ||
+		event.DatasetVersion != 2 || event.PreviousDatasetVersion != 1 || event.DatasetSource != "admin" {
+		t.Errorf("event = %+v", event)
+	}
+	if len(event.NewlyIncompatible) != 1 || event.NewlyIncompatible[0] != "Beta" || len(event.NewlyCompatible) != 0 || event.CompatibleCount != 1 {
+		t.Errorf("event changes = %+v", event)
+	}
+	if request.header.Get(WebhookIDHeader) != event.ID || request.header.Get(WebhookEventHeader) != event.Type {
+		t.Errorf("headers = %v", request.header)
+	}
+
+	<-deliveries.recorded
+	if record := deliveries.records[0]; !record.Delivered || record.StatusCode != http.StatusNoContent || record.EventID != event.ID {
+		t.Errorf("delivery record = %+v", record)
+	}
+}
+
+func TestWebhookNotifierSkipsUnchangedCompatibility(t *testing.T) {
+	receiver, received := webhookReceiver(t, http.StatusNoContent)
+	notifier := NewWebhookNotifier(context.Background(), newMemoryProfiles(webhookProfile(receiver.URL)), newMemoryDeliveries(), WebhookOptions{
+		MaxAttempts:         1,
+		Timeout:             5 * time.Second,
+		AllowPrivateTargets: true,
+	})
+
+	// A new version with the same projects changes nothing for the profile
+	previous, _ := webhookSnapshots()
+	current := previous
+	current.Version.Version = 2
+	notifier.notify(previous, current)
+
+	select {
+	case request := <-received:
+		t.Errorf("webhook sent for unchanged compatibility: %s", request.body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	attempts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	deliveries := newMemoryDeliveries()
+	notifier := NewWebhookNotifier(context.Background(), newMemoryProfiles(), deliveries, WebhookOptions{
+		MaxAttempts:         3,
+		Timeout:             5 * time.Second,
+		Backoff:             time.Millisecond,
+		AllowPrivateTargets: true,
+	})
+	notifier.deliver("p1", models.ProfileWebhook{URL: receiver.URL, Secret: "s"}, models.WebhookEvent{ID: "evt_1"})
+
+	if len(deliveries.records) != 2 || deliveries.records[0].Delivered || !deliveries.records[1].Delivered || deliveries.records[1].Attempt != 2 {
+		t.Errorf("delivery records = %+v, want a failed then a delivered attempt", deliveries.records)
+	}
+}
+
+func TestWebhookNotifierRefusesPrivateTargets(t *testing.T) {
+	receiver, received := webhookReceiver(t, http.StatusNoContent)
+	deliveries := newMemoryDeliveries()
+	notifier := NewWebhookNotifier(context.Background(), newMemoryProfiles(), deliveries, WebhookOptions{
+		MaxAttempts: 1,
+		Timeout:     5 * time.Second,
+	})
+
+	// The receiver listens on loopback, as a re-pointed public host would
+	notifier.deliver("p1", models.ProfileWebhook{URL: receiver.URL, Secret: "s"}, models.WebhookEvent{ID: "evt_1"})
+
+	select {
+	case <-received:
+		t.Error("delivery reached a loopback receiver")
+	default:
+	}
+	if len(deliveries.records) != 1 || deliveries.records[0].Delivered || !strings.Contains(deliveries.records[0].Error, "not a public address") {
+		t.Errorf("delivery records = %+v", deliveries.records)
+	}
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		valid        bool
+	}{
+		{"https://93.184.216.34/hook", false, true},
+		{"http://[2606:2800:220:1::1]:8080/hook", false, true},
+		{"ftp://93.184.216.34/hook", false, false},
+		{"https:///hook", false, false},
+		{"not a url", false, false},
+		{"http://127.0.0.1:9000/hook", false, false},
+		{"http://localhost/hook", false, false},
+		{"http://[::1]/hook", false, false},
+		{"http://[::ffff:127.0.0.1]/hook", false, false},
+		{"http://0.0.0.0/hook", false, false},
+		{"http://10.1.2.3/hook", false, false},
+		{"http://172.16.0.1/hook", false, false},
+		{"http://192.168.1.10/hook", false, false},
+		{"http://[fd00::1]/hook", false, false},
+		{"http://169.254.169.254/latest/meta-data/", false, false},
+		{"http://[fe80::1]/hook", false, false},
+		{"http://100.100.100.200/hook", false, false},
+		{"http://224.0.0.1/hook", false, false},
+		{"http://127.0.0.1:9000/hook", true, true},
+		{"http://169.254.169.254/", true, true},
+		{"ftp://127.0.0.1/hook", true, false},
+	}
+
+	for _, tt := range tests {
+		err := CheckWebhookURL(context.Background(), tt.url, tt.allowPrivate)
+		if (err == nil) != tt.valid {
+			t.Errorf("CheckWebhookURL(%q, allowPrivate=%v) = %v, want valid %v", tt.url, tt.allowPrivate, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("CheckWebhookURL(%q) error %v is not ErrInvalidWebhook", tt.url, err)
+		}
+	}
+}
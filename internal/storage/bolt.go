@@ -0,0 +1,353 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// openTimeout bounds the wait for the database file lock held by another process
+const openTimeout = 5 * time.Second
+
+// BoltStore is a Store kept in a single bbolt database file
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBolt opens or creates the database at path and applies pending migrations
+func OpenBolt(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage '%s': %w", path, err)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate storage '%s': %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close closes the database file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Meta returns a named value, or "" if it is not set
+func (s *BoltStore) Meta(key string) (string, error) {
+	var value string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value = string(tx.Bucket(bucketMeta).Get([]byte(key)))
+		return nil
+	})
+	return value, err
+}
+
+// SetMeta stores a named value
+func (s *BoltStore) SetMeta(key, value string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Put([]byte(key), []byte(value))
+	})
+}
+
+// DatasetVersions returns the stored dataset versions, oldest first
+func (s *BoltStore) DatasetVersions() ([]models.DatasetSnapshot, error) {
+	var snapshots []models.DatasetSnapshot
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketDatasets).ForEach(func(_, value []byte) error {
+			var snapshot models.DatasetSnapshot
+			if err := json.Unmarshal(value, &snapshot); err != nil {
+				return fmt.Errorf("failed to decode dataset version: %w", err)
+			}
+			snapshots = append(snapshots, snapshot)
+			return nil
+		})
+	})
+	return snapshots, err
+}
+
+// SaveDatasetVersion stores a dataset version, replacing one with the same number
+func (s *BoltStore) SaveDatasetVersion(snapshot models.DatasetSnapshot) error {
+	return s.put(bucketDatasets, encodeID(uint64(snapshot.Version.Version)), snapshot)
+}
+
+// PruneDatasetVersions deletes all but the newest keep versions
+func (s *BoltStore) PruneDatasetVersions(keep int) error {
+	return s.pruneOldest(bucketDatasets, keep)
+}
+
+// AppendChange assigns the record the next ID and stores it
+func (s *BoltStore) AppendChange(record *models.ProjectChangeRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketChanges)
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		record.ID = int64(id)
+
+		value, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode change record: %w", err)
+		}
+		return bucket.Put(encodeID(id), value)
+	})
+}
+
+// ListChanges returns up to limit changes, newest first, optionally only
+// those of one project. A limit of 0 returns every change.
+func (s *BoltStore) ListChanges(project string, limit int) ([]models.ProjectChangeRecord, error) {
+	changes := []models.ProjectChangeRecord{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(bucketChanges).Cursor()
+		for key, value := cursor.Last(); key != nil; key, value = cursor.Prev() {
+			var record models.ProjectChangeRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode change record: %w", err)
+			}
+			if project != "" && record.Project != project {
+				continue
+			}
+			changes = append(changes, record)
+			if limit > 0 && len(changes) == limit {
+				break
+			}
+		}
+		return nil
+	})
+	return changes, err
+}
+
+// APIKeys returns the stored API keys, hashed, ordered by name
+func (s *BoltStore) APIKeys() ([]auth.APIKey, error) {
+	var keys []auth.APIKey
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketAPIKeys).ForEach(func(_, value []byte) error {
+			var key auth.APIKey
+			if err := json.Unmarshal(value, &key); err != nil {
+				return fmt.Errorf("failed to decode API key: %w", err)
+			}
+			keys = append(keys, key)
+			return nil
+		})
+	})
+	return keys, err
+}
+
+// ReplaceAPIKeys replaces every stored key. Plaintext keys are hashed first.
+func (s *BoltStore) ReplaceAPIKeys(keys []auth.APIKey) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(bucketAPIKeys); err != nil {
+			return err
+		}
+		bucket, err := tx.CreateBucket(bucketAPIKeys)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if key.Key != "" {
+				key.KeyHash = auth.HashKey(key.Key)
+				key.Key = ""
+			}
+			value, err := json.Marshal(key)
+			if err != nil {
+				return fmt.Errorf("failed to encode API key %q: %w", key.Name, err)
+			}
+			if err := bucket.Put([]byte(key.Name), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutProfile stores a saved system profile, replacing one with the same ID
+func (s *BoltStore) PutProfile(profile models.SavedProfile) error {
+	return s.put(bucketProfiles, []byte(profile.ID), profile)
+}
+
+// Profile returns the saved system profile with the given ID
+func (s *BoltStore) Profile(id string) (models.SavedProfile, error) {
+	var profile models.SavedProfile
+	err := s.get(bucketProfiles, []byte(id), &profile)
+	return profile, err
+}
+
+// Profiles returns every saved system profile, ordered by ID
+func (s *BoltStore) Profiles() ([]models.SavedProfile, error) {
+	profiles := []models.SavedProfile{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketProfiles).ForEach(func(_, value []byte) error {
+			var profile models.SavedProfile
+			if err := json.Unmarshal(value, &profile); err != nil {
+				return fmt.Errorf("failed to decode profile: %w", err)
+			}
+			profiles = append(profiles, profile)
+			return nil
+		})
+	})
+	return profiles, err
+}
+
+// DeleteProfile deletes a saved system profile
+func (s *BoltStore) DeleteProfile(id string) error {
+	return s.delete(bucketProfiles, []byte(id))
+}
+
+// PutPrediction stores a prediction, replacing one with the same ID
+func (s *BoltStore) PutPrediction(prediction models.StoredPrediction) error {
+	return s.put(bucketPredictions, []byte(prediction.ID), prediction)
+}
+
+// Prediction returns the stored prediction with the given ID
+func (s *BoltStore) Prediction(id string) (models.StoredPrediction, error) {
+	var prediction models.StoredPrediction
+	err := s.get(bucketPredictions, []byte(id), &prediction)
+	return prediction, err
+}
+
+// DeletePrediction deletes a stored prediction
+func (s *BoltStore) DeletePrediction(id string) error {
+	return s.delete(bucketPredictions, []byte(id))
+}
+
+// PrunePredictions deletes predictions created before the cutoff
+func (s *BoltStore) PrunePredictions(before time.Time) (int, error) {
+	deleted := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketPredictions)
+
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var prediction models.StoredPrediction
+			if err := json.Unmarshal(value, &prediction); err != nil {
+				return fmt.Errorf("failed to decode prediction: %w", err)
+			}
+			if prediction.CreatedAt.Before(before) {
+				expired = append(expired, bytes.Clone(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	return deleted, err
+}
+
+// AppendWebhookDelivery assigns the record the next ID and stores it
+func (s *BoltStore) AppendWebhookDelivery(record *models.WebhookDelivery) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketDeliveries)
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		record.ID = int64(id)
+
+		value, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook delivery: %w", err)
+		}
+		return bucket.Put(encodeID(id), value)
+	})
+}
+
+// ListWebhookDeliveries returns up to limit deliveries of a profile's events,
+// newest first. A limit of 0 returns every delivery.
+func (s *BoltStore) ListWebhookDeliveries(profileID string, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(bucketDeliveries).Cursor()
+		for key, value := cursor.Last(); key != nil; key, value = cursor.Prev() {
+			var record models.WebhookDelivery
+			if err := json.Unmarshal(value, &record); err != nil {
+				return fmt.Errorf("failed to decode webhook delivery: %w", err)
+			}
+			if record.ProfileID != profileID {
+				continue
+			}
+			deliveries = append(deliveries, record)
+			if limit > 0 && len(deliveries) == limit {
+				break
+			}
+		}
+		return nil
+	})
+	return deliveries, err
+}
+
+// PruneWebhookDeliveries deletes all but the newest keep deliveries
+func (s *BoltStore) PruneWebhookDeliveries(keep int) error {
+	return s.pruneOldest(bucketDeliveries, keep)
+}
+
+// put stores value as JSON under key
+func (s *BoltStore) put(bucket, key []byte, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", bucket, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, encoded)
+	})
+}
+
+// get decodes the JSON stored under key into value
+func (s *BoltStore) get(bucket, key []byte, value interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		encoded := tx.Bucket(bucket).Get(key)
+		if encoded == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(encoded, value)
+	})
+}
+
+// delete removes key, returning ErrNotFound if it does not exist
+func (s *BoltStore) delete(bucket, key []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get(key) == nil {
+			return ErrNotFound
+		}
+		return b.Delete(key)
+	})
+}
+
+// pruneOldest deletes all but the keep entries with the highest keys
+func (s *BoltStore) pruneOldest(bucket []byte, keep int) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		excess := b.Stats().KeyN - keep
+		cursor := b.Cursor()
+		for key, _ := cursor.First(); key != nil && excess > 0; key, _ = cursor.First() {
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+			excess--
+		}
+		return nil
+	})
+}
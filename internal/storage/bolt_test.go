@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestStore(t *testing.T) (*BoltStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "api.db")
+	store, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, path
+}
+
+func TestDatasetVersions(t *testing.T) {
+	store, _ := openTestStore(t)
+
+	for version := 1; version <= 4; version++ {
+		snapshot := models.DatasetSnapshot{
+			Version:  models.DatasetVersion{Version: version, Source: "admin"},
+			Projects: []models.DePINProject{{Name: "Alpha", CPUCoresMin: version}},
+		}
+		if err := store.SaveDatasetVersion(snapshot); err != nil {
+			t.Fatalf("SaveDatasetVersion(%d): %v", version, err)
+		}
+	}
+	if err := store.PruneDatasetVersions(2); err != nil {
+		t.Fatalf("PruneDatasetVersions: %v", err)
+	}
+
+	snapshots, err := store.DatasetVersions()
+	if err != nil {
+		t.Fatalf("DatasetVersions: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Version.Version != 3 || snapshots[1].Version.Version != 4 {
+		t.Fatalf("versions = %+v, want 3 and 4 oldest first", snapshots)
+	}
+	if snapshots[1].Projects[0].CPUCoresMin != 4 {
+		t.Errorf("version 4 projects = %+v", snapshots[1].Projects)
+	}
+}
+
+func TestMeta(t *testing.T) {
+	store, _ := openTestStore(t)
+
+	if value, err := store.Meta("missing"); err != nil || value != "" {
+		t.Errorf("Meta(missing) = %q, %v", value, err)
+	}
+	if err := store.SetMeta("csv_checksum", "abc"); err != nil {
+		t.Fatalf("SetMeta: %v", err)
+	}
+	if value, _ := store.Meta("csv_checksum"); value != "abc" {
+		t.Errorf("Meta = %q, want abc", value)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	store, _ := openTestStore(t)
+
+	for _, project := range []string{"Alpha", "Beta", "Alpha"} {
+		record := &models.ProjectChangeRecord{Action: models.ChangeUpdate, Project: project}
+		if err := store.AppendChange(record); err != nil {
+			t.Fatalf("AppendChange: %v", err)
+		}
+		if record.ID == 0 {
+			t.Fatal("AppendChange did not assign an ID")
+		}
+	}
+
+	all, err := store.ListChanges("", 0)
+	if err != nil || len(all) != 3 || all[0].ID != 3 || all[2].ID != 1 {
+		t.Fatalf("ListChanges = %+v, %v; want 3 newest first", all, err)
+	}
+	alpha, _ := store.ListChanges("Alpha", 1)
+	if len(alpha) != 1 || alpha[0].ID != 3 {
+		t.Errorf("ListChanges(Alpha, 1) = %+v, want the latest Alpha change", alpha)
+	}
+}
+
+func TestAPIKeysAreHashed(t *testing.T) {
+	store, _ := openTestStore(t)
+
+	err := store.ReplaceAPIKeys([]auth.APIKey{{Name: "ops", Key: "secret", Scopes: []string{auth.ScopeAdmin}}})
+	if err != nil {
+		t.Fatalf("ReplaceAPIKeys: %v", err)
+	}
+	if err := store.ReplaceAPIKeys([]auth.APIKey{{Name: "alice", Key: "k-alice", Scopes: []string{auth.ScopeRead}}}); err != nil {
+		t.Fatalf("ReplaceAPIKeys: %v", err)
+	}
+
+	keys, err := store.APIKeys()
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("APIKeys = %+v, %v; want only alice", keys, err)
+	}
+	if keys[0].Key != "" || keys[0].KeyHash != auth.HashKey("k-alice") {
+		t.Errorf("stored key = %+v, want only the hash", keys[0])
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	store, _ := openTestStore(t)
+
+	if _, err := store.Profile("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Profile(missing) error = %v, want ErrNotFound", err)
+	}
+
+	profile := models.SavedProfile{ID: "p1", Name: "desktop", System: models.SystemSpec{CPUCores: 8}}
+	if err := store.PutProfile(profile); err != nil {
+		t.Fatalf("PutProfile: %v", err)
+	}
+	got, err := store.Profile("p1")
+	if err != nil || got.Name != "desktop" || got.System.CPUCores != 8 {
+		t.Errorf("Profile = %+v, %v", got, err)
+	}
+
+	if err := store.DeleteProfile("p1"); err != nil {
+		t.Fatalf("DeleteProfile: %v", err)
+	}
+	if err := store.DeleteProfile("p1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second DeleteProfile error = %v, want ErrNotFound", err)
+	}
+	if profiles, _ := store.Profiles(); len(profiles) != 0 {
+		t.Errorf("Profiles = %+v, want none", profiles)
+	}
+}
+
+func TestPrunePredictions(t *testing.T) {
+	store, _ := openTestStore(t)
+	now := time.Now()
+
+	for id, age := range map[string]time.Duration{"old": 48 * time.Hour, "new": time.Hour} {
+		if err := store.PutPrediction(models.StoredPrediction{ID: id, CreatedAt: now.Add(-age)}); err != nil {
+			t.Fatalf("PutPrediction: %v", err)
+		}
+	}
+
+	deleted, err := store.PrunePredictions(now.Add(-24 * time.Hour))
+	if err != nil || deleted != 1 {
+		t.Fatalf("PrunePredictions = %d, %v; want 1", deleted, err)
+	}
+	if _, err := store.Prediction("old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("old prediction error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Prediction("new"); err != nil {
+		t.Errorf("new prediction: %v", err)
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	store, _ := openTestStore(t)
+
+	for i, profile := range []string{"p1", "p2", "p1", "p1"} {
+		if err := store.AppendWebhookDelivery(&models.WebhookDelivery{ProfileID: profile, Attempt: i + 1}); err != nil {
+			t.Fatalf("AppendWebhookDelivery: %v", err)
+		}
+	}
+	if err := store.PruneWebhookDeliveries(3); err != nil {
+		t.Fatalf("PruneWebhookDeliveries: %v", err)
+	}
+
+	deliveries, err := store.ListWebhookDeliveries("p1", 0)
+	if err != nil || len(deliveries) != 2 || deliveries[0].Attempt != 4 || deliveries[1].Attempt != 3 {
+		t.Errorf("ListWebhookDeliveries = %+v, %v; want attempts 4 and 3", deliveries, err)
+	}
+}
+
+func TestReopenKeepsData(t *testing.T) {
+	store, path := openTestStore(t)
+	if err := store.SetMeta("key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	reopened, err := OpenBolt(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+
+	if value, _ := reopened.Meta("key"); value != "value" {
+		t.Errorf("Meta after reopen = %q", value)
+	}
+}
+
+func TestNewerSchemaRejected(t *testing.T) {
+	store, path := openTestStore(t)
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Put(schemaVersionKey, encodeID(uint64(len(migrations)+1)))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	if reopened, err := OpenBolt(path); err == nil {
+		reopened.Close()
+		t.Fatal("OpenBolt accepted a database from a newer release")
+	}
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/simoncrean/api-predict/internal/data"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// CSVSource is the project dataset CSV. It is read-only: projects edited
+// through the admin API are kept in the Store, and the CSV is imported again
+// only when its content changes.
+type CSVSource struct {
+	path string
+}
+
+// NewCSVSource creates a source for the CSV file at path
+func NewCSVSource(path string) *CSVSource {
+	return &CSVSource{path: path}
+}
+
+// Path returns the CSV file path
+func (s *CSVSource) Path() string {
+	return s.path
+}
+
+// Load parses the CSV like the dataset loader and returns its projects with
+// a checksum of the file content
+func (s *CSVSource) Load() ([]models.DePINProject, string, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open CSV file '%s': %w", s.path, err)
+	}
+
+	projects, err := data.NewLoader(s.path).ParseDePINSpecs(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return projects, hex.EncodeToString(sum[:]), nil
+}
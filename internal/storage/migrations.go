@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets of the bolt database
+var (
+	bucketMeta        = []byte("meta")
+	bucketDatasets    = []byte("datasets")
+	bucketChanges     = []byte("changes")
+	bucketAPIKeys     = []byte("api_keys")
+	bucketProfiles    = []byte("profiles")
+	bucketPredictions = []byte("predictions")
+	bucketDeliveries  = []byte("webhook_deliveries")
+)
+
+// schemaVersionKey holds the number of applied migrations in the meta bucket
+var schemaVersionKey = []byte("schema_version")
+
+// migration upgrades the database schema by one version
+type migration struct {
+	description string
+	apply       func(tx *bolt.Tx) error
+}
+
+// migrations are applied in order; migration i brings the schema to version
+// i+1. Append new migrations, never edit or reorder applied ones.
+var migrations = []migration{
+	{
+		description: "create buckets",
+		apply: func(tx *bolt.Tx) error {
+			for _, name := range [][]byte{bucketDatasets, bucketChanges, bucketAPIKeys, bucketProfiles, bucketPredictions} {
+				if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		description: "create webhook delivery log",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(bucketDeliveries)
+			return err
+		},
+	},
+}
+
+// migrate applies pending migrations, each in its own transaction. A
+// database written by a newer release is rejected rather than modified.
+func migrate(db *bolt.DB) error {
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this release supports (%d)", current, len(migrations))
+	}
+
+	for version := current + 1; version <= len(migrations); version++ {
+		m := migrations[version-1]
+		err := db.Update(func(tx *bolt.Tx) error {
+			if err := m.apply(tx); err != nil {
+				return err
+			}
+			return tx.Bucket(bucketMeta).Put(schemaVersionKey, encodeID(uint64(version)))
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %w", version, m.description, err)
+		}
+		slog.Info("Applied storage migration", "version", version, "description", m.description)
+	}
+	return nil
+}
+
+// schemaVersion returns the applied schema version, creating the meta bucket
+// of a new database
+func schemaVersion(db *bolt.DB) (int, error) {
+	var version int
+	err := db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(bucketMeta)
+		if err != nil {
+			return err
+		}
+		if value := meta.Get(schemaVersionKey); value != nil {
+			version = int(binary.BigEndian.Uint64(value))
+		}
+		return nil
+	})
+	return version, err
+}
+
+// encodeID encodes a sequence number so keys sort numerically
+func encodeID(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
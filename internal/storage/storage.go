@@ -0,0 +1,85 @@
+// Package storage persists service state across restarts: dataset versions,
+// the project change history, API keys, saved system profiles, predictions
+// and the webhook delivery log. The project CSV remains a read-only source
+// that is imported into the store.
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/simoncrean/api-predict/internal/auth"
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// ErrNotFound is returned when a stored item does not exist
+var ErrNotFound = errors.New("not found")
+
+// Store persists service state
+type Store interface {
+	DatasetStore
+	ChangeStore
+	KeyStore
+	ProfileStore
+	PredictionStore
+	WebhookStore
+
+	// Meta and SetMeta hold small named values such as import checksums
+	Meta(key string) (string, error)
+	SetMeta(key, value string) error
+
+	Close() error
+}
+
+// DatasetStore persists dataset versions
+type DatasetStore interface {
+	// DatasetVersions returns the stored versions, oldest first
+	DatasetVersions() ([]models.DatasetSnapshot, error)
+	SaveDatasetVersion(snapshot models.DatasetSnapshot) error
+	// PruneDatasetVersions deletes all but the newest keep versions
+	PruneDatasetVersions(keep int) error
+}
+
+// ChangeStore persists the project change history
+type ChangeStore interface {
+	// AppendChange assigns the record the next ID and stores it
+	AppendChange(record *models.ProjectChangeRecord) error
+	// ListChanges returns up to limit changes, newest first, optionally only
+	// those of one project. A limit of 0 returns every change.
+	ListChanges(project string, limit int) ([]models.ProjectChangeRecord, error)
+}
+
+// KeyStore persists API keys, hashed
+type KeyStore interface {
+	APIKeys() ([]auth.APIKey, error)
+	ReplaceAPIKeys(keys []auth.APIKey) error
+}
+
+// ProfileStore persists saved system profiles
+type ProfileStore interface {
+	PutProfile(profile models.SavedProfile) error
+	Profile(id string) (models.SavedProfile, error)
+	Profiles() ([]models.SavedProfile, error)
+	DeleteProfile(id string) error
+}
+
+// PredictionStore persists prediction results
+type PredictionStore interface {
+	PutPrediction(prediction models.StoredPrediction) error
+	Prediction(id string) (models.StoredPrediction, error)
+	DeletePrediction(id string) error
+	// PrunePredictions deletes predictions created before the cutoff and
+	// returns how many were deleted
+	PrunePredictions(before time.Time) (int, error)
+}
+
+// WebhookStore persists the webhook delivery log
+type WebhookStore interface {
+	// AppendWebhookDelivery assigns the record the next ID and stores it
+	AppendWebhookDelivery(record *models.WebhookDelivery) error
+	// ListWebhookDeliveries returns up to limit deliveries of a profile's
+	// events, newest first. A limit of 0 returns every delivery.
+	ListWebhookDeliveries(profileID string, limit int) ([]models.WebhookDelivery, error)
+	// PruneWebhookDeliveries deletes all but the newest keep deliveries
+	PruneWebhookDeliveries(keep int) error
+}
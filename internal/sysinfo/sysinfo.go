@@ -0,0 +1,276 @@
+// Package sysinfo builds a SystemSpec from Linux procfs and sysfs
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// PCI vendor IDs of discrete GPU makers, as reported in sysfs
+var gpuVendors = map[string]string{
+	"0x10de": "NVIDIA",
+	"0x1002": "AMD",
+}
+
+// Detector reads hardware information below Root, which is "/" on a live
+// system or a directory holding a copy of proc/ and sys/ for fixtures
+type Detector struct {
+	Root string
+
+	// NetworkMbps, when set, is used instead of the detected link speed
+	NetworkMbps int
+}
+
+// NewDetector creates a detector reading from root
+func NewDetector(root string) *Detector {
+	if root == "" {
+		root = "/"
+	}
+	return &Detector{Root: root}
+}
+
+// Detect builds a SystemSpec for the machine. Notes describe values that
+// could not be detected and need to be supplied by the user.
+func (d *Detector) Detect() (models.SystemSpec, []string, error) {
+	var notes []string
+	spec := models.SystemSpec{OS: "Linux"}
+
+	cores, err := d.cpuCores()
+	if err != nil {
+		return spec, nil, fmt.Errorf("failed to detect CPU cores: %w", err)
+	}
+	spec.CPUCores = cores
+
+	ram, err := d.memoryGB()
+	if err != nil {
+		return spec, nil, fmt.Errorf("failed to detect memory: %w", err)
+	}
+	spec.RAMGB = ram
+
+	drives, err := d.storageDevices()
+	if err != nil {
+		return spec, nil, fmt.Errorf("failed to detect storage: %w", err)
+	}
+	spec.StorageDevices = drives
+	if len(drives) == 0 {
+		notes = append(notes, "no physical storage devices found under sys/block")
+	}
+
+	gpus, gpuNotes := d.gpus()
+	spec.GPUs = gpus
+	notes = append(notes, gpuNotes...)
+
+	spec.NetworkMbps = d.NetworkMbps
+	if spec.NetworkMbps > 0 {
+		return spec, notes, nil
+	}
+
+	spec.NetworkMbps = d.networkMbps()
+	if spec.NetworkMbps == 0 {
+		notes = append(notes, "network speed unknown; pass it explicitly")
+	} else {
+		notes = append(notes, "network speed is the fastest link speed, not measured internet bandwidth")
+	}
+
+	return spec, notes, nil
+}
+
+// cpuCores counts physical cores in /proc/cpuinfo as unique (physical id,
+// core id) pairs, so SMT siblings count once. Kernels that omit those fields,
+// as on many ARM systems, fall back to counting logical processors.
+func (d *Detector) cpuCores() (int, error) {
+	type processor struct{ physicalID, coreID string }
+
+	var processors []processor
+	err := d.scanLines("proc/cpuinfo", func(line string) {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if key == "processor" {
+			processors = append(processors, processor{})
+			return
+		}
+		if len(processors) == 0 {
+			return
+		}
+		switch key {
+		case "physical id":
+			processors[len(processors)-1].physicalID = value
+		case "core id":
+			processors[len(processors)-1].coreID = value
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(processors) == 0 {
+		return 0, fmt.Errorf("no processors listed")
+	}
+
+	cores := make(map[processor]bool)
+	for _, p := range processors {
+		if p.physicalID == "" || p.coreID == "" {
+			return len(processors), nil
+		}
+		cores[p] = true
+	}
+	return len(cores), nil
+}
+
+// memoryGB reads MemTotal from /proc/meminfo, rounded to whole GB. The kernel
+// reserves some memory, so a 16GB machine reports slightly less.
+func (d *Detector) memoryGB() (int, error) {
+	var totalKB int
+	err := d.scanLines("proc/meminfo", func(line string) {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			totalKB, _ = strconv.Atoi(fields[1])
+		}
+	})
+	if err == nil && totalKB == 0 {
+		err = fmt.Errorf("MemTotal not found")
+	}
+	return int(math.Round(float64(totalKB) / (1024 * 1024))), err
+}
+
+// storageDevices lists physical, non-removable block devices. Virtual devices
+// (loop, zram, device mapper) have no device link in sysfs and are skipped.
+func (d *Detector) storageDevices() ([]models.StorageDevice, error) {
+	entries, err := os.ReadDir(d.path("sys/block"))
+	if err != nil {
+		return nil, err
+	}
+
+	var drives []models.StorageDevice
+	for _, entry := range entries {
+		name := entry.Name()
+		dir := filepath.Join("sys/block", name)
+
+		if _, err := os.Stat(d.path(dir, "device")); err != nil {
+			continue
+		}
+		if d.readString(dir, "removable") == "1" {
+			continue
+		}
+
+		// size is always in 512-byte sectors; report decimal GB like drive vendors
+		sectors, err := strconv.ParseInt(d.readString(dir, "size"), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		drive := models.StorageDevice{
+			Type:       models.StorageHDD,
+			CapacityGB: int(sectors * 512 / 1e9),
+		}
+		if drive.CapacityGB == 0 {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(name, "nvme"):
+			drive.Type = models.StorageNVMe
+		case d.readString(dir, "queue/rotational") == "0":
+			drive.Type = models.StorageSSD
+		}
+
+		drives = append(drives, drive)
+	}
+
+	return drives, nil
+}
+
+// gpus lists discrete graphics cards from /sys/class/drm. Only amdgpu exposes
+// VRAM in sysfs; other cards are reported with unknown VRAM.
+func (d *Detector) gpus() ([]models.GPU, []string) {
+	entries, err := os.ReadDir(d.path("sys/class/drm"))
+	if err != nil {
+		return nil, nil
+	}
+
+	var gpus []models.GPU
+	var notes []string
+	for _, entry := range entries {
+		name := entry.Name()
+		// card0, card1... but not connectors such as card0-HDMI-A-1
+		if !strings.HasPrefix(name, "card") || strings.Contains(name, "-") {
+			continue
+		}
+
+		device := filepath.Join("sys/class/drm", name, "device")
+		vendor, ok := gpuVendors[d.readString(device, "vendor")]
+		if !ok {
+			continue
+		}
+
+		gpu := models.GPU{Model: vendor}
+		if bytes, err := strconv.ParseInt(d.readString(device, "mem_info_vram_total"), 10, 64); err == nil {
+			gpu.VRAMGB = int(math.Round(float64(bytes) / (1 << 30)))
+		} else {
+			notes = append(notes, fmt.Sprintf("%s %s: VRAM not exposed in sysfs; set vram_gb manually", vendor, name))
+		}
+
+		gpus = append(gpus, gpu)
+	}
+
+	return gpus, notes
+}
+
+// networkMbps returns the fastest link speed of a physical network interface
+func (d *Detector) networkMbps() int {
+	entries, err := os.ReadDir(d.path("sys/class/net"))
+	if err != nil {
+		return 0
+	}
+
+	fastest := 0
+	for _, entry := range entries {
+		dir := filepath.Join("sys/class/net", entry.Name())
+		if _, err := os.Stat(d.path(dir, "device")); err != nil {
+			continue
+		}
+		// speed is -1 or unreadable when the link is down
+		if speed, err := strconv.Atoi(d.readString(dir, "speed")); err == nil {
+			fastest = max(fastest, speed)
+		}
+	}
+	return fastest
+}
+
+// path joins parts below the detector root
+func (d *Detector) path(parts ...string) string {
+	return filepath.Join(append([]string{d.Root}, parts...)...)
+}
+
+// readString returns a trimmed sysfs attribute, or "" when unreadable
+func (d *Detector) readString(parts ...string) string {
+	content, err := os.ReadFile(d.path(parts...))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// scanLines calls fn for each line of a file below the root
+func (d *Detector) scanLines(name string, fn func(line string)) error {
+	file, err := os.Open(d.path(name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+	return scanner.Err()
+}
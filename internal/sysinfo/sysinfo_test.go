@@ -0,0 +1,166 @@
+package sysinfo
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// fixture returns a detector reading a tree below testdata
+func fixture(name string) *Detector {
+	return NewDetector(filepath.Join("testdata", name))
+}
+
+func TestCPUCores(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    int
+		wantErr bool
+	}{
+		{"smt", 4, false},         // 8 threads on 4 cores
+		{"dual-socket", 4, false}, // core ids repeat across sockets
+		{"arm", 4, false},         // no core ids, counts processors
+		{"empty", 0, true},
+		{"missing", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := fixture(tt.fixture).cpuCores()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: cpuCores() = %d, %v; want %d, error %v", tt.fixture, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMemoryGB(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    int
+		wantErr bool
+	}{
+		{"smt", 16, false},
+		{"dual-socket", 63, false},
+		{"arm", 4, false},
+		{"empty", 0, true},
+		{"missing", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := fixture(tt.fixture).memoryGB()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: memoryGB() = %d, %v; want %d, error %v", tt.fixture, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestStorageDevices(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []models.StorageDevice
+		wantErr bool
+	}{
+		// Removable and virtual devices are skipped
+		{"smt", []models.StorageDevice{
+			{Type: models.StorageNVMe, CapacityGB: 512},
+			{Type: models.StorageHDD, CapacityGB: 2000},
+		}, false},
+		{"dual-socket", []models.StorageDevice{{Type: models.StorageSSD, CapacityGB: 1000}}, false},
+		{"arm", []models.StorageDevice{{Type: models.StorageSSD, CapacityGB: 31}}, false},
+		{"empty", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := fixture(tt.fixture).storageDevices()
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: storageDevices() = %+v, %v; want %+v, error %v", tt.fixture, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGPUs(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		want      []models.GPU
+		wantNotes int
+	}{
+		// The Intel card and the HDMI connector are skipped
+		{"smt", []models.GPU{{Model: "AMD", VRAMGB: 8}}, 0},
+		{"dual-socket", []models.GPU{{Model: "NVIDIA"}}, 1},
+		{"arm", nil, 0},
+	}
+
+	for _, tt := range tests {
+		got, notes := fixture(tt.fixture).gpus()
+		if !reflect.DeepEqual(got, tt.want) || len(notes) != tt.wantNotes {
+			t.Errorf("%s: gpus() = %+v, notes %q; want %+v with %d notes", tt.fixture, got, notes, tt.want, tt.wantNotes)
+		}
+	}
+}
+
+func TestNetworkMbps(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    int
+	}{
+		{"smt", 1000}, // eth0; wlan0 is down and lo has no device
+		{"dual-socket", 0},
+		{"arm", 0},
+	}
+
+	for _, tt := range tests {
+		if got := fixture(tt.fixture).networkMbps(); got != tt.want {
+			t.Errorf("%s: networkMbps() = %d, want %d", tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	spec, notes, err := fixture("smt").Detect()
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+
+	want := models.SystemSpec{
+		CPUCores:    4,
+		RAMGB:       16,
+		NetworkMbps: 1000,
+		OS:          "Linux",
+		GPUs:        []models.GPU{{Model: "AMD", VRAMGB: 8}},
+		StorageDevices: []models.StorageDevice{
+			{Type: models.StorageNVMe, CapacityGB: 512},
+			{Type: models.StorageHDD, CapacityGB: 2000},
+		},
+	}
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("Detect() = %+v, want %+v", spec, want)
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "link speed") {
+		t.Errorf("notes = %q, want the link speed caveat", notes)
+	}
+}
+
+func TestDetectNetworkOverride(t *testing.T) {
+	detector := fixture("dual-socket")
+	detector.NetworkMbps = 250
+
+	spec, notes, err := detector.Detect()
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if spec.NetworkMbps != 250 {
+		t.Errorf("NetworkMbps = %d, want the override", spec.NetworkMbps)
+	}
+	// Only the unknown VRAM note remains
+	if len(notes) != 1 || !strings.Contains(notes[0], "VRAM") {
+		t.Errorf("notes = %q", notes)
+	}
+}
+
+func TestDetectFailsWithoutCPUInfo(t *testing.T) {
+	if _, _, err := fixture("empty").Detect(); err == nil || !strings.Contains(err.Error(), "CPU") {
+		t.Errorf("Detect() error = %v, want a CPU detection error", err)
+	}
+}
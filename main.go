@@ -2,100 +2,501 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/simoncrean/api-predict/internal/api"
+	"github.com/simoncrean/api-predict/internal/auth"
 	"github.com/simoncrean/api-predict/internal/data"
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/graphqlapi"
+	"github.com/simoncrean/api-predict/internal/grpcapi"
+	"github.com/simoncrean/api-predict/internal/health"
+	"github.com/simoncrean/api-predict/internal/logging"
+	"github.com/simoncrean/api-predict/internal/metrics"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/ratelimit"
+	"github.com/simoncrean/api-predict/internal/server"
 	"github.com/simoncrean/api-predict/internal/service"
+	"github.com/simoncrean/api-predict/internal/storage"
 
-	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
 )
 
 const (
 	defaultPort     = "8080"
+	defaultGRPCPort = "9090"
 	defaultHost     = "0.0.0.0"
 	defaultDataPath = "./data/depin_specs.csv" // Will use depin_specifications_final.csv if available
 )
 
 func main() {
+	// Subcommands replace the server, e.g. "api-predict detect -predict"
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
+	healthCheck := flag.Bool("health-check", false, "Probe the running server's /readyz endpoint and exit 0 when ready")
+	flag.Parse()
+
 	// Load configuration from environment
-	config := loadConfig()
+	config, err := loadConfig()
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
+	}
+
+	if *healthCheck {
+		os.Exit(runHealthCheck(config))
+	}
+
+	// Initialize structured logging
+	logger, err := logging.New(os.Stdout, config.LogLevel, config.LogFormat)
+	if err != nil {
+		fatal("Invalid logging configuration", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	// Background tasks run until shutdown
+	ctx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Open persistent storage, applying migrations
+	store, err := storage.OpenBolt(config.StoragePath)
+	if err != nil {
+		fatal("Failed to open storage", "path", config.StoragePath, "error", err)
+	}
+	defer store.Close()
 
-	// Initialize data loader
-	dataLoader := data.NewLoader(config.DataPath)
-	depinProjects, err := dataLoader.LoadDePINSpecs()
+	// Restore stored dataset versions, importing the CSV when it changed
+	csvSource := storage.NewCSVSource(config.DataPath)
+	compatibilityService, err := openDataset(csvSource, store, config.DatasetVersionRetention, config.CSVOverwritesEdits)
 	if err != nil {
-		log.Fatalf("Failed to load DePIN specifications: %v", err)
+		fatal("Failed to load DePIN specifications", "path", config.DataPath, "error", err)
 	}
+	depinProjects := compatibilityService.GetProjects()
 
-	log.Printf("Loaded %d DePIN projects", len(depinProjects))
+	slog.Info("Loaded DePIN projects", "count", len(depinProjects), "version", compatibilityService.CurrentDatasetVersion().Version)
 
-	// Initialize services
-	compatibilityService := service.NewCompatibilityService(depinProjects)
+	// Initialize metrics and readiness
+	loadedAt := time.Now()
+	appMetrics := metrics.New()
+	appMetrics.SetDataset(len(depinProjects), loadedAt)
+	readiness := health.NewReadiness()
+	readiness.DatasetLoaded(len(depinProjects), loadedAt)
+
+	// Reference systems are optional; without them impact reports are unavailable
+	referenceSystems, err := data.LoadReferenceSystems(config.ReferenceSystemsPath, config.SpecLimits)
+	if err != nil {
+		slog.Warn("Reference systems unavailable", "path", config.ReferenceSystemsPath, "error", err)
+	}
+
+	// Dataset reloads, admin edits and summary changes are streamed to clients
+	eventBroker := events.NewBroker(events.DefaultHistorySize)
+	compatibilityService.AddDatasetListener(publishDatasetChanges(eventBroker))
+
+	// Admin project edits become stored dataset versions with a change history
+	projectEditor := service.NewProjectEditor(compatibilityService, store,
+		func(record models.ProjectChangeRecord, projects []models.DePINProject) {
+			changedAt := time.Now()
+			appMetrics.SetDataset(len(projects), changedAt)
+			readiness.DatasetLoaded(len(projects), changedAt)
+			eventBroker.Publish(models.StreamEventProjectChanged, record)
+		})
+
+	// Initialize API keys
+	keyStore, err := loadKeyStore(config.APIKeysFile, store)
+	if err != nil {
+		fatal("Failed to load API keys", "path", config.APIKeysFile, "error", err)
+	}
+	keyUsage := auth.NewUsageTracker()
+
+	// Initialize rate limiters, one store per route group
+	rateLimiters := make(map[string]*ratelimit.Limiter, len(server.RateLimitGroups))
+	for _, group := range server.RateLimitGroups {
+		limit := config.rateLimit(group)
+		limiter := ratelimit.NewLimiter(rate.Limit(limit.Rate), limit.Burst, config.RateLimitMaxClients, config.RateLimitIdleTTL)
+		limiter.StartCleanup(ctx, time.Minute)
+		rateLimiters[group] = limiter
+	}
+
+	// Saved profiles with a webhook are notified after every dataset change
+	webhookNotifier := service.NewWebhookNotifier(ctx, store, store, service.WebhookOptions{
+		MaxAttempts: config.WebhookMaxAttempts,
+		Timeout:     config.WebhookTimeout,
+		Backoff:     config.WebhookBackoff,
+
+		AllowPrivateTargets: config.WebhookAllowPrivate,
+	})
+	compatibilityService.AddDatasetListener(webhookNotifier.DatasetChanged)
+
+	// Shared predictions are pruned after the retention period
+	predictionArchive := service.NewPredictionArchive(store, config.PredictionRetention)
+	predictionArchive.StartPruning(ctx, time.Hour)
 
 	// Initialize API handlers
-	handlers := api.NewHandlers(compatibilityService)
+	handlers := api.NewHandlers(compatibilityService, appMetrics, readiness, config.SpecLimits, predictionArchive)
+	adminHandlers := api.NewAdminHandlers(keyStore, keyUsage, compatibilityService, referenceSystems, projectEditor, store)
+	profileHandlers := api.NewProfileHandlers(service.NewProfileService(compatibilityService, store, config.WebhookAllowPrivate), store, appMetrics, config.SpecLimits)
+	graphQLSchema, err := graphqlapi.NewSchema(compatibilityService, appMetrics, config.SpecLimits)
+	if err != nil {
+		fatal("Invalid GraphQL schema", "error", err)
+	}
 
 	// Setup router
-	router := setupRouter(handlers)
+	router, err := server.NewRouter(server.Config{
+		TrustedProxies: config.TrustedProxies,
+		RequireAPIKey:  config.RequireAPIKey,
+		SpecLimits:     config.SpecLimits,
+	}, server.Components{
+		Handlers:        handlers,
+		AdminHandlers:   adminHandlers,
+		ProfileHandlers: profileHandlers,
+		Metrics:         appMetrics,
+		KeyStore:        keyStore,
+		KeyUsage:        keyUsage,
+		RateLimiters:    rateLimiters,
+		EventBroker:     eventBroker,
+		GraphQLSchema:   graphQLSchema,
+	})
+	if err != nil {
+		fatal("Invalid TRUSTED_PROXIES", "error", err)
+	}
 
 	// Create HTTP server
-	server := &http.Server{
+	httpServer := &http.Server{
 		Addr:    fmt.Sprintf("%s:%s", config.Host, config.Port),
 		Handler: router,
 	}
+	// Close event streams when shutdown starts, or they would hold it up
+	httpServer.RegisterOnShutdown(eventBroker.Close)
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("🚀 DePIN Compatibility API starting on %s:%s", config.Host, config.Port)
-		log.Printf("📊 Health check: http://localhost:%s/api/v1/health", config.Port)
-		log.Printf("📖 API docs: http://localhost:%s/api/v1/docs", config.Port)
+		slog.Info("DePIN Compatibility API starting",
+			"addr", httpServer.Addr,
+			"health", fmt.Sprintf("http://localhost:%s/api/v1/health", config.Port),
+			"docs", fmt.Sprintf("http://localhost:%s/api/v1/docs", config.Port),
+			"log_level", config.LogLevel,
+		)
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("Failed to start server", "addr", httpServer.Addr, "error", err)
 		}
 	}()
 
-	// Wait for interrupt signal to gracefully shutdown
+	// Serve the gRPC interface on its own port from the same dataset
+	var grpcServer *grpc.Server
+	if config.GRPCPort != "" {
+		guard := grpcapi.NewGuard(keyStore, config.RequireAPIKey, grpcapi.RateLimiters{
+			Default: rateLimiters[server.RateLimitDefault],
+			Predict: rateLimiters[server.RateLimitPredict],
+			Read:    rateLimiters[server.RateLimitRead],
+		}, keyUsage, appMetrics, slog.Default())
+		grpcServer = grpc.NewServer(guard.ServerOptions()...)
+		grpcapi.NewServer(compatibilityService, appMetrics, readiness, config.SpecLimits).Register(grpcServer)
+
+		grpcAddr := fmt.Sprintf("%s:%s", config.Host, config.GRPCPort)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			fatal("Failed to start gRPC server", "addr", grpcAddr, "error", err)
+		}
+
+		go func() {
+			slog.Info("gRPC server starting", "addr", grpcAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				fatal("gRPC server failed", "addr", grpcAddr, "error", err)
+			}
+		}()
+	}
+
+	// Reload the dataset on SIGHUP, shut down gracefully on SIGINT/SIGTERM
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
 
-	log.Println("⏳ Shutting down server...")
+	for waiting := true; waiting; {
+		select {
+		case <-reload:
+			reloadDataset(csvSource, store, compatibilityService, config.CSVOverwritesEdits, appMetrics, readiness)
+		case <-quit:
+			waiting = false
+		}
+	}
+
+	// Fail readiness first so load balancers stop routing new requests
+	readiness.StartDraining()
+	slog.Info("Draining before shutdown", "delay", config.ShutdownDrainDelay.String())
+	time.Sleep(config.ShutdownDrainDelay)
+
+	slog.Info("Shutting down server")
 
 	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
-		log.Printf("❌ Server forced to shutdown: %v", err)
+	// Stop the gRPC server alongside, cancelling calls still running at the timeout
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		if grpcServer == nil {
+			return
+		}
+		stop := context.AfterFunc(shutdownCtx, grpcServer.Stop)
+		defer stop()
+		grpcServer.GracefulStop()
+		slog.Info("gRPC server shutdown complete")
+	}()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Server forced to shutdown", "error", err)
 	} else {
-		log.Println("✅ Server shutdown complete")
+		slog.Info("Server shutdown complete")
+	}
+	<-grpcStopped
+}
+
+// runCommand runs a CLI subcommand and returns the process exit code
+func runCommand(name string, args []string) int {
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		return 2
+	}
+
+	switch name {
+	case "detect":
+		return runDetect(args, config)
+	case "predict":
+		return runPredict(args, config)
+	case "data":
+		return runData(args, config)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q; available commands: detect, predict, data\n", name)
+		return 2
 	}
 }
 
 // Config holds application configuration
 type Config struct {
-	Port     string
-	Host     string
-	DataPath string
-	LogLevel string
+	Port          string
+	GRPCPort      string // Empty disables the gRPC server
+	Host          string
+	DataPath      string
+	LogLevel      string
+	LogFormat     string
+	APIKeysFile   string
+	RequireAPIKey bool
+
+	// Named SystemSpec profiles used to gauge dataset changes
+	ReferenceSystemsPath string
+
+	// Database file for dataset versions, project history, API keys,
+	// saved profiles and predictions
+	StoragePath string
+
+	// Dataset versions kept for as_of and dataset_version queries
+	DatasetVersionRetention int
+
+	// Import a changed CSV even when it would discard live admin edits
+	CSVOverwritesEdits bool
+
+	// How long persisted predictions are kept, zero for forever
+	PredictionRetention time.Duration
+
+	// Webhook delivery attempts per event, per-attempt timeout and the wait
+	// before the first retry, doubled for each further retry
+	WebhookMaxAttempts int
+	WebhookTimeout     time.Duration
+	WebhookBackoff     time.Duration
+
+	// Accept webhooks on loopback, private and link-local addresses
+	WebhookAllowPrivate bool
+
+	// Rate limiting, keyed by route group with "default" as the fallback
+	RateLimits          map[string]ratelimit.Limit
+	RateLimitMaxClients int
+	RateLimitIdleTTL    time.Duration
+
+	// Proxies whose X-Forwarded-For/X-Real-IP headers are trusted
+	TrustedProxies []string
+
+	// Time readiness reports draining before the server stops accepting requests
+	ShutdownDrainDelay time.Duration
+
+	// Accepted SystemSpec ranges
+	SpecLimits models.SpecLimits
+}
+
+// defaultRateLimit allows 10 requests per second with a burst of 20
+var defaultRateLimit = ratelimit.Limit{Rate: 10, Burst: 20}
+
+// rateLimit returns the limit for a route group, falling back to "default"
+func (c *Config) rateLimit(group string) ratelimit.Limit {
+	if limit, ok := c.RateLimits[group]; ok {
+		return limit
+	}
+	if limit, ok := c.RateLimits[server.RateLimitDefault]; ok {
+		return limit
+	}
+	return defaultRateLimit
 }
 
 // loadConfig loads configuration from environment variables
-func loadConfig() *Config {
-	return &Config{
+func loadConfig() (*Config, error) {
+	config := &Config{
 		Port:     getEnv("PORT", defaultPort),
+		GRPCPort: getEnv("GRPC_PORT", defaultGRPCPort),
 		Host:     getEnv("HOST", defaultHost),
 		DataPath: getEnv("DATA_PATH", defaultDataPath),
 		LogLevel: getEnv("LOG_LEVEL", "info"),
+
+		LogFormat: getEnv("LOG_FORMAT", "json"),
+
+		APIKeysFile:   getEnv("API_KEYS_FILE", ""),
+		RequireAPIKey: getEnv("REQUIRE_API_KEY", "false") == "true",
+
+		CSVOverwritesEdits: getEnv("CSV_IMPORT_OVERWRITE_EDITS", "false") == "true",
+
+		WebhookAllowPrivate: getEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false") == "true",
+
+		TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "")),
+	}
+
+	// Reference systems live next to the dataset unless configured
+	config.ReferenceSystemsPath = getEnv("REFERENCE_SYSTEMS_PATH", filepath.Join(filepath.Dir(config.DataPath), "reference_systems.json"))
+	config.StoragePath = getEnv("STORAGE_PATH", filepath.Join(filepath.Dir(config.DataPath), "api-predict.db"))
+
+	var err error
+	if config.RateLimits, err = parseRateLimits(getEnv("RATE_LIMITS", "")); err != nil {
+		return nil, fmt.Errorf("RATE_LIMITS: %w", err)
+	}
+	if config.SpecLimits, err = parseSpecLimits(getEnv("SPEC_LIMITS", "")); err != nil {
+		return nil, fmt.Errorf("SPEC_LIMITS: %w", err)
+	}
+	if config.RateLimitMaxClients, err = strconv.Atoi(getEnv("RATE_LIMIT_MAX_CLIENTS", "10000")); err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_MAX_CLIENTS: %w", err)
+	}
+	if config.RateLimitIdleTTL, err = time.ParseDuration(getEnv("RATE_LIMIT_IDLE_TTL", "10m")); err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_IDLE_TTL: %w", err)
+	}
+	if config.ShutdownDrainDelay, err = time.ParseDuration(getEnv("SHUTDOWN_DRAIN_DELAY", "5s")); err != nil {
+		return nil, fmt.Errorf("SHUTDOWN_DRAIN_DELAY: %w", err)
+	}
+	if config.DatasetVersionRetention, err = strconv.Atoi(getEnv("DATASET_VERSION_RETENTION", strconv.Itoa(service.DefaultVersionRetention))); err != nil {
+		return nil, fmt.Errorf("DATASET_VERSION_RETENTION: %w", err)
+	}
+	if config.DatasetVersionRetention < 1 {
+		return nil, fmt.Errorf("DATASET_VERSION_RETENTION: must be at least 1")
+	}
+	if config.PredictionRetention, err = time.ParseDuration(getEnv("PREDICTION_RETENTION", service.DefaultPredictionRetention.String())); err != nil {
+		return nil, fmt.Errorf("PREDICTION_RETENTION: %w", err)
+	}
+	if config.PredictionRetention < 0 {
+		return nil, fmt.Errorf("PREDICTION_RETENTION: must not be negative")
+	}
+	if config.WebhookMaxAttempts, err = strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", strconv.Itoa(service.DefaultWebhookMaxAttempts))); err != nil {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS: %w", err)
+	}
+	if config.WebhookMaxAttempts < 1 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS: must be at least 1")
+	}
+	if config.WebhookTimeout, err = time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", service.DefaultWebhookTimeout.String())); err != nil {
+		return nil, fmt.Errorf("WEBHOOK_TIMEOUT: %w", err)
+	}
+	if config.WebhookBackoff, err = time.ParseDuration(getEnv("WEBHOOK_BACKOFF", service.DefaultWebhookBackoff.String())); err != nil {
+		return nil, fmt.Errorf("WEBHOOK_BACKOFF: %w", err)
+	}
+
+	return config, nil
+}
+
+// parseRateLimits parses "group=rate:burst" pairs, e.g. "default=10:20,predict=5:10"
+func parseRateLimits(spec string) (map[string]ratelimit.Limit, error) {
+	limits := make(map[string]ratelimit.Limit)
+
+	for _, entry := range splitList(spec) {
+		group, value, found := strings.Cut(entry, "=")
+		if !found || !slices.Contains(server.RateLimitGroups, group) {
+			return nil, fmt.Errorf("invalid entry %q, expected one of %v as group=rate:burst", entry, server.RateLimitGroups)
+		}
+
+		rateValue, burstValue, found := strings.Cut(value, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid entry %q, expected group=rate:burst", entry)
+		}
+
+		r, err := strconv.ParseFloat(rateValue, 64)
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("invalid rate in %q", entry)
+		}
+		b, err := strconv.Atoi(burstValue)
+		if err != nil || b <= 0 {
+			return nil, fmt.Errorf("invalid burst in %q", entry)
+		}
+
+		limits[group] = ratelimit.Limit{Rate: r, Burst: b}
 	}
+
+	return limits, nil
+}
+
+// parseSpecLimits parses "field=min:max" pairs overriding the default
+// SystemSpec ranges, e.g. "cpu_cores=1:2048,gpus=0:32"
+func parseSpecLimits(spec string) (models.SpecLimits, error) {
+	limits := models.DefaultSpecLimits()
+
+	for _, entry := range splitList(spec) {
+		field, value, found := strings.Cut(entry, "=")
+		minValue, maxValue, rangeFound := strings.Cut(value, ":")
+		if !found || !rangeFound {
+			return limits, fmt.Errorf("invalid entry %q, expected field=min:max", entry)
+		}
+
+		lo, err := strconv.Atoi(minValue)
+		if err != nil {
+			return limits, fmt.Errorf("invalid minimum in %q", entry)
+		}
+		hi, err := strconv.Atoi(maxValue)
+		if err != nil {
+			return limits, fmt.Errorf("invalid maximum in %q", entry)
+		}
+
+		if err := limits.Set(field, models.Range{Min: lo, Max: hi}); err != nil {
+			return limits, err
+		}
+	}
+
+	return limits, nil
+}
+
+// splitList splits a comma-separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// fatal logs an error record and exits
+func fatal(msg string, args ...interface{}) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
 
 // getEnv gets environment variable with fallback
@@ -105,44 +506,3 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
-
-// setupRouter configures the HTTP router
-func setupRouter(handlers *api.Handlers) *gin.Engine {
-	// Set gin mode based on environment
-	if os.Getenv("GIN_MODE") == "" {
-		gin.SetMode(gin.ReleaseMode)
-	}
-
-	router := gin.New()
-
-	// Add middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
-	router.Use(api.CORSMiddleware())
-	router.Use(api.RateLimitMiddleware())
-
-	// API routes
-	v1 := router.Group("/api/v1")
-	{
-		// Core endpoints
-		v1.POST("/predict", handlers.PredictCompatibility)
-		v1.GET("/health", handlers.HealthCheck)
-		v1.GET("/projects", handlers.ListProjects)
-
-		// Utility endpoints
-		v1.GET("/docs", handlers.APIDocs)
-		v1.GET("/metrics", handlers.Metrics)
-	}
-
-	// Root endpoint
-	router.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"service": "DePIN Resource Predict API",
-			"version": "1.0.0",
-			"docs":    "/api/v1/docs",
-			"health":  "/api/v1/health",
-		})
-	})
-
-	return router
-}
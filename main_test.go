@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+func TestParseSpecLimits(t *testing.T) {
+	limits, err := parseSpecLimits("cpu_cores=1:2048, gpus=0:32")
+	if err != nil {
+		t.Fatalf("parseSpecLimits: %v", err)
+	}
+	want := models.DefaultSpecLimits()
+	want.CPUCores = models.Range{Min: 1, Max: 2048}
+	want.GPUs = models.Range{Min: 0, Max: 32}
+	if limits != want {
+		t.Errorf("limits = %+v, want %+v", limits, want)
+	}
+
+	if limits, err := parseSpecLimits(""); err != nil || limits != models.DefaultSpecLimits() {
+		t.Errorf("empty spec = %+v, %v, want the defaults", limits, err)
+	}
+
+	for _, spec := range []string{
+		"cpu_cores",
+		"cpu_cores=8",
+		"cpu_cores=a:8",
+		"cpu_cores=1:b",
+		"cpu_cores=8:1",
+		"warp_drive=1:8",
+	} {
+		if _, err := parseSpecLimits(spec); err == nil {
+			t.Errorf("parseSpecLimits(%q) accepted", spec)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// Output formats for CLI predictions
+const (
+	formatTable    = "table"
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+)
+
+var outputFormats = []string{formatTable, formatJSON, formatMarkdown}
+
+// writePrediction renders a prediction in the given format
+func writePrediction(w io.Writer, result *models.PredictionResponse, format string) error {
+	switch format {
+	case formatTable:
+		return writePredictionTable(w, result)
+	case formatJSON:
+		return writeJSON(w, result)
+	case formatMarkdown:
+		return writePredictionMarkdown(w, result)
+	default:
+		return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(outputFormats, ", "))
+	}
+}
+
+// writePredictionTable writes an aligned plain-text table
+func writePredictionTable(w io.Writer, result *models.PredictionResponse) error {
+	fmt.Fprintf(w, "System rating: %s\n", result.Summary.SystemRating)
+	fmt.Fprintf(w, "Compatible: %d of %d projects\n\n", result.Summary.CompatibleCount, result.Summary.TotalProjects)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "PROJECT\tCOMPATIBLE\tSCORE\tRATING\tCOST\tMISSING")
+	for _, project := range predictionRows(result) {
+		fmt.Fprintf(table, "%s\t%s\t%.2f\t%s\t%s\t%s\n",
+			project.Name, yesNo(project.Compatible), project.CompatibilityScore,
+			project.PerformanceRating, project.EstimatedCost, strings.Join(project.MissingRequirements, "; "))
+	}
+	return table.Flush()
+}
+
+// writePredictionMarkdown writes a Markdown table, e.g. for CI job summaries
+func writePredictionMarkdown(w io.Writer, result *models.PredictionResponse) error {
+	fmt.Fprintf(w, "**System rating:** %s  \n", result.Summary.SystemRating)
+	fmt.Fprintf(w, "**Compatible:** %d of %d projects\n\n", result.Summary.CompatibleCount, result.Summary.TotalProjects)
+
+	fmt.Fprintln(w, "| Project | Compatible | Score | Rating | Cost | Missing |")
+	fmt.Fprintln(w, "|---------|------------|-------|--------|------|---------|")
+	for _, project := range predictionRows(result) {
+		fmt.Fprintf(w, "| %s | %s | %.2f | %s | %s | %s |\n",
+			markdownEscape(project.Name), yesNo(project.Compatible), project.CompatibilityScore,
+			project.PerformanceRating, project.EstimatedCost,
+			markdownEscape(strings.Join(project.MissingRequirements, "; ")))
+	}
+
+	if len(result.Recommendations) > 0 {
+		fmt.Fprintln(w, "\n**Recommendations**")
+		fmt.Fprintln(w)
+		for _, recommendation := range result.Recommendations {
+			fmt.Fprintf(w, "- %s\n", markdownEscape(recommendation))
+		}
+	}
+	return nil
+}
+
+// predictionRows lists compatible projects first, each group in service order
+func predictionRows(result *models.PredictionResponse) []models.CompatibilityResult {
+	rows := make([]models.CompatibilityResult, 0, len(result.CompatibleProjects)+len(result.IncompatibleProjects))
+	rows = append(rows, result.CompatibleProjects...)
+	return append(rows, result.IncompatibleProjects...)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/simoncrean/api-predict/internal/data"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// Exit codes of the CLI subcommands
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitIncompatible = 3 // The -project target is not compatible
+	exitUnknown      = 4 // The -project target is not in the dataset
+)
+
+// runPredict implements the predict subcommand: it predicts compatibility for
+// a SystemSpec given as flags and/or a JSON file without running the server.
+// With -project, the exit code reports whether that project is compatible.
+func runPredict(args []string, config *Config) int {
+	flags := flag.NewFlagSet("predict", flag.ContinueOnError)
+	specFile := flags.String("spec", "", "JSON file with a SystemSpec or {\"system\": ...} request; - reads stdin")
+	dataPath := flags.String("data", config.DataPath, "DePIN specifications CSV")
+	format := flags.String("format", formatTable, "Output format: table, json or markdown")
+	project := flags.String("project", "", "Exit non-zero unless this project is compatible")
+	locale := flags.String("locale", "", "Message locale, e.g. es")
+
+	var flagSpec models.SystemSpec
+	flags.IntVar(&flagSpec.CPUCores, "cpu-cores", 0, "CPU cores")
+	flags.IntVar(&flagSpec.RAMGB, "ram-gb", 0, "RAM in GB")
+	flags.IntVar(&flagSpec.StorageGB, "storage-gb", 0, "Storage in GB")
+	flags.BoolVar(&flagSpec.HasSSD, "ssd", false, "Storage is SSD")
+	flags.BoolVar(&flagSpec.HasGPU, "gpu", false, "Has a dedicated GPU")
+	flags.IntVar(&flagSpec.GPUVRAMGB, "gpu-vram-gb", 0, "GPU VRAM in GB")
+	flags.IntVar(&flagSpec.NetworkMbps, "network-mbps", 0, "Network speed in Mbps")
+	flags.StringVar(&flagSpec.OS, "os", "", "Operating system: Windows, Linux or macOS")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if !slices.Contains(outputFormats, *format) {
+		fmt.Fprintf(os.Stderr, "predict: unknown format %q, expected one of %s\n", *format, strings.Join(outputFormats, ", "))
+		return exitUsage
+	}
+
+	// Start from the file, then apply the flags that were given
+	var spec models.SystemSpec
+	if *specFile != "" {
+		var err error
+		if spec, err = readSpec(*specFile); err != nil {
+			fmt.Fprintf(os.Stderr, "predict: %v\n", err)
+			return exitUsage
+		}
+	}
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "cpu-cores":
+			spec.CPUCores = flagSpec.CPUCores
+		case "ram-gb":
+			spec.RAMGB = flagSpec.RAMGB
+		case "storage-gb":
+			spec.StorageGB = flagSpec.StorageGB
+		case "ssd":
+			spec.HasSSD = flagSpec.HasSSD
+		case "gpu":
+			spec.HasGPU = flagSpec.HasGPU
+		case "gpu-vram-gb":
+			spec.GPUVRAMGB = flagSpec.GPUVRAMGB
+		case "network-mbps":
+			spec.NetworkMbps = flagSpec.NetworkMbps
+		case "os":
+			spec.OS = flagSpec.OS
+		}
+	})
+
+	errs, warnings := models.ValidateSystemSpec(spec, config.SpecLimits)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s: %s\n", warning.Field, warning.Message)
+	}
+	if len(errs) > 0 {
+		for _, fieldErr := range errs {
+			fmt.Fprintf(os.Stderr, "error: %s: %s\n", fieldErr.Field, fieldErr.Message)
+		}
+		return exitUsage
+	}
+
+	projects, err := data.NewLoader(*dataPath).LoadDePINSpecs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "predict: %v\n", err)
+		return exitError
+	}
+
+	svc := service.NewCompatibilityService(projects)
+	result, err := svc.PredictCompatibilityWithOptions(context.Background(), spec, service.PredictOptions{Locale: *locale})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "predict: %v\n", err)
+		return exitError
+	}
+	result.InputWarnings = warnings
+
+	if err := writePrediction(os.Stdout, result, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "predict: %v\n", err)
+		return exitError
+	}
+
+	if *project == "" {
+		return exitOK
+	}
+	return targetExitCode(result, *project)
+}
+
+// targetExitCode reports whether the named project is compatible
+func targetExitCode(result *models.PredictionResponse, name string) int {
+	matches := func(project models.CompatibilityResult) bool {
+		return strings.EqualFold(project.Name, name)
+	}
+
+	if slices.ContainsFunc(result.CompatibleProjects, matches) {
+		return exitOK
+	}
+	if slices.ContainsFunc(result.IncompatibleProjects, matches) {
+		return exitIncompatible
+	}
+
+	fmt.Fprintf(os.Stderr, "predict: project %q not found in dataset\n", name)
+	return exitUnknown
+}
+
+// readSpec reads a SystemSpec from a JSON file, or stdin for "-". Both a bare
+// SystemSpec and a POST /predict request body are accepted.
+func readSpec(path string) (models.SystemSpec, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return models.SystemSpec{}, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	var request struct {
+		System *models.SystemSpec `json:"system"`
+	}
+	if err := json.Unmarshal(content, &request); err == nil && request.System != nil {
+		return *request.System, nil
+	}
+
+	var spec models.SystemSpec
+	if err := json.Unmarshal(content, &spec); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return spec, fmt.Errorf("failed to parse spec %s at offset %d: %w", path, syntaxErr.Offset, err)
+		}
+		return spec, fmt.Errorf("failed to parse spec %s: %w", path, err)
+	}
+	return spec, nil
+}
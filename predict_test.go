@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/models"
+)
+
+// captureOutput redirects os.Stdout and os.Stderr for the rest of the test
+// and returns a function reading what was written to stdout
+func captureOutput(t *testing.T) func() string {
+	t.Helper()
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedStdout, savedStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = savedStdout, savedStderr
+		stdout.Close()
+		stderr.Close()
+	})
+
+	return func() string {
+		content, err := os.ReadFile(stdout.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+}
+
+// predictFixture writes a two-project dataset and returns its path
+func predictFixture(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "specs.csv")
+	writeCSV(t, path, "Light Node,2,4,100,10\nHeavy Node,64,512,20000,10000\n")
+	return path
+}
+
+func TestRunPredictExitCodes(t *testing.T) {
+	config := &Config{SpecLimits: models.DefaultSpecLimits()}
+	system := []string{"-cpu-cores", "8", "-ram-gb", "16", "-storage-gb", "500", "-ssd", "-network-mbps", "100", "-os", "Linux"}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no target", nil, exitOK},
+		{"compatible target", []string{"-project", "light node"}, exitOK},
+		{"incompatible target", []string{"-project", "Heavy Node"}, exitIncompatible},
+		{"unknown target", []string{"-project", "Nope"}, exitUnknown},
+		{"unknown format", []string{"-format", "yaml"}, exitUsage},
+		{"invalid system", []string{"-cpu-cores", "0"}, exitUsage},
+		{"unknown flag", []string{"-cpus", "8"}, exitUsage},
+		{"missing dataset", []string{"-data", filepath.Join(t.TempDir(), "missing.csv")}, exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureOutput(t)
+			args := append([]string{"-data", predictFixture(t)}, system...)
+			if got := runPredict(append(args, tt.args...), config); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPredictSpecFile(t *testing.T) {
+	config := &Config{SpecLimits: models.DefaultSpecLimits()}
+	specPath := filepath.Join(t.TempDir(), "spec.json")
+	spec := `{"system": {"cpu_cores": 2, "ram_gb": 4, "storage_gb": 100, "network_mbps": 10, "os": "Linux"}}`
+	if err := os.WriteFile(specPath, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := captureOutput(t)
+	// Flags override the file, leaving too few cores for the light node
+	args := []string{"-data", predictFixture(t), "-spec", specPath, "-cpu-cores", "1", "-format", "json", "-locale", "es"}
+	if got := runPredict(args, config); got != exitOK {
+		t.Fatalf("exit code = %d, want %d", got, exitOK)
+	}
+
+	var result models.PredictionResponse
+	if err := json.Unmarshal([]byte(stdout()), &result); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if result.Locale != "es" || result.Summary.TotalProjects != 2 {
+		t.Errorf("locale %s with %d projects, want es with 2", result.Locale, result.Summary.TotalProjects)
+	}
+	if result.Summary.CompatibleCount != 0 {
+		t.Errorf("%d compatible projects, want none with the -cpu-cores override", result.Summary.CompatibleCount)
+	}
+}
+
+func TestReadSpec(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		cores   int
+		wantErr string
+	}{
+		{"bare spec", write("bare.json", `{"cpu_cores": 4}`), 4, ""},
+		{"request body", write("request.json", `{"system": {"cpu_cores": 6}, "locale": "es"}`), 6, ""},
+		{"syntax error", write("broken.json", `{"cpu_cores": `), 0, "at offset"},
+		{"wrong type", write("typed.json", `{"cpu_cores": "four"}`), 0, "failed to parse spec"},
+		{"missing file", filepath.Join(dir, "missing.json"), 0, "failed to read spec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := readSpec(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || spec.CPUCores != tt.cores {
+				t.Errorf("spec = %+v, %v, want %d cores", spec, err, tt.cores)
+			}
+		})
+	}
+}
+
+func TestWritePrediction(t *testing.T) {
+	result := &models.PredictionResponse{
+		CompatibleProjects:   []models.CompatibilityResult{{Name: "A|B", Compatible: true, CompatibilityScore: 0.9, PerformanceRating: "Excellent", EstimatedCost: "$1-$2/month"}},
+		IncompatibleProjects: []models.CompatibilityResult{{Name: "C", CompatibilityScore: 0.3, MissingRequirements: []string{"more RAM", "an SSD"}}},
+		Summary:              models.PredictionSummary{TotalProjects: 2, CompatibleCount: 1, SystemRating: models.SystemHighEnd},
+		Recommendations:      []string{"Add RAM"},
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{formatTable, []string{"System rating: High-End", "PROJECT", "A|B", "yes", "0.90", "more RAM; an SSD"}},
+		{formatMarkdown, []string{"| Project |", `| A\|B | yes | 0.90 |`, "| C | no |", "- Add RAM"}},
+		{formatJSON, []string{`"compatible_projects"`, `"system_rating": "High-End"`}},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := writePrediction(&out, result, tt.format); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s output lacks %q:\n%s", tt.format, want, out.String())
+			}
+		}
+	}
+
+	// Compatible projects come first
+	var out strings.Builder
+	writePrediction(&out, result, formatTable)
+	if strings.Index(out.String(), "A|B") > strings.Index(out.String(), "\nC ") {
+		t.Error("incompatible project listed before the compatible one")
+	}
+
+	if err := writePrediction(&out, result, "yaml"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
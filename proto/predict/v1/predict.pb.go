@@ -0,0 +1,1647 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: predict/v1/predict.proto
+
+// Package predict.v1 is the gRPC interface of the DePIN Compatibility API. It
+// mirrors the REST endpoints for predictions, projects and health and is
+// served from the same dataset.
+
+package predictv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SystemSpec describes the hardware of a system.
+type SystemSpec struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CpuCores    int32                  `protobuf:"varint,1,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	RamGb       int32                  `protobuf:"varint,2,opt,name=ram_gb,json=ramGb,proto3" json:"ram_gb,omitempty"`
+	StorageGb   int32                  `protobuf:"varint,3,opt,name=storage_gb,json=storageGb,proto3" json:"storage_gb,omitempty"`
+	HasSsd      bool                   `protobuf:"varint,4,opt,name=has_ssd,json=hasSsd,proto3" json:"has_ssd,omitempty"`
+	HasGpu      bool                   `protobuf:"varint,5,opt,name=has_gpu,json=hasGpu,proto3" json:"has_gpu,omitempty"`
+	GpuVramGb   int32                  `protobuf:"varint,6,opt,name=gpu_vram_gb,json=gpuVramGb,proto3" json:"gpu_vram_gb,omitempty"`
+	NetworkMbps int32                  `protobuf:"varint,7,opt,name=network_mbps,json=networkMbps,proto3" json:"network_mbps,omitempty"`
+	// "Windows", "Linux" or "macOS".
+	Os string `protobuf:"bytes,8,opt,name=os,proto3" json:"os,omitempty"`
+	// Detailed hardware, taking precedence over the flat fields when set.
+	Gpus           []*GPU           `protobuf:"bytes,9,rep,name=gpus,proto3" json:"gpus,omitempty"`
+	StorageDevices []*StorageDevice `protobuf:"bytes,10,rep,name=storage_devices,json=storageDevices,proto3" json:"storage_devices,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SystemSpec) Reset() {
+	*x = SystemSpec{}
+	mi := &file_predict_v1_predict_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemSpec) ProtoMessage() {}
+
+func (x *SystemSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemSpec.ProtoReflect.Descriptor instead.
+func (*SystemSpec) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SystemSpec) GetCpuCores() int32 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *SystemSpec) GetRamGb() int32 {
+	if x != nil {
+		return x.RamGb
+	}
+	return 0
+}
+
+func (x *SystemSpec) GetStorageGb() int32 {
+	if x != nil {
+		return x.StorageGb
+	}
+	return 0
+}
+
+func (x *SystemSpec) GetHasSsd() bool {
+	if x != nil {
+		return x.HasSsd
+	}
+	return false
+}
+
+func (x *SystemSpec) GetHasGpu() bool {
+	if x != nil {
+		return x.HasGpu
+	}
+	return false
+}
+
+func (x *SystemSpec) GetGpuVramGb() int32 {
+	if x != nil {
+		return x.GpuVramGb
+	}
+	return 0
+}
+
+func (x *SystemSpec) GetNetworkMbps() int32 {
+	if x != nil {
+		return x.NetworkMbps
+	}
+	return 0
+}
+
+func (x *SystemSpec) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *SystemSpec) GetGpus() []*GPU {
+	if x != nil {
+		return x.Gpus
+	}
+	return nil
+}
+
+func (x *SystemSpec) GetStorageDevices() []*StorageDevice {
+	if x != nil {
+		return x.StorageDevices
+	}
+	return nil
+}
+
+// GPU describes a single graphics card.
+type GPU struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	VramGb        int32                  `protobuf:"varint,2,opt,name=vram_gb,json=vramGb,proto3" json:"vram_gb,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GPU) Reset() {
+	*x = GPU{}
+	mi := &file_predict_v1_predict_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GPU) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GPU) ProtoMessage() {}
+
+func (x *GPU) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GPU.ProtoReflect.Descriptor instead.
+func (*GPU) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GPU) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *GPU) GetVramGb() int32 {
+	if x != nil {
+		return x.VramGb
+	}
+	return 0
+}
+
+// StorageDevice describes a single drive.
+type StorageDevice struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "NVMe", "SSD" or "HDD".
+	Type       string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	CapacityGb int32  `protobuf:"varint,2,opt,name=capacity_gb,json=capacityGb,proto3" json:"capacity_gb,omitempty"`
+	// 0 means unknown, treated as the full capacity.
+	FreeGb        int32 `protobuf:"varint,3,opt,name=free_gb,json=freeGb,proto3" json:"free_gb,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageDevice) Reset() {
+	*x = StorageDevice{}
+	mi := &file_predict_v1_predict_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageDevice) ProtoMessage() {}
+
+func (x *StorageDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageDevice.ProtoReflect.Descriptor instead.
+func (*StorageDevice) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StorageDevice) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *StorageDevice) GetCapacityGb() int32 {
+	if x != nil {
+		return x.CapacityGb
+	}
+	return 0
+}
+
+func (x *StorageDevice) GetFreeGb() int32 {
+	if x != nil {
+		return x.FreeGb
+	}
+	return 0
+}
+
+type PredictRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	System *SystemSpec            `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
+	// Message locale, e.g. "es"; defaults to English.
+	Locale string `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	// Strip emojis from messages.
+	PlainText bool `protobuf:"varint,3,opt,name=plain_text,json=plainText,proto3" json:"plain_text,omitempty"`
+	// Predict against a past dataset, by version or by the time it was live.
+	// At most one may be set.
+	DatasetVersion int32                  `protobuf:"varint,4,opt,name=dataset_version,json=datasetVersion,proto3" json:"dataset_version,omitempty"`
+	AsOf           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PredictRequest) Reset() {
+	*x = PredictRequest{}
+	mi := &file_predict_v1_predict_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictRequest) ProtoMessage() {}
+
+func (x *PredictRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictRequest.ProtoReflect.Descriptor instead.
+func (*PredictRequest) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PredictRequest) GetSystem() *SystemSpec {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+func (x *PredictRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetPlainText() bool {
+	if x != nil {
+		return x.PlainText
+	}
+	return false
+}
+
+func (x *PredictRequest) GetDatasetVersion() int32 {
+	if x != nil {
+		return x.DatasetVersion
+	}
+	return 0
+}
+
+func (x *PredictRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type PredictResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	CompatibleProjects   []*CompatibilityResult `protobuf:"bytes,1,rep,name=compatible_projects,json=compatibleProjects,proto3" json:"compatible_projects,omitempty"`
+	IncompatibleProjects []*CompatibilityResult `protobuf:"bytes,2,rep,name=incompatible_projects,json=incompatibleProjects,proto3" json:"incompatible_projects,omitempty"`
+	Summary              *PredictionSummary     `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	Recommendations      []string               `protobuf:"bytes,4,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	Locale               string                 `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"`
+	// Suspicious but accepted system fields.
+	InputWarnings    []*FieldError          `protobuf:"bytes,6,rep,name=input_warnings,json=inputWarnings,proto3" json:"input_warnings,omitempty"`
+	DatasetVersion   int32                  `protobuf:"varint,7,opt,name=dataset_version,json=datasetVersion,proto3" json:"dataset_version,omitempty"`
+	DatasetCreatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=dataset_created_at,json=datasetCreatedAt,proto3" json:"dataset_created_at,omitempty"`
+	ScoringStrategy  string                 `protobuf:"bytes,9,opt,name=scoring_strategy,json=scoringStrategy,proto3" json:"scoring_strategy,omitempty"`
+	GeneratedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PredictResponse) Reset() {
+	*x = PredictResponse{}
+	mi := &file_predict_v1_predict_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictResponse) ProtoMessage() {}
+
+func (x *PredictResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictResponse.ProtoReflect.Descriptor instead.
+func (*PredictResponse) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PredictResponse) GetCompatibleProjects() []*CompatibilityResult {
+	if x != nil {
+		return x.CompatibleProjects
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetIncompatibleProjects() []*CompatibilityResult {
+	if x != nil {
+		return x.IncompatibleProjects
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetSummary() *PredictionSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetRecommendations() []string {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetInputWarnings() []*FieldError {
+	if x != nil {
+		return x.InputWarnings
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetDatasetVersion() int32 {
+	if x != nil {
+		return x.DatasetVersion
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetDatasetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DatasetCreatedAt
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetScoringStrategy() string {
+	if x != nil {
+		return x.ScoringStrategy
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetGeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return nil
+}
+
+// CompatibilityResult is the analysis of a system for one project.
+type CompatibilityResult struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Compatible          bool                   `protobuf:"varint,2,opt,name=compatible,proto3" json:"compatible,omitempty"`
+	CompatibilityScore  float64                `protobuf:"fixed64,3,opt,name=compatibility_score,json=compatibilityScore,proto3" json:"compatibility_score,omitempty"`
+	PerformanceRating   string                 `protobuf:"bytes,4,opt,name=performance_rating,json=performanceRating,proto3" json:"performance_rating,omitempty"`
+	EstimatedCost       string                 `protobuf:"bytes,5,opt,name=estimated_cost,json=estimatedCost,proto3" json:"estimated_cost,omitempty"`
+	MissingRequirements []string               `protobuf:"bytes,6,rep,name=missing_requirements,json=missingRequirements,proto3" json:"missing_requirements,omitempty"`
+	// Message codes for missing_requirements.
+	MissingCodes        []string `protobuf:"bytes,7,rep,name=missing_codes,json=missingCodes,proto3" json:"missing_codes,omitempty"`
+	RecommendedUpgrades []string `protobuf:"bytes,8,rep,name=recommended_upgrades,json=recommendedUpgrades,proto3" json:"recommended_upgrades,omitempty"`
+	Warnings            []string `protobuf:"bytes,9,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CompatibilityResult) Reset() {
+	*x = CompatibilityResult{}
+	mi := &file_predict_v1_predict_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompatibilityResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompatibilityResult) ProtoMessage() {}
+
+func (x *CompatibilityResult) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompatibilityResult.ProtoReflect.Descriptor instead.
+func (*CompatibilityResult) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CompatibilityResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CompatibilityResult) GetCompatible() bool {
+	if x != nil {
+		return x.Compatible
+	}
+	return false
+}
+
+func (x *CompatibilityResult) GetCompatibilityScore() float64 {
+	if x != nil {
+		return x.CompatibilityScore
+	}
+	return 0
+}
+
+func (x *CompatibilityResult) GetPerformanceRating() string {
+	if x != nil {
+		return x.PerformanceRating
+	}
+	return ""
+}
+
+func (x *CompatibilityResult) GetEstimatedCost() string {
+	if x != nil {
+		return x.EstimatedCost
+	}
+	return ""
+}
+
+func (x *CompatibilityResult) GetMissingRequirements() []string {
+	if x != nil {
+		return x.MissingRequirements
+	}
+	return nil
+}
+
+func (x *CompatibilityResult) GetMissingCodes() []string {
+	if x != nil {
+		return x.MissingCodes
+	}
+	return nil
+}
+
+func (x *CompatibilityResult) GetRecommendedUpgrades() []string {
+	if x != nil {
+		return x.RecommendedUpgrades
+	}
+	return nil
+}
+
+func (x *CompatibilityResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type PredictionSummary struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalProjects     int32                  `protobuf:"varint,1,opt,name=total_projects,json=totalProjects,proto3" json:"total_projects,omitempty"`
+	CompatibleCount   int32                  `protobuf:"varint,2,opt,name=compatible_count,json=compatibleCount,proto3" json:"compatible_count,omitempty"`
+	IncompatibleCount int32                  `protobuf:"varint,3,opt,name=incompatible_count,json=incompatibleCount,proto3" json:"incompatible_count,omitempty"`
+	CompatibilityRate float64                `protobuf:"fixed64,4,opt,name=compatibility_rate,json=compatibilityRate,proto3" json:"compatibility_rate,omitempty"`
+	AverageScore      float64                `protobuf:"fixed64,5,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	SystemRating      string                 `protobuf:"bytes,6,opt,name=system_rating,json=systemRating,proto3" json:"system_rating,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PredictionSummary) Reset() {
+	*x = PredictionSummary{}
+	mi := &file_predict_v1_predict_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictionSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictionSummary) ProtoMessage() {}
+
+func (x *PredictionSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictionSummary.ProtoReflect.Descriptor instead.
+func (*PredictionSummary) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PredictionSummary) GetTotalProjects() int32 {
+	if x != nil {
+		return x.TotalProjects
+	}
+	return 0
+}
+
+func (x *PredictionSummary) GetCompatibleCount() int32 {
+	if x != nil {
+		return x.CompatibleCount
+	}
+	return 0
+}
+
+func (x *PredictionSummary) GetIncompatibleCount() int32 {
+	if x != nil {
+		return x.IncompatibleCount
+	}
+	return 0
+}
+
+func (x *PredictionSummary) GetCompatibilityRate() float64 {
+	if x != nil {
+		return x.CompatibilityRate
+	}
+	return 0
+}
+
+func (x *PredictionSummary) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+func (x *PredictionSummary) GetSystemRating() string {
+	if x != nil {
+		return x.SystemRating
+	}
+	return ""
+}
+
+// FieldError describes an invalid or suspicious request field.
+type FieldError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Rule          string                 `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldError) Reset() {
+	*x = FieldError{}
+	mi := &file_predict_v1_predict_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldError) ProtoMessage() {}
+
+func (x *FieldError) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldError.ProtoReflect.Descriptor instead.
+func (*FieldError) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FieldError) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldError) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *FieldError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchPredictRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*PredictRequest      `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchPredictRequest) Reset() {
+	*x = BatchPredictRequest{}
+	mi := &file_predict_v1_predict_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchPredictRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPredictRequest) ProtoMessage() {}
+
+func (x *BatchPredictRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPredictRequest.ProtoReflect.Descriptor instead.
+func (*BatchPredictRequest) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchPredictRequest) GetRequests() []*PredictRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// BatchPredictResponse is the outcome of one request of a batch.
+type BatchPredictResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the request in the batch.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*BatchPredictResponse_Prediction
+	//	*BatchPredictResponse_Error
+	Outcome       isBatchPredictResponse_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchPredictResponse) Reset() {
+	*x = BatchPredictResponse{}
+	mi := &file_predict_v1_predict_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchPredictResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPredictResponse) ProtoMessage() {}
+
+func (x *BatchPredictResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPredictResponse.ProtoReflect.Descriptor instead.
+func (*BatchPredictResponse) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchPredictResponse) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchPredictResponse) GetOutcome() isBatchPredictResponse_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *BatchPredictResponse) GetPrediction() *PredictResponse {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchPredictResponse_Prediction); ok {
+			return x.Prediction
+		}
+	}
+	return nil
+}
+
+func (x *BatchPredictResponse) GetError() *BatchError {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchPredictResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchPredictResponse_Outcome interface {
+	isBatchPredictResponse_Outcome()
+}
+
+type BatchPredictResponse_Prediction struct {
+	Prediction *PredictResponse `protobuf:"bytes,2,opt,name=prediction,proto3,oneof"`
+}
+
+type BatchPredictResponse_Error struct {
+	Error *BatchError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchPredictResponse_Prediction) isBatchPredictResponse_Outcome() {}
+
+func (*BatchPredictResponse_Error) isBatchPredictResponse_Outcome() {}
+
+// BatchError reports why one request of a batch failed.
+type BatchError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// gRPC status code name, e.g. "InvalidArgument".
+	Code          string        `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string        `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Details       []*FieldError `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchError) Reset() {
+	*x = BatchError{}
+	mi := &file_predict_v1_predict_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchError) ProtoMessage() {}
+
+func (x *BatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchError.ProtoReflect.Descriptor instead.
+func (*BatchError) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *BatchError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BatchError) GetDetails() []*FieldError {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type ListProjectsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Select a past dataset, by version or by the time it was live. At most
+	// one may be set.
+	DatasetVersion int32                  `protobuf:"varint,1,opt,name=dataset_version,json=datasetVersion,proto3" json:"dataset_version,omitempty"`
+	AsOf           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListProjectsRequest) Reset() {
+	*x = ListProjectsRequest{}
+	mi := &file_predict_v1_predict_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProjectsRequest) ProtoMessage() {}
+
+func (x *ListProjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListProjectsRequest) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListProjectsRequest) GetDatasetVersion() int32 {
+	if x != nil {
+		return x.DatasetVersion
+	}
+	return 0
+}
+
+func (x *ListProjectsRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type ListProjectsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Projects         []*Project             `protobuf:"bytes,1,rep,name=projects,proto3" json:"projects,omitempty"`
+	Total            int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Summary          *ProjectSummary        `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	DatasetVersion   int32                  `protobuf:"varint,4,opt,name=dataset_version,json=datasetVersion,proto3" json:"dataset_version,omitempty"`
+	DatasetCreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=dataset_created_at,json=datasetCreatedAt,proto3" json:"dataset_created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListProjectsResponse) Reset() {
+	*x = ListProjectsResponse{}
+	mi := &file_predict_v1_predict_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProjectsResponse) ProtoMessage() {}
+
+func (x *ListProjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListProjectsResponse) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListProjectsResponse) GetProjects() []*Project {
+	if x != nil {
+		return x.Projects
+	}
+	return nil
+}
+
+func (x *ListProjectsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListProjectsResponse) GetSummary() *ProjectSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *ListProjectsResponse) GetDatasetVersion() int32 {
+	if x != nil {
+		return x.DatasetVersion
+	}
+	return 0
+}
+
+func (x *ListProjectsResponse) GetDatasetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DatasetCreatedAt
+	}
+	return nil
+}
+
+// Project is a DePIN project specification.
+type Project struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type             string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	NodeType         string                 `protobuf:"bytes,3,opt,name=node_type,json=nodeType,proto3" json:"node_type,omitempty"`
+	CpuCoresMin      int32                  `protobuf:"varint,4,opt,name=cpu_cores_min,json=cpuCoresMin,proto3" json:"cpu_cores_min,omitempty"`
+	RamGbMin         int32                  `protobuf:"varint,5,opt,name=ram_gb_min,json=ramGbMin,proto3" json:"ram_gb_min,omitempty"`
+	RamGbRecommended int32                  `protobuf:"varint,6,opt,name=ram_gb_recommended,json=ramGbRecommended,proto3" json:"ram_gb_recommended,omitempty"`
+	StorageGbMin     int32                  `protobuf:"varint,7,opt,name=storage_gb_min,json=storageGbMin,proto3" json:"storage_gb_min,omitempty"`
+	StorageType      string                 `protobuf:"bytes,8,opt,name=storage_type,json=storageType,proto3" json:"storage_type,omitempty"`
+	GpuRequired      bool                   `protobuf:"varint,9,opt,name=gpu_required,json=gpuRequired,proto3" json:"gpu_required,omitempty"`
+	GpuVramGbMin     int32                  `protobuf:"varint,10,opt,name=gpu_vram_gb_min,json=gpuVramGbMin,proto3" json:"gpu_vram_gb_min,omitempty"`
+	NetworkMbpsMin   int32                  `protobuf:"varint,11,opt,name=network_mbps_min,json=networkMbpsMin,proto3" json:"network_mbps_min,omitempty"`
+	SupportedOs      string                 `protobuf:"bytes,12,opt,name=supported_os,json=supportedOs,proto3" json:"supported_os,omitempty"`
+	EstimatedCostMin int32                  `protobuf:"varint,13,opt,name=estimated_cost_min,json=estimatedCostMin,proto3" json:"estimated_cost_min,omitempty"`
+	EstimatedCostMax int32                  `protobuf:"varint,14,opt,name=estimated_cost_max,json=estimatedCostMax,proto3" json:"estimated_cost_max,omitempty"`
+	CostCategory     string                 `protobuf:"bytes,15,opt,name=cost_category,json=costCategory,proto3" json:"cost_category,omitempty"`
+	HomeFriendly     bool                   `protobuf:"varint,16,opt,name=home_friendly,json=homeFriendly,proto3" json:"home_friendly,omitempty"`
+	Description      string                 `protobuf:"bytes,17,opt,name=description,proto3" json:"description,omitempty"`
+	// Date the specifications were last reviewed, YYYY-MM-DD.
+	LastUpdated   string `protobuf:"bytes,18,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Project) Reset() {
+	*x = Project{}
+	mi := &file_predict_v1_predict_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Project) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Project) ProtoMessage() {}
+
+func (x *Project) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Project.ProtoReflect.Descriptor instead.
+func (*Project) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Project) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Project) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Project) GetNodeType() string {
+	if x != nil {
+		return x.NodeType
+	}
+	return ""
+}
+
+func (x *Project) GetCpuCoresMin() int32 {
+	if x != nil {
+		return x.CpuCoresMin
+	}
+	return 0
+}
+
+func (x *Project) GetRamGbMin() int32 {
+	if x != nil {
+		return x.RamGbMin
+	}
+	return 0
+}
+
+func (x *Project) GetRamGbRecommended() int32 {
+	if x != nil {
+		return x.RamGbRecommended
+	}
+	return 0
+}
+
+func (x *Project) GetStorageGbMin() int32 {
+	if x != nil {
+		return x.StorageGbMin
+	}
+	return 0
+}
+
+func (x *Project) GetStorageType() string {
+	if x != nil {
+		return x.StorageType
+	}
+	return ""
+}
+
+func (x *Project) GetGpuRequired() bool {
+	if x != nil {
+		return x.GpuRequired
+	}
+	return false
+}
+
+func (x *Project) GetGpuVramGbMin() int32 {
+	if x != nil {
+		return x.GpuVramGbMin
+	}
+	return 0
+}
+
+func (x *Project) GetNetworkMbpsMin() int32 {
+	if x != nil {
+		return x.NetworkMbpsMin
+	}
+	return 0
+}
+
+func (x *Project) GetSupportedOs() string {
+	if x != nil {
+		return x.SupportedOs
+	}
+	return ""
+}
+
+func (x *Project) GetEstimatedCostMin() int32 {
+	if x != nil {
+		return x.EstimatedCostMin
+	}
+	return 0
+}
+
+func (x *Project) GetEstimatedCostMax() int32 {
+	if x != nil {
+		return x.EstimatedCostMax
+	}
+	return 0
+}
+
+func (x *Project) GetCostCategory() string {
+	if x != nil {
+		return x.CostCategory
+	}
+	return ""
+}
+
+func (x *Project) GetHomeFriendly() bool {
+	if x != nil {
+		return x.HomeFriendly
+	}
+	return false
+}
+
+func (x *Project) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Project) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+type ProjectSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ByType         map[string]int32       `protobuf:"bytes,1,rep,name=by_type,json=byType,proto3" json:"by_type,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ByCostCategory map[string]int32       `protobuf:"bytes,2,rep,name=by_cost_category,json=byCostCategory,proto3" json:"by_cost_category,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	HomeFriendly   int32                  `protobuf:"varint,3,opt,name=home_friendly,json=homeFriendly,proto3" json:"home_friendly,omitempty"`
+	GpuRequired    int32                  `protobuf:"varint,4,opt,name=gpu_required,json=gpuRequired,proto3" json:"gpu_required,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProjectSummary) Reset() {
+	*x = ProjectSummary{}
+	mi := &file_predict_v1_predict_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectSummary) ProtoMessage() {}
+
+func (x *ProjectSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectSummary.ProtoReflect.Descriptor instead.
+func (*ProjectSummary) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProjectSummary) GetByType() map[string]int32 {
+	if x != nil {
+		return x.ByType
+	}
+	return nil
+}
+
+func (x *ProjectSummary) GetByCostCategory() map[string]int32 {
+	if x != nil {
+		return x.ByCostCategory
+	}
+	return nil
+}
+
+func (x *ProjectSummary) GetHomeFriendly() int32 {
+	if x != nil {
+		return x.HomeFriendly
+	}
+	return 0
+}
+
+func (x *ProjectSummary) GetGpuRequired() int32 {
+	if x != nil {
+		return x.GpuRequired
+	}
+	return 0
+}
+
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_predict_v1_predict_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{15}
+}
+
+type HealthResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "healthy", "degraded" or "draining".
+	Status         string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Version        string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	ProjectsLoaded int32                  `protobuf:"varint,3,opt,name=projects_loaded,json=projectsLoaded,proto3" json:"projects_loaded,omitempty"`
+	Uptime         *durationpb.Duration   `protobuf:"bytes,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_predict_v1_predict_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_v1_predict_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_predict_v1_predict_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HealthResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetProjectsLoaded() int32 {
+	if x != nil {
+		return x.ProjectsLoaded
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *HealthResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_predict_v1_predict_proto protoreflect.FileDescriptor
+
+const file_predict_v1_predict_proto_rawDesc = "" +
+	"\n" +
+	"\x18predict/v1/predict.proto\x12\n" +
+	"predict.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcd\x02\n" +
+	"\n" +
+	"SystemSpec\x12\x1b\n" +
+	"\tcpu_cores\x18\x01 \x01(\x05R\bcpuCores\x12\x15\n" +
+	"\x06ram_gb\x18\x02 \x01(\x05R\x05ramGb\x12\x1d\n" +
+	"\n" +
+	"storage_gb\x18\x03 \x01(\x05R\tstorageGb\x12\x17\n" +
+	"\ahas_ssd\x18\x04 \x01(\bR\x06hasSsd\x12\x17\n" +
+	"\ahas_gpu\x18\x05 \x01(\bR\x06hasGpu\x12\x1e\n" +
+	"\vgpu_vram_gb\x18\x06 \x01(\x05R\tgpuVramGb\x12!\n" +
+	"\fnetwork_mbps\x18\a \x01(\x05R\vnetworkMbps\x12\x0e\n" +
+	"\x02os\x18\b \x01(\tR\x02os\x12#\n" +
+	"\x04gpus\x18\t \x03(\v2\x0f.predict.v1.GPUR\x04gpus\x12B\n" +
+	"\x0fstorage_devices\x18\n" +
+	" \x03(\v2\x19.predict.v1.StorageDeviceR\x0estorageDevices\"4\n" +
+	"\x03GPU\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12\x17\n" +
+	"\avram_gb\x18\x02 \x01(\x05R\x06vramGb\"]\n" +
+	"\rStorageDevice\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1f\n" +
+	"\vcapacity_gb\x18\x02 \x01(\x05R\n" +
+	"capacityGb\x12\x17\n" +
+	"\afree_gb\x18\x03 \x01(\x05R\x06freeGb\"\xd1\x01\n" +
+	"\x0ePredictRequest\x12.\n" +
+	"\x06system\x18\x01 \x01(\v2\x16.predict.v1.SystemSpecR\x06system\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x1d\n" +
+	"\n" +
+	"plain_text\x18\x03 \x01(\bR\tplainText\x12'\n" +
+	"\x0fdataset_version\x18\x04 \x01(\x05R\x0edatasetVersion\x12/\n" +
+	"\x05as_of\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"\xd0\x04\n" +
+	"\x0fPredictResponse\x12P\n" +
+	"\x13compatible_projects\x18\x01 \x03(\v2\x1f.predict.v1.CompatibilityResultR\x12compatibleProjects\x12T\n" +
+	"\x15incompatible_projects\x18\x02 \x03(\v2\x1f.predict.v1.CompatibilityResultR\x14incompatibleProjects\x127\n" +
+	"\asummary\x18\x03 \x01(\v2\x1d.predict.v1.PredictionSummaryR\asummary\x12(\n" +
+	"\x0frecommendations\x18\x04 \x03(\tR\x0frecommendations\x12\x16\n" +
+	"\x06locale\x18\x05 \x01(\tR\x06locale\x12=\n" +
+	"\x0einput_warnings\x18\x06 \x03(\v2\x16.predict.v1.FieldErrorR\rinputWarnings\x12'\n" +
+	"\x0fdataset_version\x18\a \x01(\x05R\x0edatasetVersion\x12H\n" +
+	"\x12dataset_created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x10datasetCreatedAt\x12)\n" +
+	"\x10scoring_strategy\x18\t \x01(\tR\x0fscoringStrategy\x12=\n" +
+	"\fgenerated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vgeneratedAt\"\xf7\x02\n" +
+	"\x13CompatibilityResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"compatible\x18\x02 \x01(\bR\n" +
+	"compatible\x12/\n" +
+	"\x13compatibility_score\x18\x03 \x01(\x01R\x12compatibilityScore\x12-\n" +
+	"\x12performance_rating\x18\x04 \x01(\tR\x11performanceRating\x12%\n" +
+	"\x0eestimated_cost\x18\x05 \x01(\tR\restimatedCost\x121\n" +
+	"\x14missing_requirements\x18\x06 \x03(\tR\x13missingRequirements\x12#\n" +
+	"\rmissing_codes\x18\a \x03(\tR\fmissingCodes\x121\n" +
+	"\x14recommended_upgrades\x18\b \x03(\tR\x13recommendedUpgrades\x12\x1a\n" +
+	"\bwarnings\x18\t \x03(\tR\bwarnings\"\x8d\x02\n" +
+	"\x11PredictionSummary\x12%\n" +
+	"\x0etotal_projects\x18\x01 \x01(\x05R\rtotalProjects\x12)\n" +
+	"\x10compatible_count\x18\x02 \x01(\x05R\x0fcompatibleCount\x12-\n" +
+	"\x12incompatible_count\x18\x03 \x01(\x05R\x11incompatibleCount\x12-\n" +
+	"\x12compatibility_rate\x18\x04 \x01(\x01R\x11compatibilityRate\x12#\n" +
+	"\raverage_score\x18\x05 \x01(\x01R\faverageScore\x12#\n" +
+	"\rsystem_rating\x18\x06 \x01(\tR\fsystemRating\"P\n" +
+	"\n" +
+	"FieldError\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x12\n" +
+	"\x04rule\x18\x02 \x01(\tR\x04rule\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"M\n" +
+	"\x13BatchPredictRequest\x126\n" +
+	"\brequests\x18\x01 \x03(\v2\x1a.predict.v1.PredictRequestR\brequests\"\xa6\x01\n" +
+	"\x14BatchPredictResponse\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12=\n" +
+	"\n" +
+	"prediction\x18\x02 \x01(\v2\x1b.predict.v1.PredictResponseH\x00R\n" +
+	"prediction\x12.\n" +
+	"\x05error\x18\x03 \x01(\v2\x16.predict.v1.BatchErrorH\x00R\x05errorB\t\n" +
+	"\aoutcome\"l\n" +
+	"\n" +
+	"BatchError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\adetails\x18\x03 \x03(\v2\x16.predict.v1.FieldErrorR\adetails\"o\n" +
+	"\x13ListProjectsRequest\x12'\n" +
+	"\x0fdataset_version\x18\x01 \x01(\x05R\x0edatasetVersion\x12/\n" +
+	"\x05as_of\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"\x86\x02\n" +
+	"\x14ListProjectsResponse\x12/\n" +
+	"\bprojects\x18\x01 \x03(\v2\x13.predict.v1.ProjectR\bprojects\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x124\n" +
+	"\asummary\x18\x03 \x01(\v2\x1a.predict.v1.ProjectSummaryR\asummary\x12'\n" +
+	"\x0fdataset_version\x18\x04 \x01(\x05R\x0edatasetVersion\x12H\n" +
+	"\x12dataset_created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x10datasetCreatedAt\"\x89\x05\n" +
+	"\aProject\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1b\n" +
+	"\tnode_type\x18\x03 \x01(\tR\bnodeType\x12\"\n" +
+	"\rcpu_cores_min\x18\x04 \x01(\x05R\vcpuCoresMin\x12\x1c\n" +
+	"\n" +
+	"ram_gb_min\x18\x05 \x01(\x05R\bramGbMin\x12,\n" +
+	"\x12ram_gb_recommended\x18\x06 \x01(\x05R\x10ramGbRecommended\x12$\n" +
+	"\x0estorage_gb_min\x18\a \x01(\x05R\fstorageGbMin\x12!\n" +
+	"\fstorage_type\x18\b \x01(\tR\vstorageType\x12!\n" +
+	"\fgpu_required\x18\t \x01(\bR\vgpuRequired\x12%\n" +
+	"\x0fgpu_vram_gb_min\x18\n" +
+	" \x01(\x05R\fgpuVramGbMin\x12(\n" +
+	"\x10network_mbps_min\x18\v \x01(\x05R\x0enetworkMbpsMin\x12!\n" +
+	"\fsupported_os\x18\f \x01(\tR\vsupportedOs\x12,\n" +
+	"\x12estimated_cost_min\x18\r \x01(\x05R\x10estimatedCostMin\x12,\n" +
+	"\x12estimated_cost_max\x18\x0e \x01(\x05R\x10estimatedCostMax\x12#\n" +
+	"\rcost_category\x18\x0f \x01(\tR\fcostCategory\x12#\n" +
+	"\rhome_friendly\x18\x10 \x01(\bR\fhomeFriendly\x12 \n" +
+	"\vdescription\x18\x11 \x01(\tR\vdescription\x12!\n" +
+	"\flast_updated\x18\x12 \x01(\tR\vlastUpdated\"\xf1\x02\n" +
+	"\x0eProjectSummary\x12?\n" +
+	"\aby_type\x18\x01 \x03(\v2&.predict.v1.ProjectSummary.ByTypeEntryR\x06byType\x12X\n" +
+	"\x10by_cost_category\x18\x02 \x03(\v2..predict.v1.ProjectSummary.ByCostCategoryEntryR\x0ebyCostCategory\x12#\n" +
+	"\rhome_friendly\x18\x03 \x01(\x05R\fhomeFriendly\x12!\n" +
+	"\fgpu_required\x18\x04 \x01(\x05R\vgpuRequired\x1a9\n" +
+	"\vByTypeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aA\n" +
+	"\x13ByCostCategoryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x0f\n" +
+	"\rHealthRequest\"\xd8\x01\n" +
+	"\x0eHealthResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12'\n" +
+	"\x0fprojects_loaded\x18\x03 \x01(\x05R\x0eprojectsLoaded\x121\n" +
+	"\x06uptime\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x06uptime\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp2\xbd\x02\n" +
+	"\x0ePredictService\x12B\n" +
+	"\aPredict\x12\x1a.predict.v1.PredictRequest\x1a\x1b.predict.v1.PredictResponse\x12S\n" +
+	"\fBatchPredict\x12\x1f.predict.v1.BatchPredictRequest\x1a .predict.v1.BatchPredictResponse0\x01\x12Q\n" +
+	"\fListProjects\x12\x1f.predict.v1.ListProjectsRequest\x1a .predict.v1.ListProjectsResponse\x12?\n" +
+	"\x06Health\x12\x19.predict.v1.HealthRequest\x1a\x1a.predict.v1.HealthResponseB>Z<github.com/simoncrean/api-predict/proto/predict/v1;predictv1b\x06proto3"
+
+var (
+	file_predict_v1_predict_proto_rawDescOnce sync.Once
+	file_predict_v1_predict_proto_rawDescData []byte
+)
+
+func file_predict_v1_predict_proto_rawDescGZIP() []byte {
+	file_predict_v1_predict_proto_rawDescOnce.Do(func() {
+		file_predict_v1_predict_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_predict_v1_predict_proto_rawDesc), len(file_predict_v1_predict_proto_rawDesc)))
+	})
+	return file_predict_v1_predict_proto_rawDescData
+}
+
+var file_predict_v1_predict_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_predict_v1_predict_proto_goTypes = []any{
+	(*SystemSpec)(nil),            // 0: predict.v1.SystemSpec
+	(*GPU)(nil),                   // 1: predict.v1.GPU
+	(*StorageDevice)(nil),         // 2: predict.v1.StorageDevice
+	(*PredictRequest)(nil),        // 3: predict.v1.PredictRequest
+	(*PredictResponse)(nil),       // 4: predict.v1.PredictResponse
+	(*CompatibilityResult)(nil),   // 5: predict.v1.CompatibilityResult
+	(*PredictionSummary)(nil),     // 6: predict.v1.PredictionSummary
+	(*FieldError)(nil),            // 7: predict.v1.FieldError
+	(*BatchPredictRequest)(nil),   // 8: predict.v1.BatchPredictRequest
+	(*BatchPredictResponse)(nil),  // 9: predict.v1.BatchPredictResponse
+	(*BatchError)(nil),            // 10: predict.v1.BatchError
+	(*ListProjectsRequest)(nil),   // 11: predict.v1.ListProjectsRequest
+	(*ListProjectsResponse)(nil),  // 12: predict.v1.ListProjectsResponse
+	(*Project)(nil),               // 13: predict.v1.Project
+	(*ProjectSummary)(nil),        // 14: predict.v1.ProjectSummary
+	(*HealthRequest)(nil),         // 15: predict.v1.HealthRequest
+	(*HealthResponse)(nil),        // 16: predict.v1.HealthResponse
+	nil,                           // 17: predict.v1.ProjectSummary.ByTypeEntry
+	nil,                           // 18: predict.v1.ProjectSummary.ByCostCategoryEntry
+	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 20: google.protobuf.Duration
+}
+var file_predict_v1_predict_proto_depIdxs = []int32{
+	1,  // 0: predict.v1.SystemSpec.gpus:type_name -> predict.v1.GPU
+	2,  // 1: predict.v1.SystemSpec.storage_devices:type_name -> predict.v1.StorageDevice
+	0,  // 2: predict.v1.PredictRequest.system:type_name -> predict.v1.SystemSpec
+	19, // 3: predict.v1.PredictRequest.as_of:type_name -> google.protobuf.Timestamp
+	5,  // 4: predict.v1.PredictResponse.compatible_projects:type_name -> predict.v1.CompatibilityResult
+	5,  // 5: predict.v1.PredictResponse.incompatible_projects:type_name -> predict.v1.CompatibilityResult
+	6,  // 6: predict.v1.PredictResponse.summary:type_name -> predict.v1.PredictionSummary
+	7,  // 7: predict.v1.PredictResponse.input_warnings:type_name -> predict.v1.FieldError
+	19, // 8: predict.v1.PredictResponse.dataset_created_at:type_name -> google.protobuf.Timestamp
+	19, // 9: predict.v1.PredictResponse.generated_at:type_name -> google.protobuf.Timestamp
+	3,  // 10: predict.v1.BatchPredictRequest.requests:type_name -> predict.v1.PredictRequest
+	4,  // 11: predict.v1.BatchPredictResponse.prediction:type_name -> predict.v1.PredictResponse
+	10, // 12: predict.v1.BatchPredictResponse.error:type_name -> predict.v1.BatchError
+	7,  // 13: predict.v1.BatchError.details:type_name -> predict.v1.FieldError
+	19, // 14: predict.v1.ListProjectsRequest.as_of:type_name -> google.protobuf.Timestamp
+	13, // 15: predict.v1.ListProjectsResponse.projects:type_name -> predict.v1.Project
+	14, // 16: predict.v1.ListProjectsResponse.summary:type_name -> predict.v1.ProjectSummary
+	19, // 17: predict.v1.ListProjectsResponse.dataset_created_at:type_name -> google.protobuf.Timestamp
+	17, // 18: predict.v1.ProjectSummary.by_type:type_name -> predict.v1.ProjectSummary.ByTypeEntry
+	18, // 19: predict.v1.ProjectSummary.by_cost_category:type_name -> predict.v1.ProjectSummary.ByCostCategoryEntry
+	20, // 20: predict.v1.HealthResponse.uptime:type_name -> google.protobuf.Duration
+	19, // 21: predict.v1.HealthResponse.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 22: predict.v1.PredictService.Predict:input_type -> predict.v1.PredictRequest
+	8,  // 23: predict.v1.PredictService.BatchPredict:input_type -> predict.v1.BatchPredictRequest
+	11, // 24: predict.v1.PredictService.ListProjects:input_type -> predict.v1.ListProjectsRequest
+	15, // 25: predict.v1.PredictService.Health:input_type -> predict.v1.HealthRequest
+	4,  // 26: predict.v1.PredictService.Predict:output_type -> predict.v1.PredictResponse
+	9,  // 27: predict.v1.PredictService.BatchPredict:output_type -> predict.v1.BatchPredictResponse
+	12, // 28: predict.v1.PredictService.ListProjects:output_type -> predict.v1.ListProjectsResponse
+	16, // 29: predict.v1.PredictService.Health:output_type -> predict.v1.HealthResponse
+	26, // [26:30] is the sub-list for method output_type
+	22, // [22:26] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_predict_v1_predict_proto_init() }
+func file_predict_v1_predict_proto_init() {
+	if File_predict_v1_predict_proto != nil {
+		return
+	}
+	file_predict_v1_predict_proto_msgTypes[9].OneofWrappers = []any{
+		(*BatchPredictResponse_Prediction)(nil),
+		(*BatchPredictResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_predict_v1_predict_proto_rawDesc), len(file_predict_v1_predict_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_predict_v1_predict_proto_goTypes,
+		DependencyIndexes: file_predict_v1_predict_proto_depIdxs,
+		MessageInfos:      file_predict_v1_predict_proto_msgTypes,
+	}.Build()
+	File_predict_v1_predict_proto = out.File
+	file_predict_v1_predict_proto_goTypes = nil
+	file_predict_v1_predict_proto_depIdxs = nil
+}
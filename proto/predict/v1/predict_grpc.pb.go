@@ -0,0 +1,269 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: predict/v1/predict.proto
+
+// Package predict.v1 is the gRPC interface of the DePIN Compatibility API. It
+// mirrors the REST endpoints for predictions, projects and health and is
+// served from the same dataset.
+
+package predictv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PredictService_Predict_FullMethodName      = "/predict.v1.PredictService/Predict"
+	PredictService_BatchPredict_FullMethodName = "/predict.v1.PredictService/BatchPredict"
+	PredictService_ListProjects_FullMethodName = "/predict.v1.PredictService/ListProjects"
+	PredictService_Health_FullMethodName       = "/predict.v1.PredictService/Health"
+)
+
+// PredictServiceClient is the client API for PredictService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PredictService predicts which DePIN projects a system can run.
+//
+// Calls authenticate with an API key in the "x-api-key" metadata entry or as
+// "authorization: Bearer <key>". Predict and BatchPredict need the predict
+// scope, ListProjects the read scope.
+type PredictServiceClient interface {
+	// Predict checks a system against every project, like POST /api/v1/predict.
+	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error)
+	// BatchPredict checks up to 100 systems and streams one result per
+	// request, in request order. An invalid request yields an error result
+	// without ending the stream.
+	BatchPredict(ctx context.Context, in *BatchPredictRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchPredictResponse], error)
+	// ListProjects lists the projects of the live or a past dataset version,
+	// like GET /api/v1/projects.
+	ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error)
+	// Health reports service health, like GET /api/v1/health.
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+}
+
+type predictServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPredictServiceClient(cc grpc.ClientConnInterface) PredictServiceClient {
+	return &predictServiceClient{cc}
+}
+
+func (c *predictServiceClient) Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PredictResponse)
+	err := c.cc.Invoke(ctx, PredictService_Predict_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *predictServiceClient) BatchPredict(ctx context.Context, in *BatchPredictRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchPredictResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PredictService_ServiceDesc.Streams[0], PredictService_BatchPredict_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchPredictRequest, BatchPredictResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PredictService_BatchPredictClient = grpc.ServerStreamingClient[BatchPredictResponse]
+
+func (c *predictServiceClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProjectsResponse)
+	err := c.cc.Invoke(ctx, PredictService_ListProjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *predictServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, PredictService_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PredictServiceServer is the server API for PredictService service.
+// All implementations must embed UnimplementedPredictServiceServer
+// for forward compatibility.
+//
+// PredictService predicts which DePIN projects a system can run.
+//
+// Calls authenticate with an API key in the "x-api-key" metadata entry or as
+// "authorization: Bearer <key>". Predict and BatchPredict need the predict
+// scope, ListProjects the read scope.
+type PredictServiceServer interface {
+	// Predict checks a system against every project, like POST /api/v1/predict.
+	Predict(context.Context, *PredictRequest) (*PredictResponse, error)
+	// BatchPredict checks up to 100 systems and streams one result per
+	// request, in request order. An invalid request yields an error result
+	// without ending the stream.
+	BatchPredict(*BatchPredictRequest, grpc.ServerStreamingServer[BatchPredictResponse]) error
+	// ListProjects lists the projects of the live or a past dataset version,
+	// like GET /api/v1/projects.
+	ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error)
+	// Health reports service health, like GET /api/v1/health.
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	mustEmbedUnimplementedPredictServiceServer()
+}
+
+// UnimplementedPredictServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPredictServiceServer struct{}
+
+func (UnimplementedPredictServiceServer) Predict(context.Context, *PredictRequest) (*PredictResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Predict not implemented")
+}
+func (UnimplementedPredictServiceServer) BatchPredict(*BatchPredictRequest, grpc.ServerStreamingServer[BatchPredictResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchPredict not implemented")
+}
+func (UnimplementedPredictServiceServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedPredictServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedPredictServiceServer) mustEmbedUnimplementedPredictServiceServer() {}
+func (UnimplementedPredictServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafePredictServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PredictServiceServer will
+// result in compilation errors.
+type UnsafePredictServiceServer interface {
+	mustEmbedUnimplementedPredictServiceServer()
+}
+
+func RegisterPredictServiceServer(s grpc.ServiceRegistrar, srv PredictServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPredictServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PredictService_ServiceDesc, srv)
+}
+
+func _PredictService_Predict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PredictRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PredictServiceServer).Predict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PredictService_Predict_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PredictServiceServer).Predict(ctx, req.(*PredictRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PredictService_BatchPredict_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchPredictRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PredictServiceServer).BatchPredict(m, &grpc.GenericServerStream[BatchPredictRequest, BatchPredictResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PredictService_BatchPredictServer = grpc.ServerStreamingServer[BatchPredictResponse]
+
+func _PredictService_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PredictServiceServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PredictService_ListProjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PredictServiceServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PredictService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PredictServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PredictService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PredictServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PredictService_ServiceDesc is the grpc.ServiceDesc for PredictService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PredictService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "predict.v1.PredictService",
+	HandlerType: (*PredictServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Predict",
+			Handler:    _PredictService_Predict_Handler,
+		},
+		{
+			MethodName: "ListProjects",
+			Handler:    _PredictService_ListProjects_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _PredictService_Health_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchPredict",
+			Handler:       _PredictService_BatchPredict_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "predict/v1/predict.proto",
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"maps"
+
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// publishDatasetChanges returns a dataset listener that publishes CSV imports
+// and project summary changes to the event stream. Admin edits are published
+// with their change record by the project editor instead.
+func publishDatasetChanges(broker *events.Broker) service.DatasetListener {
+	return func(previous, current models.DatasetSnapshot) {
+		if current.Version.Source != service.DatasetSourceAdmin {
+			broker.Publish(models.StreamEventDatasetReloaded, models.DatasetEvent{
+				Version:         current.Version.Version,
+				PreviousVersion: previous.Version.Version,
+				Source:          current.Version.Source,
+				Projects:        current.Version.Projects,
+				Checksum:        current.Version.Checksum,
+				CreatedAt:       current.Version.CreatedAt,
+			})
+		}
+
+		before := service.SummarizeProjects(previous.Projects)
+		after := service.SummarizeProjects(current.Projects)
+		if !summariesEqual(before, after) {
+			broker.Publish(models.StreamEventSummaryChanged, models.SummaryEvent{
+				DatasetVersion: current.Version.Version,
+				Summary:        after,
+				Previous:       before,
+			})
+		}
+	}
+}
+
+// summariesEqual reports whether two project summaries have the same counts
+func summariesEqual(a, b models.ProjectSummary) bool {
+	return a.HomeFriendly == b.HomeFriendly &&
+		a.GPURequired == b.GPURequired &&
+		maps.Equal(a.ByType, b.ByType) &&
+		maps.Equal(a.ByCostCategory, b.ByCostCategory)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/simoncrean/api-predict/internal/events"
+	"github.com/simoncrean/api-predict/internal/models"
+	"github.com/simoncrean/api-predict/internal/service"
+)
+
+// snapshot returns a dataset version from source with one project per type
+func snapshot(version int, source string, types ...string) models.DatasetSnapshot {
+	projects := make([]models.DePINProject, len(types))
+	for i, projectType := range types {
+		projects[i] = models.DePINProject{Name: projectType, Type: projectType}
+	}
+	return models.DatasetSnapshot{
+		Version:  models.DatasetVersion{Version: version, Source: source, Projects: len(projects)},
+		Projects: projects,
+	}
+}
+
+func TestPublishDatasetChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		current models.DatasetSnapshot
+		want    []string
+	}{
+		{"reload with new summary", snapshot(2, service.DatasetSourceReload, "Storage", "Compute"), []string{models.StreamEventDatasetReloaded, models.StreamEventSummaryChanged}},
+		{"reload with same summary", snapshot(2, service.DatasetSourceReload, "Storage"), []string{models.StreamEventDatasetReloaded}},
+		{"admin edit with new summary", snapshot(2, service.DatasetSourceAdmin, "Compute"), []string{models.StreamEventSummaryChanged}},
+		{"admin edit with same summary", snapshot(2, service.DatasetSourceAdmin, "Storage"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := events.NewBroker(events.DefaultHistorySize)
+			_, _, stream, cancel := broker.Subscribe("")
+			publishDatasetChanges(broker)(snapshot(1, service.DatasetSourceInitial, "Storage"), tt.current)
+			cancel()
+
+			var published []events.Event
+			for event := range stream {
+				published = append(published, event)
+			}
+			types := make([]string, len(published))
+			for i, event := range published {
+				types[i] = event.Type
+			}
+			if !slices.Equal(types, tt.want) {
+				t.Fatalf("published %v, want %v", types, tt.want)
+			}
+
+			for _, event := range published {
+				switch data := event.Data.(type) {
+				case models.DatasetEvent:
+					if data.Version != 2 || data.PreviousVersion != 1 || data.Projects != len(tt.current.Projects) {
+						t.Errorf("dataset event = %+v", data)
+					}
+				case models.SummaryEvent:
+					if data.DatasetVersion != 2 || data.Previous.ByType["Storage"] != 1 || data.Summary.ByType["Compute"] != 1 {
+						t.Errorf("summary event = %+v", data)
+					}
+				}
+			}
+		})
+	}
+}